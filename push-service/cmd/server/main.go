@@ -19,31 +19,55 @@
 // @BasePath  /
 
 // @schemes   http https
+
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+// @description Scoped API key. Also accepted as "Authorization: Bearer <key>".
+
+// @securityDefinitions.apikey UserToken
+// @in header
+// @name Authorization
+// @description End-user JWT as "Bearer <token>", accepted on device routes when auth.jwt is enabled.
 package main
 
 import (
 	"context"
+	"errors"
 	"log"
+	"net"
 	"net/http"
+	"net/http/pprof"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"push-service/internal/config"
+	"push-service/internal/grpcserver"
 	"push-service/internal/handlers"
+	"push-service/internal/health"
+	"push-service/internal/metrics"
+	"push-service/internal/middleware"
+	"push-service/internal/models"
 	"push-service/internal/platform/fcm"
 	"push-service/internal/queue"
+	"push-service/internal/ratelimit"
 	"push-service/internal/repository"
 	"push-service/internal/service"
 	"push-service/pkg/database"
 	"push-service/pkg/logger"
 	"push-service/pkg/rabbitmq"
+	"push-service/pkg/redis"
+	"push-service/pkg/tracing"
 
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
 )
 
 func main() {
@@ -58,6 +82,19 @@ func main() {
 		log.Fatalf("Failed to initialize logger: %v", err)
 	}
 	defer logger.L().Sync()
+	if err := logger.SetRedaction(logger.RedactionPolicy{
+		Tokens:   cfg.Log.Redaction.Tokens,
+		Content:  cfg.Log.Redaction.Content,
+		DataKeys: cfg.Log.Redaction.DataKeys,
+	}); err != nil {
+		logger.L().Fatal("Invalid log redaction policy", zap.Error(err))
+	}
+
+	// Initialize tracing
+	shutdownTracing, err := tracing.Init(context.Background(), &cfg.Tracing)
+	if err != nil {
+		logger.L().Fatal("Failed to initialize tracing", zap.Error(err))
+	}
 
 	// Set Gin mode
 	gin.SetMode(cfg.Server.Mode)
@@ -76,14 +113,42 @@ func main() {
 	}
 	defer rabbitmqClient.Close()
 
+	// Initialize Redis. It is optional: without it rate limits are kept per
+	// replica. If it is down, the rate limiter keeps probing it.
+	var redisClient *redis.RedisClient
+	if cfg.Redis.Enabled {
+		redisClient, err = redis.NewRedisClient(&cfg.Redis)
+		if err != nil {
+			logger.L().Warn("Failed to connect to Redis, using in-process rate limits until it is reachable", zap.Error(err))
+		}
+		if redisClient != nil {
+			defer redisClient.Close()
+		}
+	}
+
 	// Initialize FCM client
 	fcmClient, err := fcm.NewFCMClient(&cfg.FCM)
 	if err != nil {
 		logger.L().Fatal("Failed to initialize FCM client", zap.Error(err))
 	}
 
+	// Initialize stale device cleanup job
+	cleanupService := service.NewCleanupService(repository.NewCleanupRepository(db.Pool), &cfg.Cleanup)
+
+	// Initialize the push queue and template service shared by the APIs and
+	// the worker, so a template change clears the one cache every send reads
+	pushQueue, err := queue.NewPushQueue(rabbitmqClient, &cfg.Queue)
+	if err != nil {
+		logger.L().Fatal("Failed to initialize push queue", zap.Error(err))
+	}
+	templateService := service.NewTemplateService(repository.NewTemplateRepository(db.Pool), repository.NewUserAttributesRepository(db.Pool), &cfg.Templates)
+
+	// Initialize readiness and liveness checks
+	consumers := health.NewConsumers(queue.PushQueueName, queue.GatewayPushQueueName)
+	readiness, liveness := setupHealthChecks(db, rabbitmqClient, redisClient, fcmClient, consumers, cfg)
+
 	// Create Gin router
-	router := setupRouter(db, rabbitmqClient, fcmClient, cfg)
+	router := setupRouter(db, pushQueue, templateService, redisClient, fcmClient, cleanupService, readiness, liveness, cfg)
 
 	// Create server
 	srv := &http.Server{
@@ -99,8 +164,29 @@ func main() {
 		}
 	}()
 
+	// Start gRPC server
+	var grpcServer *grpc.Server
+	if cfg.GRPC.Enabled {
+		grpcServer = setupGRPCServer(db, pushQueue, templateService, fcmClient, cfg)
+		listener, err := net.Listen("tcp", ":"+cfg.GRPC.Port)
+		if err != nil {
+			logger.L().Fatal("Failed to listen for gRPC", zap.Error(err))
+		}
+		go func() {
+			logger.L().Info("Starting gRPC server", zap.String("port", cfg.GRPC.Port))
+			if err := grpcServer.Serve(listener); err != nil {
+				logger.L().Fatal("Failed to start gRPC server", zap.Error(err))
+			}
+		}()
+	}
+
 	// Start queue worker
-	go startPushWorker(rabbitmqClient, fcmClient, db, cfg)
+	go startPushWorker(pushQueue, templateService, fcmClient, db, consumers, cfg)
+
+	// Start cleanup job
+	cleanupCtx, stopCleanup := context.WithCancel(context.Background())
+	defer stopCleanup()
+	go cleanupService.Start(cleanupCtx)
 
 	// Wait for interrupt signal
 	quit := make(chan os.Signal, 1)
@@ -116,33 +202,89 @@ func main() {
 	if err := srv.Shutdown(ctx); err != nil {
 		logger.L().Fatal("Server forced to shutdown", zap.Error(err))
 	}
+	if grpcServer != nil {
+		grpcServer.GracefulStop()
+	}
+	if err := shutdownTracing(ctx); err != nil {
+		logger.L().Error("Failed to flush traces", zap.Error(err))
+	}
 
 	logger.L().Info("Server exited properly")
 }
 
-func setupRouter(db *database.DB, rabbitmqClient *rabbitmq.RabbitMQClient, fcmClient fcm.FCMClient, cfg *config.Config) *gin.Engine {
+func setupRouter(db *database.DB, pushQueue *queue.PushQueue, templateService service.TemplateService, redisClient *redis.RedisClient, fcmClient fcm.FCMClient, cleanupService service.CleanupService, readiness, liveness *health.Registry, cfg *config.Config) *gin.Engine {
 	router := gin.New()
 
 	// Middleware
 	router.Use(gin.Recovery())
+	router.Use(otelgin.Middleware(cfg.Tracing.ServiceName, otelgin.WithGinFilter(tracedRoute)))
+	router.Use(middleware.AssignRequestID())
 	router.Use(loggerMiddleware())
+	if cfg.Metrics.Enabled {
+		router.Use(middleware.Metrics())
+	}
 
 	// Initialize repositories and services
 	deviceRepo := repository.NewDeviceRepository(db.Pool)
-	pushQueue, err := queue.NewPushQueue(rabbitmqClient, &cfg.Queue)
-	if err != nil {
-		logger.L().Fatal("Failed to initialize push queue", zap.Error(err))
-	}
+	userAttributesRepo := repository.NewUserAttributesRepository(db.Pool)
+	apiKeyRepo := repository.NewAPIKeyRepository(db.Pool)
+	idempotencyRepo := repository.NewIdempotencyRepository(db.Pool)
+	notificationRepo := repository.NewNotificationRepository(db.Pool)
+	preferenceRepo := repository.NewPreferenceRepository(db.Pool)
+	quietHoursRepo := repository.NewQuietHoursRepository(db.Pool)
+	deferredPushRepo := repository.NewDeferredPushRepository(db.Pool)
+	userDataRepo := repository.NewUserDataRepository(db.Pool, userAttributesRepo, preferenceRepo, quietHoursRepo, deferredPushRepo)
 
 	deviceService := service.NewDeviceService(deviceRepo, fcmClient, cfg)
-	pushService := service.NewPushService(deviceRepo, fcmClient, pushQueue, cfg)
+	quietHoursService := service.NewQuietHoursService(quietHoursRepo, deferredPushRepo, pushQueue, &cfg.QuietHours)
+	pushService := service.NewPushService(deviceRepo, idempotencyRepo, notificationRepo, preferenceRepo, templateService, quietHoursService, fcmClient, pushQueue, cfg)
+	userService := service.NewUserService(userDataRepo, userAttributesRepo, preferenceRepo, pushQueue)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, &cfg.Auth)
+	idempotencyService := service.NewIdempotencyService(idempotencyRepo, &cfg.Idempotency)
+	var userTokens *middleware.UserTokenVerifier
+	if cfg.Auth.JWT.Enabled {
+		var err error
+		userTokens, err = middleware.NewUserTokenVerifier(&cfg.Auth.JWT)
+		if err != nil {
+			logger.L().Fatal("Failed to initialize user token verifier", zap.Error(err))
+		}
+	}
+	auth := middleware.NewAuthenticator(apiKeyService, userTokens, &cfg.Auth)
+
+	// Rate limits are shared through Redis when available, per replica otherwise
+	limiter := ratelimit.NewLocalLimiter()
+	if redisClient != nil {
+		limiter = ratelimit.NewFallbackLimiter(ratelimit.NewRedisLimiter(redisClient.Client), limiter)
+	}
+	rateLimiter := middleware.NewRateLimiter(limiter, &cfg.RateLimit)
+	limit := rateLimiter.Limit()
+	idempotent := middleware.NewIdempotency(idempotencyService).Handle()
 
 	deviceHandler := handlers.NewDeviceHandler(deviceService)
 	pushHandler := handlers.NewPushHandler(pushService)
+	userHandler := handlers.NewUserHandler(userService)
+	templateHandler := handlers.NewTemplateHandler(templateService)
+	quietHoursHandler := handlers.NewQuietHoursHandler(quietHoursService)
+	credentials := map[string]handlers.CredentialReloader{"fcm": fcmClient.ReloadCredentials}
+	if userTokens != nil {
+		credentials["jwks"] = func(context.Context) error { return userTokens.Reload() }
+	}
+	adminHandler := handlers.NewAdminHandler(cleanupService, cfg, credentials)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyService)
 
 	// Health check
-	router.GET("/health", handlers.HealthCheck)
-	router.GET("/ready", handlers.ReadinessCheck(db))
+	router.GET("/health", handlers.HealthCheck(liveness))
+	router.GET("/live", handlers.HealthCheck(liveness))
+	router.GET("/ready", handlers.ReadinessCheck(readiness))
+
+	// Prometheus metrics
+	if cfg.Metrics.Enabled {
+		metrics.Registry.MustRegister(
+			metrics.NewDBPoolCollector(db.Pool),
+			metrics.NewQueueDepthCollector(pushQueue.GetQueueStats),
+		)
+		router.GET("/metrics", gin.WrapH(metrics.Handler()))
+	}
 
 	// Swagger documentation
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
@@ -150,29 +292,128 @@ func setupRouter(db *database.DB, rabbitmqClient *rabbitmq.RabbitMQClient, fcmCl
 	// API v1 routes
 	v1 := router.Group("/v1")
 	{
-		v1.POST("/devices", deviceHandler.RegisterDevice)
-		v1.DELETE("/devices/:token", deviceHandler.UnregisterDevice)
-		v1.GET("/devices", deviceHandler.GetUserDevices)
-		v1.POST("/push/send", pushHandler.SendPush)
-		v1.POST("/push/send-bulk", pushHandler.SendBulkPush)
-		v1.GET("/queue/stats", pushHandler.GetQueueStats)
-		v1.POST("/push/test-direct", pushHandler.TestDirectSend)
+		v1.POST("/devices", auth.RequireScopeOrUser(models.ScopeDevicesWrite), limit, deviceHandler.RegisterDevice)
+		v1.POST("/devices/batch", auth.RequireScope(models.ScopeDevicesWrite), limit, deviceHandler.RegisterDevices)
+		v1.DELETE("/devices/batch", auth.RequireScope(models.ScopeDevicesWrite), limit, deviceHandler.UnregisterDevices)
+		v1.DELETE("/devices/:token", auth.RequireScopeOrUser(models.ScopeDevicesWrite), limit, deviceHandler.UnregisterDevice)
+		v1.GET("/devices", auth.RequireScopeOrUser(models.ScopeDevicesRead), limit, deviceHandler.GetUserDevices)
+		v1.PUT("/devices/:token/tags", auth.RequireScope(models.ScopeDevicesWrite), limit, deviceHandler.SetDeviceTags)
+		v1.DELETE("/users/:user_id/devices", auth.RequireScopeOrUser(models.ScopeDevicesWrite), limit, deviceHandler.UnregisterUserDevices)
+		v1.GET("/users/:user_id/attributes", auth.RequireScope(models.ScopeDevicesRead), limit, userHandler.GetUserAttributes)
+		v1.PUT("/users/:user_id/attributes", auth.RequireScope(models.ScopeDevicesWrite), limit, userHandler.SetUserAttributes)
+		v1.GET("/users/:user_id/preferences", auth.RequireScopeOrUser(models.ScopeDevicesRead), limit, userHandler.GetPreferences)
+		v1.PUT("/users/:user_id/preferences", auth.RequireScopeOrUser(models.ScopeDevicesWrite), limit, userHandler.SetPreferences)
+		v1.DELETE("/users/:user_id/preferences/:category", auth.RequireScopeOrUser(models.ScopeDevicesWrite), limit, userHandler.DeletePreference)
+		v1.GET("/users/:user_id/quiet-hours", auth.RequireScopeOrUser(models.ScopeDevicesRead), limit, quietHoursHandler.GetUserQuietHours)
+		v1.PUT("/users/:user_id/quiet-hours", auth.RequireScopeOrUser(models.ScopeDevicesWrite), limit, quietHoursHandler.SetUserQuietHours)
+		v1.DELETE("/users/:user_id/quiet-hours", auth.RequireScopeOrUser(models.ScopeDevicesWrite), limit, quietHoursHandler.DeleteUserQuietHours)
+		v1.POST("/push/send", auth.RequireScope(models.ScopePushSend), limit, rateLimiter.LimitTargetUser(), idempotent, pushHandler.SendPush)
+		v1.POST("/push/send-bulk", auth.RequireScope(models.ScopePushBulk), limit, idempotent, pushHandler.SendBulkPush)
+		v1.POST("/push/send-segment", auth.RequireScope(models.ScopePushBulk), limit, idempotent, pushHandler.SendSegmentPush)
+		v1.POST("/templates", auth.RequireScope(models.ScopeTemplatesWrite), limit, templateHandler.CreateTemplate)
+		v1.GET("/templates", auth.RequireScope(models.ScopeTemplatesRead), limit, templateHandler.ListTemplates)
+		v1.GET("/templates/:key", auth.RequireScope(models.ScopeTemplatesRead), limit, templateHandler.GetTemplate)
+		v1.GET("/templates/:key/versions", auth.RequireScope(models.ScopeTemplatesRead), limit, templateHandler.ListTemplateVersions)
+		v1.PUT("/templates/:key", auth.RequireScope(models.ScopeTemplatesWrite), limit, templateHandler.UpdateTemplate)
+		v1.DELETE("/templates/:key", auth.RequireScope(models.ScopeTemplatesWrite), limit, templateHandler.DeleteTemplate)
+		v1.POST("/templates/:key/preview", auth.RequireScope(models.ScopeTemplatesRead), limit, templateHandler.PreviewTemplate)
+	}
+
+	// Routes requiring the admin scope
+	adminOnly := v1.Group("", auth.RequireScope(models.ScopeAdmin), limit)
+	{
+		adminOnly.DELETE("/users/:user_id", userHandler.EraseUser)
+		adminOnly.GET("/users/:user_id/export", userHandler.ExportUser)
+		adminOnly.GET("/queue/stats", pushHandler.GetQueueStats)
+
+		admin := adminOnly.Group("/admin")
+		admin.GET("/devices", deviceHandler.SearchDevices)
+		admin.GET("/cleanup", adminHandler.GetCleanupStatus)
+		admin.POST("/cleanup/run", adminHandler.RunCleanup)
+		admin.POST("/keys", apiKeyHandler.CreateKey)
+		admin.GET("/keys", apiKeyHandler.ListKeys)
+		admin.POST("/keys/:id/rotate", apiKeyHandler.RotateKey)
+		admin.DELETE("/keys/:id", apiKeyHandler.RevokeKey)
+		admin.GET("/log-level", adminHandler.GetLogLevel)
+		admin.PUT("/log-level", adminHandler.SetLogLevel)
+		admin.DELETE("/log-level/:component", adminHandler.ClearLogLevel)
+		admin.GET("/config", adminHandler.GetConfig)
+		admin.POST("/credentials/reload", adminHandler.ReloadCredentials)
+		admin.GET("/apps/:app/quiet-hours", quietHoursHandler.GetAppQuietHours)
+		admin.PUT("/apps/:app/quiet-hours", quietHoursHandler.SetAppQuietHours)
+		admin.DELETE("/apps/:app/quiet-hours", quietHoursHandler.DeleteAppQuietHours)
+	}
+
+	// Profiling, only when enabled since profiles expose internals
+	if cfg.Admin.PprofEnabled {
+		debug := router.Group("/debug/pprof", auth.RequireScope(models.ScopeAdmin), limit)
+		debug.GET("/", gin.WrapF(pprof.Index))
+		debug.GET("/cmdline", gin.WrapF(pprof.Cmdline))
+		debug.GET("/profile", gin.WrapF(pprof.Profile))
+		debug.GET("/symbol", gin.WrapF(pprof.Symbol))
+		debug.POST("/symbol", gin.WrapF(pprof.Symbol))
+		debug.GET("/trace", gin.WrapF(pprof.Trace))
+		debug.GET("/:profile", gin.WrapF(pprof.Index))
 	}
 
 	return router
 }
 
-func startPushWorker(rabbitmqClient *rabbitmq.RabbitMQClient, fcmClient fcm.FCMClient, db *database.DB, cfg *config.Config) {
-	ctx, cancel := context.WithCancel(context.Background())
+func setupGRPCServer(db *database.DB, pushQueue *queue.PushQueue, templateService service.TemplateService, fcmClient fcm.FCMClient, cfg *config.Config) *grpc.Server {
+	// Initialize repositories and services for the gRPC API
+	deviceRepo := repository.NewDeviceRepository(db.Pool)
+	idempotencyRepo := repository.NewIdempotencyRepository(db.Pool)
+	notificationRepo := repository.NewNotificationRepository(db.Pool)
+	preferenceRepo := repository.NewPreferenceRepository(db.Pool)
+	quietHoursRepo := repository.NewQuietHoursRepository(db.Pool)
+	deferredPushRepo := repository.NewDeferredPushRepository(db.Pool)
+	apiKeyRepo := repository.NewAPIKeyRepository(db.Pool)
+
+	deviceService := service.NewDeviceService(deviceRepo, fcmClient, cfg)
+	quietHoursService := service.NewQuietHoursService(quietHoursRepo, deferredPushRepo, pushQueue, &cfg.QuietHours)
+	pushService := service.NewPushService(deviceRepo, idempotencyRepo, notificationRepo, preferenceRepo, templateService, quietHoursService, fcmClient, pushQueue, cfg)
+	notificationService := service.NewNotificationService(notificationRepo)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, &cfg.Auth)
+
+	return grpcserver.NewServer(deviceService, pushService, notificationService, apiKeyService, &cfg.Auth)
+}
+
+// setupHealthChecks registers the dependency checks behind /ready and the
+// stalled worker check behind /live
+func setupHealthChecks(db *database.DB, rabbitmqClient *rabbitmq.RabbitMQClient, redisClient *redis.RedisClient, fcmClient fcm.FCMClient, consumers *health.Consumers, cfg *config.Config) (*health.Registry, *health.Registry) {
+	readiness := health.NewRegistry(cfg.Health.CheckTimeout)
+	readiness.Register("postgres", db.Pool.Ping)
+	readiness.Register("rabbitmq", rabbitmqClient.Ping)
+	readiness.Register("consumers", consumers.CheckRegistered)
+	readiness.Register("fcm", fcmClient.CheckCredentials)
+	if cfg.Redis.Enabled {
+		readiness.Register("redis", func(ctx context.Context) error {
+			if redisClient == nil {
+				return errors.New("invalid configuration, rate limits are per replica")
+			}
+			return redisClient.Client.Ping(ctx).Err()
+		})
+	}
+
+	liveness := health.NewRegistry(cfg.Health.CheckTimeout)
+	liveness.Register("worker", consumers.CheckStalled(rabbitmqClient.QueueLength, cfg.Health.StallTimeout))
+
+	return readiness, liveness
+}
+
+func startPushWorker(pushQueue *queue.PushQueue, templateService service.TemplateService, fcmClient fcm.FCMClient, db *database.DB, consumers *health.Consumers, cfg *config.Config) {
+	ctx, cancel := context.WithCancel(logger.WithComponent(context.Background(), logger.ComponentWorker))
 	defer cancel()
 
 	// Initialize repositories and services for worker
 	deviceRepo := repository.NewDeviceRepository(db.Pool)
-	pushQueue, err := queue.NewPushQueue(rabbitmqClient, &cfg.Queue)
-	if err != nil {
-		logger.L().Fatal("Failed to initialize push queue in worker", zap.Error(err))
-	}
-	pushService := service.NewPushService(deviceRepo, fcmClient, pushQueue, cfg)
+	idempotencyRepo := repository.NewIdempotencyRepository(db.Pool)
+	notificationRepo := repository.NewNotificationRepository(db.Pool)
+	preferenceRepo := repository.NewPreferenceRepository(db.Pool)
+	quietHoursRepo := repository.NewQuietHoursRepository(db.Pool)
+	deferredPushRepo := repository.NewDeferredPushRepository(db.Pool)
+	quietHoursService := service.NewQuietHoursService(quietHoursRepo, deferredPushRepo, pushQueue, &cfg.QuietHours)
+	pushService := service.NewPushService(deviceRepo, idempotencyRepo, notificationRepo, preferenceRepo, templateService, quietHoursService, fcmClient, pushQueue, cfg)
 
 	logger.L().Info("Starting push worker...",
 		zap.Int("prefetch_count", cfg.Queue.Worker.PrefetchCount),
@@ -183,6 +424,7 @@ func startPushWorker(rabbitmqClient *rabbitmq.RabbitMQClient, fcmClient fcm.FCMC
 	if err != nil {
 		logger.L().Fatal("Failed to start consuming messages from internal queue", zap.Error(err))
 	}
+	consumers.Registered(queue.PushQueueName)
 
 	// Process internal queue messages in a goroutine
 	go func() {
@@ -194,7 +436,10 @@ func startPushWorker(rabbitmqClient *rabbitmq.RabbitMQClient, fcmClient fcm.FCMC
 					zap.Uint64("delivery_tag", delivery.DeliveryTag),
 				)
 			}
+			consumers.Processed(queue.PushQueueName)
 		}
+		consumers.Unregistered(queue.PushQueueName)
+		logger.L().Warn("Internal queue consumer stopped")
 	}()
 
 	// Start consuming messages from API Gateway queue
@@ -202,6 +447,7 @@ func startPushWorker(rabbitmqClient *rabbitmq.RabbitMQClient, fcmClient fcm.FCMC
 	if err != nil {
 		logger.L().Fatal("Failed to start consuming messages from gateway queue", zap.Error(err))
 	}
+	consumers.Registered(queue.GatewayPushQueueName)
 
 	// Process gateway messages in a goroutine
 	go func() {
@@ -213,16 +459,31 @@ func startPushWorker(rabbitmqClient *rabbitmq.RabbitMQClient, fcmClient fcm.FCMC
 					zap.Uint64("delivery_tag", delivery.DeliveryTag),
 				)
 			}
+			consumers.Processed(queue.GatewayPushQueueName)
 		}
+		consumers.Unregistered(queue.GatewayPushQueueName)
+		logger.L().Warn("Gateway queue consumer stopped")
 	}()
 
 	logger.L().Info("Push workers started (internal and gateway queues)")
 
+	// Publish notifications held during quiet hours once the hours end
+	go quietHoursService.Start(ctx)
+
 	// Wait for context cancellation (graceful shutdown)
 	<-ctx.Done()
 	logger.L().Info("Push worker shutting down...")
 }
 
+// tracedRoute leaves health checks and metric scrapes out of traces
+func tracedRoute(c *gin.Context) bool {
+	switch c.FullPath() {
+	case "/health", "/live", "/ready", "/metrics":
+		return false
+	}
+	return true
+}
+
 func loggerMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
@@ -230,13 +491,22 @@ func loggerMiddleware() gin.HandlerFunc {
 		// Process request
 		c.Next()
 
-		// Log after request completion
-		logger.L().Info("HTTP request",
+		// Log after request completion, with the request ID from AssignRequestID
+		logger.FromContext(c.Request.Context()).Info("HTTP request",
 			zap.String("method", c.Request.Method),
-			zap.String("path", c.Request.URL.Path),
+			zap.String("path", loggedPath(c)),
 			zap.Int("status", c.Writer.Status()),
 			zap.Duration("duration", time.Since(start)),
 			zap.String("client_ip", c.ClientIP()),
 		)
 	}
 }
+
+// loggedPath returns the request path with any device token in it redacted
+func loggedPath(c *gin.Context) string {
+	token := c.Param("token")
+	if token == "" {
+		return c.Request.URL.Path
+	}
+	return strings.Replace(c.Request.URL.Path, token, logger.RedactToken(token), 1)
+}
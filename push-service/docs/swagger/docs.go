@@ -24,7 +24,7 @@ const docTemplate = `{
     "paths": {
         "/health": {
             "get": {
-                "description": "Returns the health status of the service",
+                "description": "Returns whether the service is alive. Fails when the push worker has stalled: a queue has messages waiting but none were processed within the stall timeout.",
                 "consumes": [
                     "application/json"
                 ],
@@ -34,20 +34,55 @@ const docTemplate = `{
                 "tags": [
                     "health"
                 ],
-                "summary": "Health check endpoint",
+                "summary": "Liveness check endpoint",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/handlers.HealthResponse"
                         }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HealthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/live": {
+            "get": {
+                "description": "Returns whether the service is alive. Fails when the push worker has stalled: a queue has messages waiting but none were processed within the stall timeout.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Liveness check endpoint",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HealthResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HealthResponse"
+                        }
                     }
                 }
             }
         },
         "/ready": {
             "get": {
-                "description": "Returns the readiness status of the service including database connectivity",
+                "description": "Returns whether the service can take traffic: Postgres, the RabbitMQ connection and channel, the queue consumers, Redis when enabled and FCM credentials. Each check reports its latency and last error.",
                 "consumes": [
                     "application/json"
                 ],
@@ -74,9 +109,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/devices": {
+        "/v1/admin/apps/{app}/quiet-hours": {
             "get": {
-                "description": "Get all registered devices for a user",
+                "description": "Get the quiet hours applied to devices registered for the app whose users have not set their own",
                 "consumes": [
                     "application/json"
                 ],
@@ -84,15 +119,15 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "devices"
+                    "admin"
                 ],
-                "summary": "Get user devices",
+                "summary": "Get an app's quiet hours",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "user_id",
-                        "in": "query",
+                        "description": "App",
+                        "name": "app",
+                        "in": "path",
                         "required": true
                     }
                 ],
@@ -100,31 +135,30 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.GetUserDevicesResponse"
+                            "$ref": "#/definitions/models.QuietHours"
                         }
                     },
-                    "400": {
-                        "description": "User ID is required",
+                    "404": {
+                        "description": "Quiet hours not set (quiet_hours_not_found)",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Failed to get user devices",
+                        "description": "Failed to get quiet hours",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
             },
-            "post": {
-                "description": "Register a device token for push notifications",
+            "put": {
+                "description": "Hold non-urgent notifications to the app's devices between start and end, HH:MM in each device's time zone, unless the user has set their own quiet hours",
                 "consumes": [
                     "application/json"
                 ],
@@ -132,51 +166,55 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "devices"
+                    "admin"
                 ],
-                "summary": "Register a new device",
+                "summary": "Set an app's quiet hours",
                 "parameters": [
                     {
-                        "description": "Device registration request",
+                        "type": "string",
+                        "description": "App",
+                        "name": "app",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Quiet hours",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateDeviceRequest"
+                            "$ref": "#/definitions/models.SetQuietHoursRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.RegisterDeviceResponse"
+                            "$ref": "#/definitions/models.QuietHours"
                         }
                     },
                     "400": {
                         "description": "Invalid request body",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Failed to register device",
+                        "description": "Failed to set quiet hours",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
-                }
-            }
-        },
-        "/v1/devices/{token}": {
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
             "delete": {
-                "description": "Unregister a device token (soft delete)",
+                "description": "Remove the app's quiet hours. Notifications already held are delivered when their window ends.",
                 "consumes": [
                     "application/json"
                 ],
@@ -184,21 +222,21 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "devices"
+                    "admin"
                 ],
-                "summary": "Unregister a device",
+                "summary": "Delete an app's quiet hours",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Device token",
-                        "name": "token",
+                        "description": "App",
+                        "name": "app",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Device unregistered successfully",
+                        "description": "Quiet hours deleted successfully",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -206,30 +244,29 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "400": {
-                        "description": "Device token is required",
+                    "404": {
+                        "description": "Quiet hours not set (quiet_hours_not_found)",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Failed to unregister device",
+                        "description": "Failed to delete quiet hours",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
             }
         },
-        "/v1/push/send": {
-            "post": {
-                "description": "Send a push notification to a user's devices via RabbitMQ queue",
+        "/v1/admin/cleanup": {
+            "get": {
+                "description": "Get the stale device cleanup policy and the result of the last scheduled or manual run",
                 "consumes": [
                     "application/json"
                 ],
@@ -237,54 +274,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "push"
-                ],
-                "summary": "Send push notification",
-                "parameters": [
-                    {
-                        "description": "Push notification request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.SendPushRequest"
-                        }
-                    }
+                    "admin"
                 ],
+                "summary": "Get cleanup job status",
                 "responses": {
                     "200": {
-                        "description": "Push notification enqueued successfully",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid request body",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "500": {
-                        "description": "Failed to send push notification",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/handlers.CleanupStatusResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
             }
         },
-        "/v1/push/send-bulk": {
+        "/v1/admin/cleanup/run": {
             "post": {
-                "description": "Send push notifications to multiple users via RabbitMQ queue",
+                "description": "Run the stale device cleanup now. With dry_run=true the affected rows are counted but nothing is changed.",
                 "consumes": [
                     "application/json"
                 ],
@@ -292,52 +302,47 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "push"
+                    "admin"
                 ],
-                "summary": "Send bulk push notifications",
+                "summary": "Run cleanup job",
                 "parameters": [
                     {
-                        "description": "Bulk push notification request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.BulkPushRequest"
-                        }
+                        "type": "boolean",
+                        "description": "Only report what would be cleaned up",
+                        "name": "dry_run",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Bulk push notifications enqueued successfully",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/models.CleanupResult"
                         }
                     },
                     "400": {
-                        "description": "Invalid request body",
+                        "description": "Invalid dry_run value",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Failed to send bulk push notifications",
+                        "description": "Cleanup failed",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
             }
         },
-        "/v1/push/test-direct": {
-            "post": {
-                "description": "Send a test push notification directly via FCM (bypasses queue, for testing only)",
+        "/v1/admin/config": {
+            "get": {
+                "description": "Get the configuration the service is running with, after defaults, config file and environment are applied. Passwords, keys and credentials are redacted.",
                 "consumes": [
                     "application/json"
                 ],
@@ -345,54 +350,28 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "push"
-                ],
-                "summary": "Test direct FCM send",
-                "parameters": [
-                    {
-                        "description": "Direct send request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "object"
-                        }
-                    }
+                    "admin"
                 ],
+                "summary": "Get effective configuration",
                 "responses": {
                     "200": {
-                        "description": "FCM test message sent successfully",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid request body",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "500": {
-                        "description": "FCM send failed",
+                        "description": "OK",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "additionalProperties": true
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
             }
         },
-        "/v1/queue/stats": {
-            "get": {
-                "description": "Get statistics for all push notification queues (main, retry, dead letter)",
+        "/v1/admin/credentials/reload": {
+            "post": {
+                "description": "Reload the FCM service account and, when JWT auth is enabled, the JWKS file, so rotated credentials take effect without a restart. Credentials that fail to load keep their previous value.",
                 "consumes": [
                     "application/json"
                 ],
@@ -400,187 +379,3575 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "queue"
+                    "admin"
                 ],
-                "summary": "Get queue statistics",
+                "summary": "Reload credentials",
                 "responses": {
                     "200": {
-                        "description": "Queue statistics",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.CredentialReloadResult"
+                            }
                         }
                     },
-                    "500": {
-                        "description": "Failed to get queue statistics",
+                    "503": {
+                        "description": "One or more credentials failed to reload; details lists every result",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
-                }
-            }
-        }
-    },
-    "definitions": {
-        "handlers.GetUserDevicesResponse": {
-            "description": "User devices response",
-            "type": "object",
-            "properties": {
-                "count": {
-                    "type": "integer",
-                    "example": 2
                 },
-                "devices": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.DeviceResponse"
+                "security": [
+                    {
+                        "ApiKeyAuth": []
                     }
-                },
-                "user_id": {
-                    "type": "string",
-                    "example": "user123"
-                }
+                ]
             }
         },
-        "handlers.HealthResponse": {
-            "type": "object",
-            "properties": {
-                "database": {
+        "/v1/admin/devices": {
+            "get": {
+                "description": "Search all devices with filters, sorting and keyset pagination (admin)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Search devices",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "ios",
+                            "android",
+                            "web"
+                        ],
+                        "type": "string",
+                        "description": "Platform",
+                        "name": "platform",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "App",
+                        "name": "app",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Active flag",
+                        "name": "active",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created at or after (RFC3339)",
+                        "name": "created_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created before (RFC3339)",
+                        "name": "created_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last seen at or after (RFC3339)",
+                        "name": "last_seen_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last seen before (RFC3339)",
+                        "name": "last_seen_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Metadata filter as key:op:value, op one of eq, ne, lt, lte, gt, gte (e.g. app_version:lt:3.2)",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "created_at",
+                            "last_seen_at"
+                        ],
+                        "type": "string",
+                        "description": "Sort column",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "Sort order",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 500)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SearchDevicesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query (invalid_request) or cursor (invalid_cursor)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to search devices",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/v1/admin/keys": {
+            "get": {
+                "description": "List all API keys, including revoked and expired keys",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List API keys",
+                "responses": {
+                    "200": {
+                        "description": "API keys",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to list API keys",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Create a scoped API key. The plaintext key is only returned in this response.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Create API key",
+                "parameters": [
+                    {
+                        "description": "API key request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateAPIKeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.CreatedAPIKey"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or scope",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create API key",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/v1/admin/keys/{id}": {
+            "delete": {
+                "description": "Revoke an API key immediately",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Revoke API key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "API key revoked successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "API key not found (api_key_not_found)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to revoke API key",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/v1/admin/keys/{id}/rotate": {
+            "post": {
+                "description": "Issue a replacement key with the same name and scopes. The old key expires after the grace period.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Rotate API key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rotation options",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.RotateAPIKeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.CreatedAPIKey"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "API key not found (api_key_not_found)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to rotate API key",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/v1/admin/log-level": {
+            "get": {
+                "description": "Get the global log level and any component overrides, with their expiry times",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get log levels",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/logger.LevelStatus"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Change the global log level, or a component's level (http, grpc, worker, fcm, queue), without a restart. With a duration the change is undone once it elapses.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Set log level",
+                "parameters": [
+                    {
+                        "description": "Level change",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SetLogLevelRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/logger.LevelStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "Unknown level or component, or invalid duration",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/v1/admin/log-level/{component}": {
+            "delete": {
+                "description": "Remove a component's level override so it follows the global level again",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Clear component log level",
+                "parameters": [
+                    {
+                        "enum": [
+                            "http",
+                            "grpc",
+                            "worker",
+                            "fcm",
+                            "queue"
+                        ],
+                        "type": "string",
+                        "description": "Component",
+                        "name": "component",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/logger.LevelStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "Unknown component",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/v1/devices": {
+            "get": {
+                "description": "Get all registered devices for a user. End-user JWT callers get their own devices and may omit user_id.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "devices"
+                ],
+                "summary": "Get user devices",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (required for API key callers)",
+                        "name": "user_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.GetUserDevicesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "User ID is required",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key or user token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "user_id does not match the authenticated user",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get user devices",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "UserToken": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Register a device token for push notifications. End-user JWT callers may omit user_id.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "devices"
+                ],
+                "summary": "Register a new device",
+                "parameters": [
+                    {
+                        "description": "Device registration request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateDeviceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RegisterDeviceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key or user token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "user_id does not match the authenticated user",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Device token failed validation (invalid_device_token)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to register device",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "UserToken": []
+                    }
+                ]
+            }
+        },
+        "/v1/devices/batch": {
+            "post": {
+                "description": "Register up to DEVICES_BATCH_MAX_SIZE devices with one upsert, for app migrations and imports. Tokens are not validated with FCM at registration. Each entry is reported separately; entries that fail validation or repeat an earlier app and token are skipped.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "devices"
+                ],
+                "summary": "Register devices in bulk",
+                "parameters": [
+                    {
+                        "description": "Devices to register",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BatchRegisterDevicesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BatchDeviceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, empty batch or batch_too_large",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to register devices",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Unregister up to DEVICES_BATCH_MAX_SIZE device tokens (soft delete) in one statement. Tokens with no registered device are reported as device_not_found.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "devices"
+                ],
+                "summary": "Unregister devices in bulk",
+                "parameters": [
+                    {
+                        "description": "Device tokens to unregister",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BatchUnregisterDevicesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BatchDeviceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, empty batch or batch_too_large",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to unregister devices",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/v1/devices/{token}": {
+            "delete": {
+                "description": "Unregister a device token (soft delete). End-user JWT callers can only unregister their own devices.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "devices"
+                ],
+                "summary": "Unregister a device",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Device token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "App the token is registered in (default: default)",
+                        "name": "app",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Device unregistered successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Device token is required",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key or user token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Device not found (device_not_found)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to unregister device",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "UserToken": []
+                    }
+                ]
+            }
+        },
+        "/v1/devices/{token}/tags": {
+            "put": {
+                "description": "Replace all tags on a device, used for segment targeting",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "devices"
+                ],
+                "summary": "Set device tags",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Device token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "App the token is registered in (default: default)",
+                        "name": "app",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Device tags",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SetDeviceTagsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Device tags updated successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Device not found (device_not_found)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to set device tags",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/v1/push/send": {
+            "post": {
+                "description": "Send a push notification to a user's devices via RabbitMQ queue. With mode=sync (or \"sync\": true) the queue is bypassed and the response reports the outcome for each device. With template_id the title and body come from a stored template; {{variable}} placeholders are filled from variables, data and the user's attributes.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "push"
+                ],
+                "summary": "Send push notification",
+                "parameters": [
+                    {
+                        "description": "Push notification request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SendPushRequest"
+                        }
+                    },
+                    {
+                        "enum": [
+                            "async",
+                            "sync"
+                        ],
+                        "type": "string",
+                        "description": "Delivery mode",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client-chosen key; retries with the same key replay the original response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Per-device results of a synchronous send; asynchronous sends return a message, user_id and notification_id",
+                        "schema": {
+                            "$ref": "#/definitions/models.SyncPushResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body (invalid_request)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "The user has no devices (no_devices) or the template does not exist (template_not_found)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "A request with this Idempotency-Key is in progress (idempotency_key_in_progress)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "No devices match the requested platforms (no_matching_platforms), the template could not be rendered (template_render_failed) or the Idempotency-Key was used for a different request (idempotency_key_mismatch)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded (rate_limited)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Notification queue unavailable (queue_unavailable)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/v1/push/send-bulk": {
+            "post": {
+                "description": "Send push notifications to multiple users via RabbitMQ queue",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "push"
+                ],
+                "summary": "Send bulk push notifications",
+                "parameters": [
+                    {
+                        "description": "Bulk push notification request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BulkPushRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client-chosen key; retries with the same key replay the original response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Bulk push notifications enqueued successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body (invalid_request)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "A request with this Idempotency-Key is in progress (idempotency_key_in_progress)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Idempotency-Key was already used for a different request (idempotency_key_mismatch)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded (rate_limited)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/v1/push/send-segment": {
+            "post": {
+                "description": "Send a push notification to every active device matching a segment definition, e.g. \"platform = ios AND tag:beta AND app_version \u003e= 3.0\"",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "push"
+                ],
+                "summary": "Send push notification to a segment",
+                "parameters": [
+                    {
+                        "description": "Segment push notification request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SendSegmentPushRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client-chosen key; retries with the same key replay the original response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SegmentPushResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body (invalid_request) or segment (invalid_segment)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "A request with this Idempotency-Key is in progress (idempotency_key_in_progress)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Idempotency-Key was already used for a different request (idempotency_key_mismatch)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded (rate_limited)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Notification queue unavailable (queue_unavailable)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/v1/queue/stats": {
+            "get": {
+                "description": "Get statistics for all push notification queues (main, retry, dead letter)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "queue"
+                ],
+                "summary": "Get queue statistics",
+                "responses": {
+                    "200": {
+                        "description": "Queue statistics",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "503": {
+                        "description": "Notification queue unavailable (queue_unavailable)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/v1/templates": {
+            "get": {
+                "description": "List the latest version of every push template",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "templates"
+                ],
+                "summary": "List templates",
+                "responses": {
+                    "200": {
+                        "description": "Templates",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to list templates",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Create a push template at version 1, with content for one or more locales",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "templates"
+                ],
+                "summary": "Create template",
+                "parameters": [
+                    {
+                        "description": "Template",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateTemplateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.PushTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or placeholder (invalid_template)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Template key already exists (template_exists)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create template",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/v1/templates/{key}": {
+            "get": {
+                "description": "Get the latest version of a push template, or the version given",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "templates"
+                ],
+                "summary": "Get template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Template key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Template version",
+                        "name": "version",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PushTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid version",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Template or version not found (template_not_found)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get template",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Replace a push template's content, adding a new version. Sends use the new version at once on this instance and within templates.cache_ttl elsewhere.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "templates"
+                ],
+                "summary": "Update template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Template key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Template content",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateTemplateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PushTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or placeholder (invalid_template)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Template not found (template_not_found)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update template",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Delete a push template and all its versions",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "templates"
+                ],
+                "summary": "Delete template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Template key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Template deleted successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Template not found (template_not_found)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete template",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/v1/templates/{key}/preview": {
+            "post": {
+                "description": "Render a push template with sample variables and show the FCM message each platform would be sent. Nothing is sent.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "templates"
+                ],
+                "summary": "Preview template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Template key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Sample variables",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.PreviewTemplateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TemplatePreview"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Template or version not found (template_not_found)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Template could not be rendered (template_render_failed)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to preview template",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/v1/templates/{key}/versions": {
+            "get": {
+                "description": "List the versions of a push template, newest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "templates"
+                ],
+                "summary": "List template versions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Template key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Versions",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Template not found (template_not_found)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to list versions",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/v1/users/{user_id}": {
+            "delete": {
+                "description": "Hard-delete all devices and notification history for a user and publish an erasure audit event",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Erase user data",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserErasureResult"
+                        }
+                    },
+                    "400": {
+                        "description": "User ID is required",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to erase user data",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/v1/users/{user_id}/attributes": {
+            "get": {
+                "description": "Get the tags and key/value attributes used to target a user in segments",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get user attributes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserAttributes"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get user attributes",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Replace the tags and key/value attributes used to target a user in segments",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Set user attributes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User attributes",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SetUserAttributesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserAttributes"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to set user attributes",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/v1/users/{user_id}/devices": {
+            "delete": {
+                "description": "Unregister (soft delete) every device of a user, for logout-all, password resets and revoked sessions. except_token keeps one device, usually the caller's own; platform limits the platforms. Notifications already queued for the removed devices are dropped. End-user JWT callers can only act on themselves.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "devices"
+                ],
+                "summary": "Unregister all of a user's devices",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Device token to keep",
+                        "name": "except_token",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only unregister these platforms",
+                        "name": "platform",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UnregisterUserDevicesResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid platform",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key or user token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "user_id does not match the authenticated user",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to unregister devices",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "UserToken": []
+                    }
+                ]
+            }
+        },
+        "/v1/users/{user_id}/export": {
+            "get": {
+                "description": "Export all device and notification records held for a user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Export user data",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserDataExport"
+                        }
+                    },
+                    "400": {
+                        "description": "User ID is required",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to export user data",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/v1/users/{user_id}/preferences": {
+            "get": {
+                "description": "List the categories a user has opted in to or out of, for all devices or one. Categories without a preference are enabled.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get notification preferences",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserPreferences"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key or user token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "user_id does not match the authenticated user",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get preferences",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "UserToken": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Opt a user in to or out of categories, for all devices or, with device_token, one. Other preferences are kept. Transactional and security notifications cannot be turned off.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Set notification preferences",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Preferences",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SetPreferencesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserPreferences"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key or user token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "user_id does not match the authenticated user",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to set preferences",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "UserToken": []
+                    }
+                ]
+            }
+        },
+        "/v1/users/{user_id}/preferences/{category}": {
+            "delete": {
+                "description": "Remove a user-wide preference, or with device_token a device's, so the category falls back to the user-wide preference or the default",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Delete a notification preference",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Category",
+                        "name": "category",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Device token",
+                        "name": "device_token",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Preference deleted successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key or user token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "user_id does not match the authenticated user",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Preference not found (preference_not_found)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete preference",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "UserToken": []
+                    }
+                ]
+            }
+        },
+        "/v1/users/{user_id}/quiet-hours": {
+            "get": {
+                "description": "Get the daily window, in each device's local time, during which the user's non-urgent notifications are held",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get a user's quiet hours",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.QuietHours"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key or user token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "user_id does not match the authenticated user",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Quiet hours not set (quiet_hours_not_found)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get quiet hours",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "UserToken": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Hold the user's non-urgent notifications between start and end, HH:MM in each device's time zone, and deliver them when the window ends. A window that ends before it starts spans midnight. The user's quiet hours replace their apps'.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Set a user's quiet hours",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Quiet hours",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SetQuietHoursRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.QuietHours"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key or user token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "user_id does not match the authenticated user",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to set quiet hours",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "UserToken": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Remove the user's quiet hours, so their apps' quiet hours apply. Notifications already held are delivered when their window ends.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Delete a user's quiet hours",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Quiet hours deleted successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key or user token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "user_id does not match the authenticated user",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Quiet hours not set (quiet_hours_not_found)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete quiet hours",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "UserToken": []
+                    }
+                ]
+            }
+        }
+    },
+    "definitions": {
+        "handlers.CleanupStatusResponse": {
+            "description": "Stale device cleanup job status",
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "last_result": {
+                    "$ref": "#/definitions/models.CleanupResult"
+                },
+                "policy": {
+                    "$ref": "#/definitions/models.CleanupPolicy"
+                }
+            }
+        },
+        "handlers.CredentialReloadResult": {
+            "description": "Credential reload outcome",
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "failed to read JWKS file: no such file or directory"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "fcm"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "reloaded"
+                }
+            }
+        },
+        "handlers.GetUserDevicesResponse": {
+            "description": "User devices response",
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "devices": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DeviceResponse"
+                    }
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "user123"
+                }
+            }
+        },
+        "handlers.HealthResponse": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/health.CheckResult"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "healthy"
+                },
+                "timestamp": {
+                    "type": "string",
+                    "example": "2025-01-01T00:00:00Z"
+                }
+            }
+        },
+        "handlers.RegisterDeviceResponse": {
+            "description": "Device registration response",
+            "type": "object",
+            "properties": {
+                "device": {
+                    "$ref": "#/definitions/models.DeviceResponse"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Device registered successfully"
+                }
+            }
+        },
+        "handlers.SearchDevicesResponse": {
+            "description": "Admin device search response",
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 50
+                },
+                "devices": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Device"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string",
+                    "example": "eyJ2IjoiMjAyNS0wMS0wMVQwMDowMDowMFoiLCJpZCI6IjEyMyJ9"
+                }
+            }
+        },
+        "handlers.SetLogLevelRequest": {
+            "description": "Log level change. Without a component the global level is changed.",
+            "type": "object",
+            "required": [
+                "level"
+            ],
+            "properties": {
+                "component": {
+                    "type": "string",
+                    "example": "fcm"
+                },
+                "duration": {
+                    "description": "Duration after which the change is undone, such as \"15m\". Permanent when empty.",
+                    "type": "string",
+                    "example": "15m"
+                },
+                "level": {
+                    "type": "string",
+                    "example": "debug"
+                }
+            }
+        },
+        "health.CheckResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "connection is closed"
+                },
+                "last_error": {
+                    "description": "LastError is the most recent failure, kept after the check recovers",
+                    "type": "string",
+                    "example": "connection is closed"
+                },
+                "last_error_at": {
+                    "type": "string"
+                },
+                "latency_ms": {
+                    "type": "number",
+                    "example": 1.25
+                },
+                "status": {
+                    "type": "string",
+                    "example": "healthy"
+                }
+            }
+        },
+        "logger.ComponentLevel": {
+            "type": "object",
+            "properties": {
+                "component": {
+                    "type": "string",
+                    "example": "fcm"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "level": {
+                    "type": "string",
+                    "example": "debug"
+                }
+            }
+        },
+        "logger.LevelStatus": {
+            "type": "object",
+            "properties": {
+                "components": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/logger.ComponentLevel"
+                    }
+                },
+                "level": {
+                    "type": "string",
+                    "example": "info"
+                },
+                "level_expires_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BatchDeviceResponse": {
+            "type": "object",
+            "properties": {
+                "failure_count": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BatchDeviceResult"
+                    }
+                },
+                "success_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.BatchDeviceResult": {
+            "type": "object",
+            "properties": {
+                "device": {
+                    "$ref": "#/definitions/models.DeviceResponse"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "error_code": {
+                    "type": "string",
+                    "example": "device_not_found"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "success": {
+                    "type": "boolean"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BatchRegisterDevicesRequest": {
+            "type": "object",
+            "required": [
+                "devices"
+            ],
+            "properties": {
+                "devices": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CreateDeviceRequest"
+                    }
+                }
+            }
+        },
+        "models.BatchUnregisterDevicesRequest": {
+            "type": "object",
+            "required": [
+                "tokens"
+            ],
+            "properties": {
+                "tokens": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.BulkPushRequest": {
+            "type": "object",
+            "required": [
+                "body",
+                "title",
+                "user_ids"
+            ],
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "category": {
+                    "type": "string",
+                    "enum": [
+                        "transactional",
+                        "security",
+                        "marketing",
+                        "promotions",
+                        "social",
+                        "reminders"
+                    ],
+                    "example": "marketing"
+                },
+                "data": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "title": {
+                    "type": "string"
+                },
+                "user_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.CleanupPolicy": {
+            "type": "object",
+            "properties": {
+                "deactivate_after_days": {
+                    "type": "integer"
+                },
+                "delete_inactive_after_days": {
+                    "type": "integer"
+                },
+                "history_retention_days": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CleanupResult": {
+            "type": "object",
+            "properties": {
+                "devices_deactivated": {
+                    "type": "integer"
+                },
+                "devices_deleted": {
+                    "type": "integer"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "gateway_messages_purged": {
+                    "type": "integer"
+                },
+                "idempotency_keys_purged": {
+                    "type": "integer"
+                },
+                "notifications_purged": {
+                    "type": "integer"
+                },
+                "skipped": {
+                    "description": "Another replica held the lock",
+                    "type": "boolean"
+                },
+                "started_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ContentOverride": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "image": {
+                    "type": "string"
+                },
+                "link": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateAPIKeyRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "scopes"
+            ],
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "billing-service"
+                },
+                "rate_limit": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 1200
+                },
+                "scopes": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "push:send"
+                    ]
+                }
+            }
+        },
+        "models.CreateDeviceRequest": {
+            "type": "object",
+            "required": [
+                "platform",
+                "token"
+            ],
+            "properties": {
+                "app": {
+                    "description": "Defaults to DefaultApp",
+                    "type": "string"
+                },
+                "locale": {
+                    "description": "Locale is the device's language, such as pt-BR. An existing device\nkeeps its locale when this is empty.",
+                    "type": "string",
+                    "example": "pt-BR"
+                },
+                "metadata": {
+                    "description": "Metadata such as app_version or os_version, merged into any existing metadata",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "platform": {
+                    "type": "string",
+                    "enum": [
+                        "ios",
+                        "android",
+                        "web"
+                    ]
+                },
+                "tags": {
+                    "description": "Tags are added to any existing device tags",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "timezone": {
+                    "description": "Timezone is the device's IANA time zone, used for quiet hours. An\nexisting device keeps its time zone when this is empty.",
+                    "type": "string",
+                    "example": "America/Sao_Paulo"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "Required unless taken from an end-user JWT",
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateTemplateRequest": {
+            "type": "object",
+            "required": [
+                "default_locale",
+                "key",
+                "locales"
+            ],
+            "properties": {
+                "default_locale": {
+                    "type": "string",
+                    "example": "en"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "order_shipped"
+                },
+                "locales": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.LocalizedContent"
+                    }
+                }
+            }
+        },
+        "models.CreatedAPIKey": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string",
+                    "example": "psk_1a2b3c4d_..."
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "rate_limit": {
+                    "description": "RateLimit overrides the default requests per rate limit window",
+                    "type": "integer"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "rotated_from": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.DeferredPush": {
+            "type": "object",
+            "properties": {
+                "deliver_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "object"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Device": {
+            "type": "object",
+            "properties": {
+                "app": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "last_seen_at": {
+                    "type": "string"
+                },
+                "locale": {
+                    "type": "string"
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "platform": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "timezone": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DeviceResponse": {
+            "type": "object",
+            "properties": {
+                "app": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "locale": {
+                    "type": "string"
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "platform": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "timezone": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DeviceSendResult": {
+            "type": "object",
+            "properties": {
+                "device_id": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "error_code": {
+                    "type": "string",
+                    "example": "unregistered"
+                },
+                "message_id": {
+                    "type": "string"
+                },
+                "platform": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                },
+                "suppressed": {
+                    "description": "Suppressed is set when the device opted out of the category and was not sent to",
+                    "type": "boolean"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ErrorResponse": {
+            "description": "API error",
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "device_not_found"
+                },
+                "details": {
+                    "type": "object"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Device not found"
+                },
+                "request_id": {
+                    "type": "string",
+                    "example": "6f1c2a9e-3b7d-4c1e-9a51-0d2f8e4b7c63"
+                }
+            }
+        },
+        "models.LocalizationKeys": {
+            "type": "object",
+            "properties": {
+                "body_loc_args": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "A-1042"
+                    ]
+                },
+                "body_loc_key": {
+                    "type": "string",
+                    "example": "order_shipped_body"
+                },
+                "title_loc_args": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title_loc_key": {
+                    "type": "string",
+                    "example": "order_shipped_title"
+                }
+            }
+        },
+        "models.LocalizedContent": {
+            "type": "object",
+            "required": [
+                "body",
+                "title"
+            ],
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "example": "Hi {{name}}, order {{order_id}} is on its way"
+                },
+                "image": {
+                    "type": "string"
+                },
+                "link": {
+                    "type": "string",
+                    "example": "https://example.com/orders/{{order_id}}"
+                },
+                "platform_overrides": {
+                    "description": "PlatformOverrides replace fields on ios, android or web devices",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.ContentOverride"
+                    }
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Your order shipped"
+                }
+            }
+        },
+        "models.NotificationPreference": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string",
+                    "example": "promotions"
+                },
+                "device_token": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PreferenceUpdate": {
+            "type": "object",
+            "required": [
+                "category",
+                "enabled"
+            ],
+            "properties": {
+                "category": {
+                    "type": "string",
+                    "enum": [
+                        "marketing",
+                        "promotions",
+                        "social",
+                        "reminders"
+                    ],
+                    "example": "promotions"
+                },
+                "device_token": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "models.PreviewTemplateRequest": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "locale": {
+                    "type": "string",
+                    "example": "pt-BR"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "UserID adds the user's attributes to the variables",
+                    "type": "string"
+                },
+                "variables": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "version": {
+                    "description": "Version defaults to the latest",
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
+        "models.PushNotification": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "data": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "device_id": {
+                    "type": "string"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "image": {
+                    "type": "string"
+                },
+                "link": {
+                    "type": "string"
+                },
+                "locales": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.LocalizedContent"
+                    }
+                },
+                "localization": {
+                    "$ref": "#/definitions/models.LocalizationKeys"
+                },
+                "platform_overrides": {
+                    "description": "PlatformOverrides and Locales are applied per device by ForDevice",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.ContentOverride"
+                    }
+                },
+                "sent_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PushTemplate": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "default_locale": {
+                    "description": "DefaultLocale is used for devices whose locale has no variant",
+                    "type": "string",
+                    "example": "en"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string",
+                    "example": "order_shipped"
+                },
+                "latest_version": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "locales": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.LocalizedContent"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "models.QuietHours": {
+            "type": "object",
+            "properties": {
+                "end": {
                     "type": "string",
-                    "example": "healthy"
+                    "example": "07:00"
                 },
-                "status": {
+                "scope": {
                     "type": "string",
-                    "example": "healthy"
+                    "example": "user"
                 },
-                "timestamp": {
+                "scope_id": {
                     "type": "string",
-                    "example": "2025-01-01T00:00:00Z"
+                    "example": "user123"
+                },
+                "start": {
+                    "type": "string",
+                    "example": "22:00"
+                },
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
-        "handlers.RegisterDeviceResponse": {
-            "description": "Device registration response",
+        "models.RotateAPIKeyRequest": {
             "type": "object",
             "properties": {
-                "device": {
-                    "$ref": "#/definitions/models.DeviceResponse"
+                "grace_period_seconds": {
+                    "description": "GracePeriodSeconds keeps the old key valid for this long after rotation",
+                    "type": "integer",
+                    "example": 3600
+                }
+            }
+        },
+        "models.SegmentPushResult": {
+            "type": "object",
+            "properties": {
+                "device_count": {
+                    "type": "integer"
                 },
-                "message": {
-                    "type": "string",
-                    "example": "Device registered successfully"
+                "enqueued_count": {
+                    "type": "integer"
+                },
+                "segment": {
+                    "type": "string"
                 }
             }
         },
-        "models.BulkPushRequest": {
+        "models.SendPushRequest": {
             "type": "object",
             "required": [
-                "body",
-                "title",
-                "user_ids"
+                "user_id"
             ],
             "properties": {
                 "body": {
                     "type": "string"
                 },
+                "category": {
+                    "description": "Category is checked against the user's preferences; it defaults to transactional",
+                    "type": "string",
+                    "enum": [
+                        "transactional",
+                        "security",
+                        "marketing",
+                        "promotions",
+                        "social",
+                        "reminders"
+                    ],
+                    "example": "promotions"
+                },
                 "data": {
                     "type": "object",
                     "additionalProperties": {}
                 },
-                "title": {
+                "image": {
                     "type": "string"
                 },
-                "user_ids": {
+                "link": {
+                    "type": "string"
+                },
+                "locales": {
+                    "description": "Locales are title and body variants, picked per device by its locale.\nTitle and body are sent to devices matching none of them.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.LocalizedContent"
+                    }
+                },
+                "localization": {
+                    "description": "Localization sends keys for the app to localize instead",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.LocalizationKeys"
+                        }
+                    ]
+                },
+                "platforms": {
+                    "description": "Filter by specific platforms",
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
+                },
+                "sync": {
+                    "description": "Send now, bypassing the queue, and report per-device results",
+                    "type": "boolean"
+                },
+                "template_id": {
+                    "description": "TemplateID renders the content from a stored template instead. Image\nand link, when given, take precedence over the template's.",
+                    "type": "string",
+                    "example": "order_shipped"
+                },
+                "template_version": {
+                    "description": "TemplateVersion pins a template version; the latest is used by default",
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "title": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "variables": {
+                    "description": "Variables fill {{variable}} placeholders, taking precedence over data\nand user attributes. With variables, an inline title and body are\nrendered too.",
+                    "type": "object",
+                    "additionalProperties": {}
                 }
             }
         },
-        "models.CreateDeviceRequest": {
+        "models.SendSegmentPushRequest": {
             "type": "object",
             "required": [
-                "platform",
-                "token",
-                "user_id"
+                "body",
+                "segment",
+                "title"
             ],
             "properties": {
-                "platform": {
+                "body": {
+                    "type": "string"
+                },
+                "category": {
+                    "description": "Category is checked against each user's preferences; it defaults to transactional",
                     "type": "string",
                     "enum": [
-                        "ios",
-                        "android",
-                        "web"
-                    ]
+                        "transactional",
+                        "security",
+                        "marketing",
+                        "promotions",
+                        "social",
+                        "reminders"
+                    ],
+                    "example": "marketing"
                 },
-                "token": {
+                "data": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "image": {
                     "type": "string"
                 },
-                "user_id": {
+                "link": {
+                    "type": "string"
+                },
+                "segment": {
+                    "type": "string",
+                    "example": "platform = ios AND tag:beta AND app_version \u003e= 3.0"
+                },
+                "title": {
                     "type": "string"
                 }
             }
         },
-        "models.DeviceResponse": {
+        "models.SetDeviceTagsRequest": {
             "type": "object",
             "properties": {
-                "id": {
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.SetPreferencesRequest": {
+            "type": "object",
+            "required": [
+                "preferences"
+            ],
+            "properties": {
+                "preferences": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/models.PreferenceUpdate"
+                    }
+                }
+            }
+        },
+        "models.SetQuietHoursRequest": {
+            "type": "object",
+            "required": [
+                "end",
+                "start"
+            ],
+            "properties": {
+                "end": {
+                    "type": "string",
+                    "example": "07:00"
+                },
+                "start": {
+                    "type": "string",
+                    "example": "22:00"
+                }
+            }
+        },
+        "models.SetUserAttributesRequest": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.SyncPushResult": {
+            "type": "object",
+            "properties": {
+                "failure_count": {
+                    "type": "integer"
+                },
+                "notification_id": {
                     "type": "string"
                 },
-                "is_active": {
-                    "type": "boolean"
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DeviceSendResult"
+                    }
                 },
-                "platform": {
+                "success_count": {
+                    "type": "integer"
+                },
+                "suppressed_count": {
+                    "type": "integer"
+                },
+                "user_id": {
                     "type": "string"
+                }
+            }
+        },
+        "models.TemplatePreview": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "$ref": "#/definitions/models.LocalizedContent"
                 },
-                "token": {
+                "key": {
                     "type": "string"
                 },
-                "user_id": {
+                "locale": {
                     "type": "string"
+                },
+                "payloads": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
-        "models.SendPushRequest": {
+        "models.UnregisterUserDevicesResult": {
+            "type": "object",
+            "properties": {
+                "unregistered_count": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "user123"
+                }
+            }
+        },
+        "models.UpdateTemplateRequest": {
             "type": "object",
             "required": [
-                "body",
-                "title",
-                "user_id"
+                "default_locale",
+                "locales"
             ],
             "properties": {
-                "body": {
+                "default_locale": {
+                    "type": "string",
+                    "example": "en"
+                },
+                "description": {
                     "type": "string"
                 },
-                "data": {
+                "locales": {
                     "type": "object",
-                    "additionalProperties": {}
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.LocalizedContent"
+                    }
+                }
+            }
+        },
+        "models.UserAttributes": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
                 },
-                "image": {
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updated_at": {
                     "type": "string"
                 },
-                "link": {
+                "user_id": {
                     "type": "string"
+                }
+            }
+        },
+        "models.UserDataExport": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "$ref": "#/definitions/models.UserAttributes"
                 },
-                "platforms": {
-                    "description": "Filter by specific platforms",
+                "deferred_pushes": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/models.DeferredPush"
                     }
                 },
-                "title": {
+                "devices": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Device"
+                    }
+                },
+                "exported_at": {
+                    "type": "string"
+                },
+                "notifications": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PushNotification"
+                    }
+                },
+                "preferences": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.NotificationPreference"
+                    }
+                },
+                "quiet_hours": {
+                    "$ref": "#/definitions/models.QuietHours"
+                },
+                "user_id": {
                     "type": "string"
+                }
+            }
+        },
+        "models.UserErasureResult": {
+            "type": "object",
+            "properties": {
+                "attributes_deleted": {
+                    "type": "boolean"
+                },
+                "audit_event_published": {
+                    "type": "boolean"
+                },
+                "deferred_pushes_deleted": {
+                    "type": "integer"
+                },
+                "devices_deleted": {
+                    "type": "integer"
+                },
+                "erased_at": {
+                    "type": "string"
+                },
+                "gateway_messages_deleted": {
+                    "type": "integer"
+                },
+                "idempotency_keys_deleted": {
+                    "type": "integer"
+                },
+                "notifications_deleted": {
+                    "type": "integer"
+                },
+                "preferences_deleted": {
+                    "type": "integer"
+                },
+                "quiet_hours_deleted": {
+                    "type": "boolean"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.UserPreferences": {
+            "type": "object",
+            "properties": {
+                "preferences": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.NotificationPreference"
+                    }
                 },
                 "user_id": {
                     "type": "string"
                 }
             }
         }
+    },
+    "securityDefinitions": {
+        "ApiKeyAuth": {
+            "description": "Scoped API key. Also accepted as \"Authorization: Bearer \u003ckey\u003e\".",
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
+        },
+        "UserToken": {
+            "description": "End-user JWT as \"Bearer \u003ctoken\u003e\", accepted on device routes when auth.jwt is enabled.",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        }
     }
 }`
 
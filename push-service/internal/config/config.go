@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"os"
+	"push-service/internal/templating"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -10,13 +12,25 @@ import (
 )
 
 type Config struct {
-	Server   ServerConfig   `mapstructure:"server"`
-	Database DatabaseConfig `mapstructure:"database"`
-	Redis    RedisConfig    `mapstructure:"redis"`
-	RabbitMQ RabbitMQConfig `mapstructure:"rabbitmq"`
-	FCM      FCMConfig      `mapstructure:"fcm"`
-	Log      LogConfig      `mapstructure:"log"`
-	Queue    QueueConfig    `mapstructure:"queue"`
+	Server      ServerConfig      `mapstructure:"server"`
+	GRPC        GRPCConfig        `mapstructure:"grpc"`
+	Database    DatabaseConfig    `mapstructure:"database"`
+	Redis       RedisConfig       `mapstructure:"redis"`
+	RabbitMQ    RabbitMQConfig    `mapstructure:"rabbitmq"`
+	FCM         FCMConfig         `mapstructure:"fcm"`
+	Log         LogConfig         `mapstructure:"log"`
+	Metrics     MetricsConfig     `mapstructure:"metrics"`
+	Tracing     TracingConfig     `mapstructure:"tracing"`
+	Health      HealthConfig      `mapstructure:"health"`
+	Admin       AdminConfig       `mapstructure:"admin"`
+	Queue       QueueConfig       `mapstructure:"queue"`
+	Devices     DevicesConfig     `mapstructure:"devices"`
+	Cleanup     CleanupConfig     `mapstructure:"cleanup"`
+	Auth        AuthConfig        `mapstructure:"auth"`
+	RateLimit   RateLimitConfig   `mapstructure:"rate_limit"`
+	Idempotency IdempotencyConfig `mapstructure:"idempotency"`
+	Templates   TemplatesConfig   `mapstructure:"templates"`
+	QuietHours  QuietHoursConfig  `mapstructure:"quiet_hours"`
 }
 
 type ServerConfig struct {
@@ -25,11 +39,17 @@ type ServerConfig struct {
 	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
 }
 
+// GRPCConfig controls the gRPC API, served on its own port alongside HTTP
+type GRPCConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Port    string `mapstructure:"port"`
+}
+
 type DatabaseConfig struct {
 	Host            string        `mapstructure:"host"`
 	Port            string        `mapstructure:"port"`
 	User            string        `mapstructure:"user"`
-	Password        string        `mapstructure:"password"`
+	Password        string        `mapstructure:"password" redact:"true"`
 	Name            string        `mapstructure:"name"`
 	SSLMode         string        `mapstructure:"ssl_mode"`
 	MaxOpenConns    int           `mapstructure:"max_open_conns"`
@@ -38,28 +58,65 @@ type DatabaseConfig struct {
 }
 
 type RedisConfig struct {
+	Enabled  bool   `mapstructure:"enabled"`
 	Host     string `mapstructure:"host"`
 	Port     string `mapstructure:"port"`
-	Password string `mapstructure:"password"`
+	Password string `mapstructure:"password" redact:"true"`
 	DB       int    `mapstructure:"db"`
 }
 
 type FCMConfig struct {
-	CredentialsJSON string `mapstructure:"credentials_json"`
+	CredentialsJSON string `mapstructure:"credentials_json" redact:"true"`
 	ProjectID       string `mapstructure:"project_id"`
 	UseFile         bool   `mapstructure:"use_file"`
 }
 
 type LogConfig struct {
-	Level  string `mapstructure:"level"`
-	Format string `mapstructure:"format"`
+	Level     string             `mapstructure:"level"`
+	Format    string             `mapstructure:"format"`
+	Redaction LogRedactionConfig `mapstructure:"redaction"`
+}
+
+// LogRedactionConfig controls how device tokens and notification content
+// appear in logs
+type LogRedactionConfig struct {
+	Tokens   string   `mapstructure:"tokens"`    // mask or hash
+	Content  bool     `mapstructure:"content"`   // Log notification titles, bodies and data values
+	DataKeys []string `mapstructure:"data_keys"` // Data keys whose values are never logged
+}
+
+// MetricsConfig controls the Prometheus /metrics endpoint
+type MetricsConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+}
+
+// TracingConfig controls OpenTelemetry tracing and where spans are exported
+type TracingConfig struct {
+	Enabled     bool    `mapstructure:"enabled"`
+	Exporter    string  `mapstructure:"exporter"` // otlp, stdout or file
+	Endpoint    string  `mapstructure:"endpoint"` // OTLP gRPC collector address
+	Insecure    bool    `mapstructure:"insecure"`
+	File        string  `mapstructure:"file"`
+	ServiceName string  `mapstructure:"service_name"`
+	SampleRatio float64 `mapstructure:"sample_ratio"`
+}
+
+// HealthConfig controls the readiness and liveness checks
+type HealthConfig struct {
+	CheckTimeout time.Duration `mapstructure:"check_timeout"` // Per-check time limit
+	StallTimeout time.Duration `mapstructure:"stall_timeout"` // Idle time with messages waiting before the worker counts as stalled
+}
+
+// AdminConfig controls optional admin-only endpoints
+type AdminConfig struct {
+	PprofEnabled bool `mapstructure:"pprof_enabled"` // Serve net/http/pprof under /debug/pprof
 }
 
 type RabbitMQConfig struct {
 	Host     string `mapstructure:"host"`
 	Port     string `mapstructure:"port"`
 	Username string `mapstructure:"username"`
-	Password string `mapstructure:"password"`
+	Password string `mapstructure:"password" redact:"true"`
 	VHost    string `mapstructure:"vhost"`
 }
 
@@ -67,6 +124,8 @@ type QueueConfig struct {
 	Worker      WorkerConfig      `mapstructure:"worker"`
 	Retry       RetryConfig       `mapstructure:"retry"`
 	Validation  ValidationConfig  `mapstructure:"validation"`
+	Segment     SegmentConfig     `mapstructure:"segment"`
+	Sync        SyncConfig        `mapstructure:"sync"`
 }
 
 type WorkerConfig struct {
@@ -75,6 +134,18 @@ type WorkerConfig struct {
 	BatchSize     int           `mapstructure:"batch_size"`
 }
 
+type SegmentConfig struct {
+	PageSize int `mapstructure:"page_size"` // users whose devices are read per page
+}
+
+// SyncConfig bounds synchronous sends, which bypass the queue and call FCM
+// while the client waits. MaxConcurrency caps in-flight FCM calls across all
+// synchronous requests.
+type SyncConfig struct {
+	Timeout        time.Duration `mapstructure:"timeout"`
+	MaxConcurrency int           `mapstructure:"max_concurrency"`
+}
+
 type RetryConfig struct {
 	MaxRetries int           `mapstructure:"max_retries"`
 	Backoff    time.Duration `mapstructure:"backoff"`
@@ -85,6 +156,92 @@ type ValidationConfig struct {
 	Timeout time.Duration `mapstructure:"timeout"`
 }
 
+// DevicesConfig limits the batch device endpoints
+type DevicesConfig struct {
+	BatchMaxSize int `mapstructure:"batch_max_size"`
+}
+
+// AuthConfig controls API key authentication. BootstrapKey is an admin key
+// taken from the environment, used to create the first stored keys.
+type AuthConfig struct {
+	Enabled      bool      `mapstructure:"enabled"`
+	BootstrapKey string    `mapstructure:"bootstrap_key" redact:"true"`
+	JWT          JWTConfig `mapstructure:"jwt"`
+}
+
+// JWTConfig controls end-user tokens accepted on device routes. HS256 tokens
+// are verified with HMACSecret and RS256 tokens with the keys in JWKSFile.
+type JWTConfig struct {
+	Enabled     bool          `mapstructure:"enabled"`
+	HMACSecret  string        `mapstructure:"hmac_secret" redact:"true"`
+	JWKSFile    string        `mapstructure:"jwks_file"`
+	Issuer      string        `mapstructure:"issuer"`
+	Audience    string        `mapstructure:"audience"`
+	UserIDClaim string        `mapstructure:"user_id_claim"`
+	Leeway      time.Duration `mapstructure:"leeway"`
+}
+
+// RateLimitConfig controls HTTP rate limits, counted in requests per Window.
+// Routes overrides the per-caller limit for "METHOD /path" keys. A limit of 0
+// disables it.
+type RateLimitConfig struct {
+	Enabled bool           `mapstructure:"enabled"`
+	Window  time.Duration  `mapstructure:"window"`
+	PerKey  int            `mapstructure:"per_key"`
+	PerUser int            `mapstructure:"per_user"`
+	Routes  map[string]int `mapstructure:"routes"`
+}
+
+// RouteLimit returns the configured limit for a route, if any. Viper lowercases
+// map keys, so routes are matched case-insensitively.
+func (c *RateLimitConfig) RouteLimit(method, path string) (int, bool) {
+	limit, ok := c.Routes[strings.ToLower(method+" "+path)]
+	return limit, ok
+}
+
+// IdempotencyConfig controls how long Idempotency-Key results and processed
+// gateway notification IDs are remembered
+type IdempotencyConfig struct {
+	TTL time.Duration `mapstructure:"ttl"`
+	// LockTimeout is how long a key stays reserved by a request that has not
+	// finished, after which a retry takes it over. It should exceed the
+	// longest send.
+	LockTimeout time.Duration `mapstructure:"lock_timeout"`
+}
+
+// TemplatesConfig controls how {{variable}} placeholders in push content are rendered
+type TemplatesConfig struct {
+	MissingVariables string `mapstructure:"missing_variables"` // error, empty or keep
+	Escape           string `mapstructure:"escape"`            // none or html, applied to substituted values
+	// CacheTTL is how long each instance caches the latest version of a
+	// template. Changes made through other instances show after this long.
+	// 0 disables the cache.
+	CacheTTL time.Duration `mapstructure:"cache_ttl"`
+}
+
+// CleanupConfig controls the stale device janitor. It is off unless enabled,
+// as it deletes data. A policy set to 0 days is disabled.
+type CleanupConfig struct {
+	Enabled                 bool          `mapstructure:"enabled"`
+	Interval                time.Duration `mapstructure:"interval"`
+	DeactivateAfterDays     int           `mapstructure:"deactivate_after_days"`
+	DeleteInactiveAfterDays int           `mapstructure:"delete_inactive_after_days"`
+	HistoryRetentionDays    int           `mapstructure:"history_retention_days"`
+}
+
+// QuietHoursConfig controls holding notifications for devices in quiet hours
+// and the scheduler that publishes them when the quiet hours end
+type QuietHoursConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// DefaultTimezone applies to devices that have not reported a valid time zone
+	DefaultTimezone string `mapstructure:"default_timezone"`
+	// BypassCategories are sent during quiet hours. A notification without a
+	// category is transactional.
+	BypassCategories []string      `mapstructure:"bypass_categories"`
+	PollInterval     time.Duration `mapstructure:"poll_interval"` // how often due notifications are published
+	BatchSize        int           `mapstructure:"batch_size"`    // notifications published per poll
+}
+
 func Load() (*Config, error) {
 	// Load .env file if exists
 	godotenv.Load() // This will load .env file, but doesn't fail if it doesn't exist
@@ -133,6 +290,9 @@ func setDefaults() {
 	viper.SetDefault("server.mode", "debug")
 	viper.SetDefault("server.shutdown_timeout", "30s")
 
+	viper.SetDefault("grpc.enabled", true)
+	viper.SetDefault("grpc.port", "9090")
+
 	viper.SetDefault("database.host", "localhost")
 	viper.SetDefault("database.port", "5432")
 	viper.SetDefault("database.name", "push_service")
@@ -141,6 +301,7 @@ func setDefaults() {
 	viper.SetDefault("database.max_idle_conns", 25)
 	viper.SetDefault("database.conn_max_lifetime", "5m")
 
+	viper.SetDefault("redis.enabled", false)
 	viper.SetDefault("redis.host", "localhost")
 	viper.SetDefault("redis.port", "6379")
 	viper.SetDefault("redis.db", 0)
@@ -158,9 +319,65 @@ func setDefaults() {
 	viper.SetDefault("queue.retry.backoff", "5s")
 	viper.SetDefault("queue.validation.enabled", true)
 	viper.SetDefault("queue.validation.timeout", "5s")
+	viper.SetDefault("queue.segment.page_size", 500)
+	viper.SetDefault("queue.sync.timeout", "10s")
+	viper.SetDefault("queue.sync.max_concurrency", 20)
+
+	viper.SetDefault("devices.batch_max_size", 1000)
+
+	viper.SetDefault("cleanup.enabled", false)
+	viper.SetDefault("cleanup.interval", "1h")
+	viper.SetDefault("cleanup.deactivate_after_days", 90)
+	viper.SetDefault("cleanup.delete_inactive_after_days", 30)
+	viper.SetDefault("cleanup.history_retention_days", 30)
+
+	viper.SetDefault("auth.enabled", true)
+	viper.SetDefault("auth.jwt.enabled", false)
+	viper.SetDefault("auth.jwt.user_id_claim", "sub")
+	viper.SetDefault("auth.jwt.leeway", "1m")
+
+	viper.SetDefault("rate_limit.enabled", true)
+	viper.SetDefault("rate_limit.window", "1m")
+	viper.SetDefault("rate_limit.per_key", 600)
+	viper.SetDefault("rate_limit.per_user", 30)
+	viper.SetDefault("rate_limit.routes", map[string]int{
+		"post /v1/push/send-bulk":    60,
+		"post /v1/push/send-segment": 10,
+	})
+
+	viper.SetDefault("idempotency.ttl", "24h")
+	viper.SetDefault("idempotency.lock_timeout", "2m")
+
+	viper.SetDefault("templates.missing_variables", "error")
+	viper.SetDefault("templates.escape", "none")
+	viper.SetDefault("templates.cache_ttl", "1m")
+
+	viper.SetDefault("quiet_hours.enabled", true)
+	viper.SetDefault("quiet_hours.default_timezone", "UTC")
+	viper.SetDefault("quiet_hours.bypass_categories", []string{"transactional", "security"})
+	viper.SetDefault("quiet_hours.poll_interval", "30s")
+	viper.SetDefault("quiet_hours.batch_size", 100)
 
 	viper.SetDefault("log.level", "info")
 	viper.SetDefault("log.format", "json")
+	viper.SetDefault("log.redaction.tokens", "mask")
+	viper.SetDefault("log.redaction.content", false)
+	viper.SetDefault("log.redaction.data_keys", []string{})
+
+	viper.SetDefault("metrics.enabled", true)
+
+	viper.SetDefault("tracing.enabled", false)
+	viper.SetDefault("tracing.exporter", "otlp")
+	viper.SetDefault("tracing.endpoint", "localhost:4317")
+	viper.SetDefault("tracing.insecure", true)
+	viper.SetDefault("tracing.file", "traces.jsonl")
+	viper.SetDefault("tracing.service_name", "push-service")
+	viper.SetDefault("tracing.sample_ratio", 1.0)
+
+	viper.SetDefault("health.check_timeout", "2s")
+	viper.SetDefault("health.stall_timeout", "60s")
+
+	viper.SetDefault("admin.pprof_enabled", false)
 }
 
 func bindEnvVars() {
@@ -169,6 +386,10 @@ func bindEnvVars() {
 	viper.BindEnv("server.mode", "SERVER_MODE")
 	viper.BindEnv("server.shutdown_timeout", "SERVER_SHUTDOWN_TIMEOUT")
 
+	// gRPC
+	viper.BindEnv("grpc.enabled", "GRPC_ENABLED")
+	viper.BindEnv("grpc.port", "GRPC_PORT")
+
 	// Database
 	viper.BindEnv("database.host", "DB_HOST")
 	viper.BindEnv("database.port", "DB_PORT")
@@ -181,6 +402,7 @@ func bindEnvVars() {
 	viper.BindEnv("database.conn_max_lifetime", "DB_CONN_MAX_LIFETIME")
 
 	// Redis
+	viper.BindEnv("redis.enabled", "REDIS_ENABLED")
 	viper.BindEnv("redis.host", "REDIS_HOST")
 	viper.BindEnv("redis.port", "REDIS_PORT")
 	viper.BindEnv("redis.password", "REDIS_PASSWORD")
@@ -201,6 +423,52 @@ func bindEnvVars() {
 	viper.BindEnv("queue.retry.backoff", "QUEUE_RETRY_BACKOFF")
 	viper.BindEnv("queue.validation.enabled", "QUEUE_VALIDATION_ENABLED")
 	viper.BindEnv("queue.validation.timeout", "QUEUE_VALIDATION_TIMEOUT")
+	viper.BindEnv("queue.segment.page_size", "QUEUE_SEGMENT_PAGE_SIZE")
+	viper.BindEnv("queue.sync.timeout", "QUEUE_SYNC_TIMEOUT")
+	viper.BindEnv("queue.sync.max_concurrency", "QUEUE_SYNC_MAX_CONCURRENCY")
+
+	// Devices
+	viper.BindEnv("devices.batch_max_size", "DEVICES_BATCH_MAX_SIZE")
+
+	// Cleanup
+	viper.BindEnv("cleanup.enabled", "CLEANUP_ENABLED")
+	viper.BindEnv("cleanup.interval", "CLEANUP_INTERVAL")
+	viper.BindEnv("cleanup.deactivate_after_days", "CLEANUP_DEACTIVATE_AFTER_DAYS")
+	viper.BindEnv("cleanup.delete_inactive_after_days", "CLEANUP_DELETE_INACTIVE_AFTER_DAYS")
+	viper.BindEnv("cleanup.history_retention_days", "CLEANUP_HISTORY_RETENTION_DAYS")
+
+	// Auth
+	viper.BindEnv("auth.enabled", "AUTH_ENABLED")
+	viper.BindEnv("auth.bootstrap_key", "AUTH_BOOTSTRAP_KEY")
+	viper.BindEnv("auth.jwt.enabled", "AUTH_JWT_ENABLED")
+	viper.BindEnv("auth.jwt.hmac_secret", "AUTH_JWT_HMAC_SECRET")
+	viper.BindEnv("auth.jwt.jwks_file", "AUTH_JWT_JWKS_FILE")
+	viper.BindEnv("auth.jwt.issuer", "AUTH_JWT_ISSUER")
+	viper.BindEnv("auth.jwt.audience", "AUTH_JWT_AUDIENCE")
+	viper.BindEnv("auth.jwt.user_id_claim", "AUTH_JWT_USER_ID_CLAIM")
+	viper.BindEnv("auth.jwt.leeway", "AUTH_JWT_LEEWAY")
+
+	// Rate limiting
+	viper.BindEnv("rate_limit.enabled", "RATE_LIMIT_ENABLED")
+	viper.BindEnv("rate_limit.window", "RATE_LIMIT_WINDOW")
+	viper.BindEnv("rate_limit.per_key", "RATE_LIMIT_PER_KEY")
+	viper.BindEnv("rate_limit.per_user", "RATE_LIMIT_PER_USER")
+
+	// Idempotency
+	viper.BindEnv("idempotency.ttl", "IDEMPOTENCY_TTL")
+	viper.BindEnv("idempotency.lock_timeout", "IDEMPOTENCY_LOCK_TIMEOUT")
+
+	// Templates
+	viper.BindEnv("templates.missing_variables", "TEMPLATES_MISSING_VARIABLES")
+	viper.BindEnv("templates.escape", "TEMPLATES_ESCAPE")
+	viper.BindEnv("templates.cache_ttl", "TEMPLATES_CACHE_TTL")
+
+	// Quiet hours
+	viper.BindEnv("quiet_hours.enabled", "QUIET_HOURS_ENABLED")
+	viper.BindEnv("quiet_hours.default_timezone", "QUIET_HOURS_DEFAULT_TIMEZONE")
+	viper.BindEnv("quiet_hours.bypass_categories", "QUIET_HOURS_BYPASS_CATEGORIES")
+	viper.BindEnv("quiet_hours.poll_interval", "QUIET_HOURS_POLL_INTERVAL")
+	viper.BindEnv("quiet_hours.batch_size", "QUIET_HOURS_BATCH_SIZE")
 
 	// FCM
 	viper.BindEnv("fcm.credentials_json", "FCM_CREDENTIALS_JSON")
@@ -210,6 +478,28 @@ func bindEnvVars() {
 	// Log
 	viper.BindEnv("log.level", "LOG_LEVEL")
 	viper.BindEnv("log.format", "LOG_FORMAT")
+	viper.BindEnv("log.redaction.tokens", "LOG_REDACTION_TOKENS")
+	viper.BindEnv("log.redaction.content", "LOG_REDACTION_CONTENT")
+	viper.BindEnv("log.redaction.data_keys", "LOG_REDACTION_DATA_KEYS")
+
+	// Metrics
+	viper.BindEnv("metrics.enabled", "METRICS_ENABLED")
+
+	// Tracing
+	viper.BindEnv("tracing.enabled", "TRACING_ENABLED")
+	viper.BindEnv("tracing.exporter", "TRACING_EXPORTER")
+	viper.BindEnv("tracing.endpoint", "TRACING_ENDPOINT")
+	viper.BindEnv("tracing.insecure", "TRACING_INSECURE")
+	viper.BindEnv("tracing.file", "TRACING_FILE")
+	viper.BindEnv("tracing.service_name", "TRACING_SERVICE_NAME")
+	viper.BindEnv("tracing.sample_ratio", "TRACING_SAMPLE_RATIO")
+
+	// Health
+	viper.BindEnv("health.check_timeout", "HEALTH_CHECK_TIMEOUT")
+	viper.BindEnv("health.stall_timeout", "HEALTH_STALL_TIMEOUT")
+
+	// Admin
+	viper.BindEnv("admin.pprof_enabled", "ADMIN_PPROF_ENABLED")
 }
 
 // GetDatabaseURL builds the database connection URL
@@ -261,6 +551,41 @@ func validateConfig(config *Config) error {
 	if config.FCM.CredentialsJSON == "" {
 		return fmt.Errorf("FCM credentials are required")
 	}
+	if config.Auth.JWT.Enabled && config.Auth.JWT.HMACSecret == "" && config.Auth.JWT.JWKSFile == "" {
+		return fmt.Errorf("JWT auth requires an HMAC secret or a JWKS file")
+	}
+	templateOptions := templating.Options{
+		Missing: config.Templates.MissingVariables,
+		Escape:  config.Templates.Escape,
+	}
+	if err := templateOptions.Validate(); err != nil {
+		return fmt.Errorf("invalid templates config: %w", err)
+	}
+	if config.Idempotency.LockTimeout < 0 {
+		return fmt.Errorf("idempotency lock timeout must not be negative")
+	}
+	if config.Templates.CacheTTL < 0 {
+		return fmt.Errorf("template cache TTL must not be negative")
+	}
+	if _, err := time.LoadLocation(config.QuietHours.DefaultTimezone); err != nil {
+		return fmt.Errorf("invalid quiet hours default timezone: %w", err)
+	}
+	if config.QuietHours.BatchSize < 0 {
+		return fmt.Errorf("quiet hours batch size must not be negative")
+	}
+	if config.RateLimit.Enabled {
+		if config.RateLimit.Window <= 0 {
+			return fmt.Errorf("rate limit window must be positive")
+		}
+		if config.RateLimit.PerKey < 0 || config.RateLimit.PerUser < 0 {
+			return fmt.Errorf("rate limits must not be negative")
+		}
+		for route, limit := range config.RateLimit.Routes {
+			if limit < 0 {
+				return fmt.Errorf("rate limit for route %q must not be negative", route)
+			}
+		}
+	}
 
 	return nil
 }
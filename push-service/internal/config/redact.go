@@ -0,0 +1,44 @@
+package config
+
+import (
+	"reflect"
+	"time"
+)
+
+// redactedValue replaces secrets in Redacted output
+const redactedValue = "[REDACTED]"
+
+// Redacted returns the configuration keyed by its config file names, with
+// fields tagged redact:"true" masked. Unset secrets stay empty so it is
+// still visible whether one was provided.
+func (c *Config) Redacted() map[string]any {
+	return redactStruct(reflect.ValueOf(c).Elem())
+}
+
+func redactStruct(v reflect.Value) map[string]any {
+	out := make(map[string]any, v.NumField())
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		key := field.Tag.Get("mapstructure")
+		if key == "" {
+			key = field.Name
+		}
+		value := v.Field(i)
+
+		switch {
+		case field.Tag.Get("redact") == "true":
+			if value.IsZero() {
+				out[key] = ""
+			} else {
+				out[key] = redactedValue
+			}
+		case value.Type() == reflect.TypeOf(time.Duration(0)):
+			out[key] = time.Duration(value.Int()).String()
+		case value.Kind() == reflect.Struct:
+			out[key] = redactStruct(value)
+		default:
+			out[key] = value.Interface()
+		}
+	}
+	return out
+}
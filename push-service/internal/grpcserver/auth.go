@@ -0,0 +1,111 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"push-service/internal/config"
+	"push-service/internal/models"
+	"push-service/internal/service"
+	pushv1 "push-service/proto/push/v1"
+	"strings"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// methodScopes lists the scope each RPC requires. Methods not listed, such as
+// server reflection, require the admin scope.
+var methodScopes = map[string]string{
+	pushv1.DeviceService_RegisterDevice_FullMethodName:        models.ScopeDevicesWrite,
+	pushv1.DeviceService_UnregisterDevice_FullMethodName:      models.ScopeDevicesWrite,
+	pushv1.DeviceService_ListUserDevices_FullMethodName:       models.ScopeDevicesRead,
+	pushv1.PushService_SendPush_FullMethodName:                models.ScopePushSend,
+	pushv1.PushService_GetNotificationStatus_FullMethodName:   models.ScopePushSend,
+	pushv1.PushService_WatchNotificationStatus_FullMethodName: models.ScopePushSend,
+	pushv1.PushService_SendBulkPush_FullMethodName:            models.ScopePushBulk,
+	pushv1.PushService_SendSegmentPush_FullMethodName:         models.ScopePushBulk,
+	pushv1.PushService_GetQueueStats_FullMethodName:           models.ScopeAdmin,
+}
+
+var (
+	errAPIKeyRequired    = service.NewError(service.KindUnauthorized, "api_key_required", "API key is required")
+	errInsufficientScope = service.NewError(service.KindForbidden, "insufficient_scope", "Insufficient scope")
+	errAuthUnavailable   = service.NewError(service.KindUnavailable, "auth_unavailable", "Authentication unavailable")
+)
+
+// authenticator checks API keys sent as x-api-key or "authorization: Bearer"
+// metadata, mirroring the HTTP middleware
+type authenticator struct {
+	apiKeyService service.APIKeyService
+	cfg           *config.AuthConfig
+}
+
+func newAuthenticator(apiKeyService service.APIKeyService, cfg *config.AuthConfig) *authenticator {
+	return &authenticator{apiKeyService: apiKeyService, cfg: cfg}
+}
+
+func (a *authenticator) unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if err := a.authenticate(ctx, info.FullMethod); err != nil {
+		return nil, toStatus(err)
+	}
+	return handler(ctx, req)
+}
+
+func (a *authenticator) stream(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := a.authenticate(stream.Context(), info.FullMethod); err != nil {
+		return toStatus(err)
+	}
+	return handler(srv, stream)
+}
+
+func (a *authenticator) authenticate(ctx context.Context, method string) error {
+	if !a.cfg.Enabled {
+		return nil
+	}
+
+	rawKey := apiKeyFromMetadata(ctx)
+	if rawKey == "" {
+		return errAPIKeyRequired
+	}
+
+	key, err := a.apiKeyService.Authenticate(ctx, rawKey)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidAPIKey) {
+			return err
+		}
+		return errAuthUnavailable.Wrap(err)
+	}
+
+	scope, ok := methodScopes[method]
+	if !ok {
+		scope = models.ScopeAdmin
+	}
+	if !key.HasScope(scope) {
+		zap.L().Warn("API key missing required scope",
+			zap.String("key_id", key.ID),
+			zap.String("method", method),
+			zap.String("required_scope", scope),
+		)
+		return errInsufficientScope.WithDetails(map[string]string{"required_scope": scope})
+	}
+
+	return nil
+}
+
+// apiKeyFromMetadata extracts the raw key from x-api-key or authorization metadata
+func apiKeyFromMetadata(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if values := md.Get("x-api-key"); len(values) > 0 && values[0] != "" {
+		return values[0]
+	}
+	if values := md.Get("authorization"); len(values) > 0 {
+		if auth := values[0]; len(auth) > 7 && strings.EqualFold(auth[:7], "Bearer ") {
+			return strings.TrimSpace(auth[7:])
+		}
+	}
+	return ""
+}
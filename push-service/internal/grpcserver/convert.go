@@ -0,0 +1,92 @@
+package grpcserver
+
+import (
+	"push-service/internal/models"
+	pushv1 "push-service/proto/push/v1"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var platformNames = map[pushv1.Platform]string{
+	pushv1.Platform_PLATFORM_IOS:     "ios",
+	pushv1.Platform_PLATFORM_ANDROID: "android",
+	pushv1.Platform_PLATFORM_WEB:     "web",
+}
+
+var notificationStates = map[string]pushv1.NotificationState{
+	models.NotificationStatusQueued:     pushv1.NotificationState_NOTIFICATION_STATE_QUEUED,
+	models.NotificationStatusSending:    pushv1.NotificationState_NOTIFICATION_STATE_SENDING,
+	models.NotificationStatusRetrying:   pushv1.NotificationState_NOTIFICATION_STATE_RETRYING,
+	models.NotificationStatusSent:       pushv1.NotificationState_NOTIFICATION_STATE_SENT,
+	models.NotificationStatusFailed:     pushv1.NotificationState_NOTIFICATION_STATE_FAILED,
+	models.NotificationStatusSuppressed: pushv1.NotificationState_NOTIFICATION_STATE_SUPPRESSED,
+	models.NotificationStatusDeferred:   pushv1.NotificationState_NOTIFICATION_STATE_DEFERRED,
+}
+
+// platformName returns the model platform for p, or "" when unspecified or unknown
+func platformName(p pushv1.Platform) string {
+	return platformNames[p]
+}
+
+func platformFromName(name string) pushv1.Platform {
+	for p, n := range platformNames {
+		if n == name {
+			return p
+		}
+	}
+	return pushv1.Platform_PLATFORM_UNSPECIFIED
+}
+
+// dataMap converts protobuf string data to the map the service layer expects
+func dataMap(data map[string]string) map[string]any {
+	if len(data) == 0 {
+		return nil
+	}
+	result := make(map[string]any, len(data))
+	for k, v := range data {
+		result[k] = v
+	}
+	return result
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func deviceToProto(d *models.DeviceResponse) *pushv1.Device {
+	return &pushv1.Device{
+		Id:       d.ID,
+		UserId:   d.UserID,
+		App:      d.App,
+		Token:    d.Token,
+		Platform: platformFromName(d.Platform),
+		IsActive: d.IsActive,
+		Metadata: d.Metadata,
+		Tags:     d.Tags,
+	}
+}
+
+func notificationStatusToProto(s *models.NotificationStatus) *pushv1.NotificationStatus {
+	status := &pushv1.NotificationStatus{
+		NotificationId:  s.ID,
+		UserId:          s.UserID,
+		Category:        s.Category,
+		State:           notificationStates[s.Status],
+		DeviceCount:     int32(s.DeviceCount),
+		SuccessCount:    int32(s.SuccessCount),
+		FailureCount:    int32(s.FailureCount),
+		SuppressedCount: int32(s.SuppressedCount),
+		CreatedAt:       timestamppb.New(s.CreatedAt),
+		UpdatedAt:       timestamppb.New(s.UpdatedAt),
+	}
+	if s.ErrorMessage != nil {
+		status.ErrorMessage = *s.ErrorMessage
+	}
+	if s.SentAt != nil {
+		status.SentAt = timestamppb.New(*s.SentAt)
+	}
+	return status
+}
@@ -0,0 +1,68 @@
+package grpcserver
+
+import (
+	"context"
+	"push-service/internal/models"
+	"push-service/internal/service"
+	pushv1 "push-service/proto/push/v1"
+)
+
+type deviceServer struct {
+	pushv1.UnimplementedDeviceServiceServer
+	deviceService service.DeviceService
+}
+
+func (s *deviceServer) RegisterDevice(ctx context.Context, req *pushv1.RegisterDeviceRequest) (*pushv1.RegisterDeviceResponse, error) {
+	platform := platformName(req.GetPlatform())
+	switch {
+	case req.GetUserId() == "":
+		return nil, toStatus(service.ErrInvalidRequest.WithDetails("user_id is required"))
+	case req.GetToken() == "":
+		return nil, toStatus(service.ErrInvalidRequest.WithDetails("token is required"))
+	case platform == "":
+		return nil, toStatus(service.ErrInvalidRequest.WithDetails("platform must be one of ios, android, web"))
+	}
+
+	device, err := s.deviceService.RegisterDevice(ctx, models.CreateDeviceRequest{
+		UserID:   req.GetUserId(),
+		App:      req.GetApp(),
+		Token:    req.GetToken(),
+		Platform: platform,
+		Metadata: req.GetMetadata(),
+		Tags:     req.GetTags(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &pushv1.RegisterDeviceResponse{Device: deviceToProto(device)}, nil
+}
+
+func (s *deviceServer) UnregisterDevice(ctx context.Context, req *pushv1.UnregisterDeviceRequest) (*pushv1.UnregisterDeviceResponse, error) {
+	if req.GetToken() == "" {
+		return nil, toStatus(service.ErrInvalidRequest.WithDetails("token is required"))
+	}
+
+	if err := s.deviceService.UnregisterDevice(ctx, req.GetApp(), req.GetToken()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &pushv1.UnregisterDeviceResponse{}, nil
+}
+
+func (s *deviceServer) ListUserDevices(ctx context.Context, req *pushv1.ListUserDevicesRequest) (*pushv1.ListUserDevicesResponse, error) {
+	if req.GetUserId() == "" {
+		return nil, toStatus(service.ErrInvalidRequest.WithDetails("user_id is required"))
+	}
+
+	devices, err := s.deviceService.GetUserDevices(ctx, req.GetUserId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &pushv1.ListUserDevicesResponse{Devices: make([]*pushv1.Device, 0, len(devices))}
+	for i := range devices {
+		resp.Devices = append(resp.Devices, deviceToProto(&devices[i]))
+	}
+	return resp, nil
+}
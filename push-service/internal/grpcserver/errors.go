@@ -0,0 +1,68 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"push-service/internal/service"
+
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorDomain identifies this service in ErrorInfo details
+const errorDomain = "push-service"
+
+// statusCode maps an error kind to its gRPC code
+func statusCode(kind service.Kind) codes.Code {
+	switch kind {
+	case service.KindInvalid, service.KindUnprocessable:
+		return codes.InvalidArgument
+	case service.KindUnauthorized:
+		return codes.Unauthenticated
+	case service.KindForbidden:
+		return codes.PermissionDenied
+	case service.KindNotFound:
+		return codes.NotFound
+	case service.KindConflict:
+		return codes.Aborted
+	case service.KindRateLimited:
+		return codes.ResourceExhausted
+	case service.KindUnavailable:
+		return codes.Unavailable
+	default:
+		return codes.Internal
+	}
+}
+
+// toStatus converts err to a gRPC status. Domain errors keep their code as the
+// ErrorInfo reason; any other error is logged and reported as Internal.
+func toStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+
+	var domainErr *service.Error
+	if !errors.As(err, &domainErr) {
+		domainErr = service.ErrInternal
+	}
+
+	code := statusCode(domainErr.Kind)
+	if code == codes.Internal || code == codes.Unavailable {
+		zap.L().Error("gRPC request failed", zap.String("code", domainErr.Code), zap.Error(err))
+	}
+
+	st := status.New(code, domainErr.Message)
+	info := &errdetails.ErrorInfo{Reason: domainErr.Code, Domain: errorDomain}
+	if detailed, detailsErr := st.WithDetails(info); detailsErr == nil {
+		st = detailed
+	}
+	return st.Err()
+}
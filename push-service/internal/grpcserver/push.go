@@ -0,0 +1,180 @@
+package grpcserver
+
+import (
+	"context"
+	"push-service/internal/models"
+	"push-service/internal/service"
+	pushv1 "push-service/proto/push/v1"
+	"slices"
+	"strings"
+)
+
+type pushServer struct {
+	pushv1.UnimplementedPushServiceServer
+	pushService         service.PushService
+	notificationService service.NotificationService
+}
+
+func (s *pushServer) SendPush(ctx context.Context, req *pushv1.SendPushRequest) (*pushv1.SendPushResponse, error) {
+	if err := requireContent(req.GetTitle(), req.GetBody()); err != nil {
+		return nil, toStatus(err)
+	}
+	if req.GetUserId() == "" {
+		return nil, toStatus(service.ErrInvalidRequest.WithDetails("user_id is required"))
+	}
+	if err := validCategory(req.GetCategory()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	pushReq := models.SendPushRequest{
+		UserID:   req.GetUserId(),
+		Title:    req.GetTitle(),
+		Body:     req.GetBody(),
+		Image:    optionalString(req.GetImage()),
+		Link:     optionalString(req.GetLink()),
+		Data:     dataMap(req.GetData()),
+		Category: req.GetCategory(),
+		Sync:     req.GetSync(),
+	}
+	for _, p := range req.GetPlatforms() {
+		name := platformName(p)
+		if name == "" {
+			return nil, toStatus(service.ErrInvalidRequest.WithDetails("platforms must be ios, android or web"))
+		}
+		pushReq.Platforms = append(pushReq.Platforms, name)
+	}
+
+	if !pushReq.Sync {
+		notificationID, err := s.pushService.SendPush(ctx, pushReq)
+		if err != nil {
+			return nil, toStatus(err)
+		}
+		return &pushv1.SendPushResponse{NotificationId: notificationID}, nil
+	}
+
+	result, err := s.pushService.SendPushSync(ctx, pushReq)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &pushv1.SendPushResponse{
+		NotificationId:  result.NotificationID,
+		SuccessCount:    int32(result.SuccessCount),
+		FailureCount:    int32(result.FailureCount),
+		SuppressedCount: int32(result.SuppressedCount),
+		Results:         make([]*pushv1.DeviceSendResult, 0, len(result.Results)),
+	}
+	for _, r := range result.Results {
+		resp.Results = append(resp.Results, &pushv1.DeviceSendResult{
+			DeviceId:   r.DeviceID,
+			Platform:   platformFromName(r.Platform),
+			Token:      r.Token,
+			Success:    r.Success,
+			MessageId:  r.MessageID,
+			ErrorCode:  r.ErrorCode,
+			Error:      r.Error,
+			Suppressed: r.Suppressed,
+		})
+	}
+	return resp, nil
+}
+
+func (s *pushServer) SendBulkPush(ctx context.Context, req *pushv1.SendBulkPushRequest) (*pushv1.SendBulkPushResponse, error) {
+	if err := requireContent(req.GetTitle(), req.GetBody()); err != nil {
+		return nil, toStatus(err)
+	}
+	if len(req.GetUserIds()) == 0 {
+		return nil, toStatus(service.ErrInvalidRequest.WithDetails("user_ids is required"))
+	}
+	if err := validCategory(req.GetCategory()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	err := s.pushService.SendBulkPush(ctx, models.BulkPushRequest{
+		UserIDs:  req.GetUserIds(),
+		Title:    req.GetTitle(),
+		Body:     req.GetBody(),
+		Data:     dataMap(req.GetData()),
+		Category: req.GetCategory(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &pushv1.SendBulkPushResponse{UserCount: int32(len(req.GetUserIds()))}, nil
+}
+
+func (s *pushServer) SendSegmentPush(ctx context.Context, req *pushv1.SendSegmentPushRequest) (*pushv1.SendSegmentPushResponse, error) {
+	if err := requireContent(req.GetTitle(), req.GetBody()); err != nil {
+		return nil, toStatus(err)
+	}
+	if req.GetSegment() == "" {
+		return nil, toStatus(service.ErrInvalidRequest.WithDetails("segment is required"))
+	}
+	if err := validCategory(req.GetCategory()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	result, err := s.pushService.SendSegmentPush(ctx, models.SendSegmentPushRequest{
+		Segment:  req.GetSegment(),
+		Title:    req.GetTitle(),
+		Body:     req.GetBody(),
+		Image:    optionalString(req.GetImage()),
+		Link:     optionalString(req.GetLink()),
+		Data:     dataMap(req.GetData()),
+		Category: req.GetCategory(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &pushv1.SendSegmentPushResponse{
+		Segment:       result.Segment,
+		DeviceCount:   int32(result.DeviceCount),
+		EnqueuedCount: int32(result.EnqueuedCount),
+	}, nil
+}
+
+func (s *pushServer) GetNotificationStatus(ctx context.Context, req *pushv1.GetNotificationStatusRequest) (*pushv1.NotificationStatus, error) {
+	status, err := s.notificationService.GetStatus(ctx, req.GetNotificationId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return notificationStatusToProto(status), nil
+}
+
+// WatchNotificationStatus streams the notification's status whenever it
+// changes, ending once it reaches a final state or the client goes away
+func (s *pushServer) WatchNotificationStatus(req *pushv1.WatchNotificationStatusRequest, stream pushv1.PushService_WatchNotificationStatusServer) error {
+	err := s.notificationService.WatchStatus(stream.Context(), req.GetNotificationId(), func(status *models.NotificationStatus) error {
+		return stream.Send(notificationStatusToProto(status))
+	})
+	return toStatus(err)
+}
+
+func (s *pushServer) GetQueueStats(ctx context.Context, _ *pushv1.GetQueueStatsRequest) (*pushv1.GetQueueStatsResponse, error) {
+	stats, err := s.pushService.GetQueueStats(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pushv1.GetQueueStatsResponse{Queues: stats}, nil
+}
+
+func requireContent(title, body string) error {
+	if title == "" {
+		return service.ErrInvalidRequest.WithDetails("title is required")
+	}
+	if body == "" {
+		return service.ErrInvalidRequest.WithDetails("body is required")
+	}
+	return nil
+}
+
+// validCategory checks a notification category, which HTTP requests have
+// checked by binding. Empty means transactional.
+func validCategory(category string) error {
+	if category != "" && !slices.Contains(models.Categories, category) {
+		return service.ErrInvalidRequest.WithDetails("category must be one of " + strings.Join(models.Categories, ", "))
+	}
+	return nil
+}
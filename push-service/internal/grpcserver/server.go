@@ -0,0 +1,97 @@
+// Package grpcserver exposes the device and push services over gRPC, using
+// the same service layer as the HTTP handlers.
+package grpcserver
+
+import (
+	"context"
+	"push-service/internal/config"
+	"push-service/internal/service"
+	"push-service/pkg/logger"
+	pushv1 "push-service/proto/push/v1"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+// NewServer builds a gRPC server with the DeviceService and PushService APIs,
+// API key authentication, tracing and server reflection registered
+func NewServer(
+	deviceService service.DeviceService,
+	pushService service.PushService,
+	notificationService service.NotificationService,
+	apiKeyService service.APIKeyService,
+	authCfg *config.AuthConfig,
+) *grpc.Server {
+	auth := newAuthenticator(apiKeyService, authCfg)
+
+	server := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(logUnary, auth.unary),
+		grpc.ChainStreamInterceptor(logStream, auth.stream),
+	)
+
+	pushv1.RegisterDeviceServiceServer(server, &deviceServer{deviceService: deviceService})
+	pushv1.RegisterPushServiceServer(server, &pushServer{
+		pushService:         pushService,
+		notificationService: notificationService,
+	})
+	reflection.Register(server)
+
+	return server
+}
+
+// requestIDMetadataKey carries the caller's request ID, like X-Request-ID over HTTP
+const requestIDMetadataKey = "x-request-id"
+
+// withRequestID accepts the caller's request ID, or generates one, and stores
+// it in the context for logs and queued messages
+func withRequestID(ctx context.Context) context.Context {
+	var requestID string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(requestIDMetadataKey); len(values) > 0 {
+			requestID = values[0]
+		}
+	}
+	requestID = logger.RequestIDOrNew(requestID)
+	grpc.SetHeader(ctx, metadata.Pairs(requestIDMetadataKey, requestID))
+	return logger.WithRequestID(logger.WithComponent(ctx, logger.ComponentGRPC), requestID)
+}
+
+// requestStream overrides the context of a server stream
+type requestStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *requestStream) Context() context.Context {
+	return s.ctx
+}
+
+func logUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	ctx = withRequestID(ctx)
+	resp, err := handler(ctx, req)
+	logRequest(ctx, info.FullMethod, start, err)
+	return resp, err
+}
+
+func logStream(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	ctx := withRequestID(stream.Context())
+	err := handler(srv, &requestStream{ServerStream: stream, ctx: ctx})
+	logRequest(ctx, info.FullMethod, start, err)
+	return err
+}
+
+func logRequest(ctx context.Context, method string, start time.Time, err error) {
+	logger.FromContext(ctx).Info("gRPC request",
+		zap.String("method", method),
+		zap.String("code", status.Code(err).String()),
+		zap.Duration("duration", time.Since(start)),
+	)
+}
@@ -0,0 +1,253 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"push-service/internal/config"
+	"push-service/internal/models"
+	"push-service/internal/service"
+	"push-service/pkg/logger"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// CleanupStatusResponse represents the cleanup job status
+// @Description Stale device cleanup job status
+type CleanupStatusResponse struct {
+	Enabled    bool                  `json:"enabled" example:"true"`
+	Policy     models.CleanupPolicy  `json:"policy"`
+	LastResult *models.CleanupResult `json:"last_result,omitempty"`
+}
+
+// SetLogLevelRequest changes the global level, or one component's level
+// @Description Log level change. Without a component the global level is changed.
+type SetLogLevelRequest struct {
+	Level     string `json:"level" binding:"required" example:"debug"`
+	Component string `json:"component,omitempty" example:"fcm"`
+	// Duration after which the change is undone, such as "15m". Permanent when empty.
+	Duration string `json:"duration,omitempty" example:"15m"`
+}
+
+// CredentialReloadResult is the outcome of reloading one set of credentials
+// @Description Credential reload outcome
+type CredentialReloadResult struct {
+	Name   string `json:"name" example:"fcm"`
+	Status string `json:"status" example:"reloaded"`
+	Error  string `json:"error,omitempty" example:"failed to read JWKS file: no such file or directory"`
+}
+
+// Credential reload statuses
+const (
+	CredentialReloaded = "reloaded"
+	CredentialFailed   = "failed"
+)
+
+// CredentialReloader reloads one set of credentials from its source
+type CredentialReloader func(ctx context.Context) error
+
+type AdminHandler struct {
+	cleanupService service.CleanupService
+	cfg            *config.Config
+	credentials    map[string]CredentialReloader
+}
+
+func NewAdminHandler(cleanupService service.CleanupService, cfg *config.Config, credentials map[string]CredentialReloader) *AdminHandler {
+	return &AdminHandler{
+		cleanupService: cleanupService,
+		cfg:            cfg,
+		credentials:    credentials,
+	}
+}
+
+// GetCleanupStatus godoc
+// @Summary Get cleanup job status
+// @Description Get the stale device cleanup policy and the result of the last scheduled or manual run
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} CleanupStatusResponse
+// @Router /v1/admin/cleanup [get]
+func (h *AdminHandler) GetCleanupStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, CleanupStatusResponse{
+		Enabled:    h.cfg.Cleanup.Enabled,
+		Policy:     h.cleanupService.Policy(),
+		LastResult: h.cleanupService.LastResult(),
+	})
+}
+
+// RunCleanup godoc
+// @Summary Run cleanup job
+// @Description Run the stale device cleanup now. With dry_run=true the affected rows are counted but nothing is changed.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param dry_run query bool false "Only report what would be cleaned up"
+// @Success 200 {object} models.CleanupResult
+// @Failure 400 {object} models.ErrorResponse "Invalid dry_run value"
+// @Failure 500 {object} models.ErrorResponse "Cleanup failed"
+// @Router /v1/admin/cleanup/run [post]
+func (h *AdminHandler) RunCleanup(c *gin.Context) {
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		respondError(c, service.ErrInvalidRequest.WithDetails("dry_run must be a boolean"))
+		return
+	}
+
+	result, err := h.cleanupService.Run(c.Request.Context(), dryRun)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetLogLevel godoc
+// @Summary Get log levels
+// @Description Get the global log level and any component overrides, with their expiry times
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} logger.LevelStatus
+// @Router /v1/admin/log-level [get]
+func (h *AdminHandler) GetLogLevel(c *gin.Context) {
+	c.JSON(http.StatusOK, logger.Levels())
+}
+
+// SetLogLevel godoc
+// @Summary Set log level
+// @Description Change the global log level, or a component's level (http, grpc, worker, fcm, queue), without a restart. With a duration the change is undone once it elapses.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body SetLogLevelRequest true "Level change"
+// @Success 200 {object} logger.LevelStatus
+// @Failure 400 {object} models.ErrorResponse "Unknown level or component, or invalid duration"
+// @Router /v1/admin/log-level [put]
+func (h *AdminHandler) SetLogLevel(c *gin.Context) {
+	var req SetLogLevelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalidRequest(c, err)
+		return
+	}
+
+	level, err := logger.ParseLevel(req.Level)
+	if err != nil {
+		respondInvalidRequest(c, err)
+		return
+	}
+	if req.Component != "" && !logger.IsComponent(req.Component) {
+		respondError(c, service.ErrInvalidRequest.WithDetails(gin.H{
+			"message":    "unknown component",
+			"components": logger.Components,
+		}))
+		return
+	}
+	var ttl time.Duration
+	if req.Duration != "" {
+		ttl, err = time.ParseDuration(req.Duration)
+		if err != nil || ttl <= 0 {
+			respondError(c, service.ErrInvalidRequest.WithDetails("duration must be a positive duration such as 15m"))
+			return
+		}
+	}
+
+	if req.Component == "" {
+		logger.SetLevel(level, ttl)
+	} else {
+		logger.SetComponentLevel(req.Component, level, ttl)
+	}
+	logger.FromContext(c.Request.Context()).Warn("Log level changed",
+		zap.String("level", level.String()),
+		zap.String("component", req.Component),
+		zap.Duration("duration", ttl),
+	)
+
+	c.JSON(http.StatusOK, logger.Levels())
+}
+
+// ClearLogLevel godoc
+// @Summary Clear component log level
+// @Description Remove a component's level override so it follows the global level again
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param component path string true "Component" Enums(http, grpc, worker, fcm, queue)
+// @Success 200 {object} logger.LevelStatus
+// @Failure 400 {object} models.ErrorResponse "Unknown component"
+// @Router /v1/admin/log-level/{component} [delete]
+func (h *AdminHandler) ClearLogLevel(c *gin.Context) {
+	component := c.Param("component")
+	if !logger.IsComponent(component) {
+		respondError(c, service.ErrInvalidRequest.WithDetails(gin.H{
+			"message":    "unknown component",
+			"components": logger.Components,
+		}))
+		return
+	}
+
+	logger.ClearComponentLevel(component)
+	c.JSON(http.StatusOK, logger.Levels())
+}
+
+// GetConfig godoc
+// @Summary Get effective configuration
+// @Description Get the configuration the service is running with, after defaults, config file and environment are applied. Passwords, keys and credentials are redacted.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]interface{}
+// @Router /v1/admin/config [get]
+func (h *AdminHandler) GetConfig(c *gin.Context) {
+	c.JSON(http.StatusOK, h.cfg.Redacted())
+}
+
+// ReloadCredentials godoc
+// @Summary Reload credentials
+// @Description Reload the FCM service account and, when JWT auth is enabled, the JWKS file, so rotated credentials take effect without a restart. Credentials that fail to load keep their previous value.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} CredentialReloadResult
+// @Failure 503 {object} models.ErrorResponse "One or more credentials failed to reload; details lists every result"
+// @Router /v1/admin/credentials/reload [post]
+func (h *AdminHandler) ReloadCredentials(c *gin.Context) {
+	names := make([]string, 0, len(h.credentials))
+	for name := range h.credentials {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	log := logger.FromContext(c.Request.Context())
+	results := make([]CredentialReloadResult, 0, len(names))
+	failed := false
+	for _, name := range names {
+		result := CredentialReloadResult{Name: name, Status: CredentialReloaded}
+		if err := h.credentials[name](c.Request.Context()); err != nil {
+			failed = true
+			result.Status = CredentialFailed
+			result.Error = err.Error()
+			log.Error("Failed to reload credentials", zap.String("credentials", name), zap.Error(err))
+		} else {
+			log.Info("Credentials reloaded", zap.String("credentials", name))
+		}
+		results = append(results, result)
+	}
+
+	if failed {
+		respondError(c, service.ErrCredentialReloadFailed.WithDetails(results))
+		return
+	}
+	c.JSON(http.StatusOK, results)
+}
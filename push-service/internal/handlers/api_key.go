@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"net/http"
+	"push-service/internal/models"
+	"push-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type APIKeyHandler struct {
+	apiKeyService service.APIKeyService
+}
+
+func NewAPIKeyHandler(apiKeyService service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{apiKeyService: apiKeyService}
+}
+
+// CreateKey godoc
+// @Summary Create API key
+// @Description Create a scoped API key. The plaintext key is only returned in this response.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body models.CreateAPIKeyRequest true "API key request"
+// @Success 201 {object} models.CreatedAPIKey
+// @Failure 400 {object} models.ErrorResponse "Invalid request body or scope"
+// @Failure 500 {object} models.ErrorResponse "Failed to create API key"
+// @Router /v1/admin/keys [post]
+func (h *APIKeyHandler) CreateKey(c *gin.Context) {
+	var req models.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalidRequest(c, err)
+		return
+	}
+
+	key, err := h.apiKeyService.CreateKey(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, key)
+}
+
+// ListKeys godoc
+// @Summary List API keys
+// @Description List all API keys, including revoked and expired keys
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]interface{} "API keys"
+// @Failure 500 {object} models.ErrorResponse "Failed to list API keys"
+// @Router /v1/admin/keys [get]
+func (h *APIKeyHandler) ListKeys(c *gin.Context) {
+	keys, err := h.apiKeyService.ListKeys(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"keys":  keys,
+		"count": len(keys),
+	})
+}
+
+// RotateKey godoc
+// @Summary Rotate API key
+// @Description Issue a replacement key with the same name and scopes. The old key expires after the grace period.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "API key ID"
+// @Param request body models.RotateAPIKeyRequest false "Rotation options"
+// @Success 201 {object} models.CreatedAPIKey
+// @Failure 400 {object} models.ErrorResponse "Invalid request body"
+// @Failure 404 {object} models.ErrorResponse "API key not found (api_key_not_found)"
+// @Failure 500 {object} models.ErrorResponse "Failed to rotate API key"
+// @Router /v1/admin/keys/{id}/rotate [post]
+func (h *APIKeyHandler) RotateKey(c *gin.Context) {
+	var req models.RotateAPIKeyRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondInvalidRequest(c, err)
+			return
+		}
+		if req.GracePeriodSeconds < 0 {
+			respondError(c, service.ErrInvalidRequest.WithDetails("grace_period_seconds must not be negative"))
+			return
+		}
+	}
+
+	key, err := h.apiKeyService.RotateKey(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, key)
+}
+
+// RevokeKey godoc
+// @Summary Revoke API key
+// @Description Revoke an API key immediately
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "API key ID"
+// @Success 200 {object} map[string]string "API key revoked successfully"
+// @Failure 404 {object} models.ErrorResponse "API key not found (api_key_not_found)"
+// @Failure 500 {object} models.ErrorResponse "Failed to revoke API key"
+// @Router /v1/admin/keys/{id} [delete]
+func (h *APIKeyHandler) RevokeKey(c *gin.Context) {
+	if err := h.apiKeyService.RevokeKey(c.Request.Context(), c.Param("id")); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "API key revoked successfully"})
+}
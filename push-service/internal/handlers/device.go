@@ -1,9 +1,14 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
+	"push-service/internal/middleware"
 	"push-service/internal/models"
 	"push-service/internal/service"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -12,9 +17,11 @@ import (
 // RegisterDeviceRequest represents the device registration request
 // @Description Device registration request
 type RegisterDeviceRequest struct {
-	UserID   string `json:"user_id" binding:"required" example:"user123"`
-	Token    string `json:"token" binding:"required" example:"fcm_token_here"`
-	Platform string `json:"platform" binding:"required,oneof=ios android web" example:"android"`
+	UserID   string            `json:"user_id" binding:"required" example:"user123"`
+	App      string            `json:"app,omitempty" example:"default"`
+	Token    string            `json:"token" binding:"required" example:"fcm_token_here"`
+	Platform string            `json:"platform" binding:"required,oneof=ios android web" example:"android"`
+	Metadata map[string]string `json:"metadata,omitempty"`
 }
 
 // RegisterDeviceResponse represents the device registration response
@@ -32,6 +39,14 @@ type GetUserDevicesResponse struct {
 	Count   int                  `json:"count" example:"2"`
 }
 
+// SearchDevicesResponse represents a page of admin device search results
+// @Description Admin device search response
+type SearchDevicesResponse struct {
+	Devices    []models.Device `json:"devices"`
+	Count      int             `json:"count" example:"50"`
+	NextCursor string          `json:"next_cursor,omitempty" example:"eyJ2IjoiMjAyNS0wMS0wMVQwMDowMDowMFoiLCJpZCI6IjEyMyJ9"`
+}
+
 type DeviceHandler struct {
 	deviceService service.DeviceService
 }
@@ -40,29 +55,58 @@ func NewDeviceHandler(deviceService service.DeviceService) *DeviceHandler {
 	return &DeviceHandler{deviceService: deviceService}
 }
 
+// resolveUserID returns the user a device request acts on. End-user JWT callers
+// act on the user in their token and may not name another one; API key callers
+// must name the user. It writes the error response when ok is false.
+func resolveUserID(c *gin.Context, requested string) (userID string, ok bool) {
+	if tokenUserID, isUser := middleware.UserIDFromContext(c); isUser {
+		if requested != "" && requested != tokenUserID {
+			respondError(c, errUserMismatch)
+			return "", false
+		}
+		return tokenUserID, true
+	}
+
+	if requested == "" {
+		respondError(c, errUserIDRequired)
+		return "", false
+	}
+	return requested, true
+}
+
 // RegisterDevice godoc
 // @Summary Register a new device
-// @Description Register a device token for push notifications
+// @Description Register a device token for push notifications. End-user JWT callers may omit user_id.
 // @Tags devices
 // @Accept json
 // @Produce json
+// @Security ApiKeyAuth
+// @Security UserToken
 // @Param request body models.CreateDeviceRequest true "Device registration request"
 // @Success 201 {object} RegisterDeviceResponse
-// @Failure 400 {object} map[string]string "Invalid request body"
-// @Failure 500 {object} map[string]string "Failed to register device"
+// @Failure 400 {object} models.ErrorResponse "Invalid request body"
+// @Failure 401 {object} models.ErrorResponse "Invalid API key or user token"
+// @Failure 403 {object} models.ErrorResponse "user_id does not match the authenticated user"
+// @Failure 422 {object} models.ErrorResponse "Device token failed validation (invalid_device_token)"
+// @Failure 500 {object} models.ErrorResponse "Failed to register device"
 // @Router /v1/devices [post]
 func (h *DeviceHandler) RegisterDevice(c *gin.Context) {
 	var req models.CreateDeviceRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		zap.L().Warn("Invalid request body", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		respondInvalidRequest(c, err)
 		return
 	}
 
+	userID, ok := resolveUserID(c, req.UserID)
+	if !ok {
+		return
+	}
+	req.UserID = userID
+
 	device, err := h.deviceService.RegisterDevice(c.Request.Context(), req)
 	if err != nil {
-		zap.L().Error("Failed to register device", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to register device"})
+		respondError(c, err)
 		return
 	}
 
@@ -74,54 +118,203 @@ func (h *DeviceHandler) RegisterDevice(c *gin.Context) {
 
 // UnregisterDevice godoc
 // @Summary Unregister a device
-// @Description Unregister a device token (soft delete)
+// @Description Unregister a device token (soft delete). End-user JWT callers can only unregister their own devices.
 // @Tags devices
 // @Accept json
 // @Produce json
+// @Security ApiKeyAuth
+// @Security UserToken
 // @Param token path string true "Device token"
+// @Param app query string false "App the token is registered in (default: default)"
 // @Success 200 {object} map[string]string "Device unregistered successfully"
-// @Failure 400 {object} map[string]string "Device token is required"
-// @Failure 500 {object} map[string]string "Failed to unregister device"
+// @Failure 400 {object} models.ErrorResponse "Device token is required"
+// @Failure 401 {object} models.ErrorResponse "Invalid API key or user token"
+// @Failure 404 {object} models.ErrorResponse "Device not found (device_not_found)"
+// @Failure 500 {object} models.ErrorResponse "Failed to unregister device"
 // @Router /v1/devices/{token} [delete]
 func (h *DeviceHandler) UnregisterDevice(c *gin.Context) {
 	token := c.Param("token")
 	if token == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Device token is required"})
+		respondError(c, errDeviceTokenRequired)
 		return
 	}
 
-	err := h.deviceService.UnregisterDevice(c.Request.Context(), token)
+	var err error
+	if userID, isUser := middleware.UserIDFromContext(c); isUser {
+		err = h.deviceService.UnregisterUserDevice(c.Request.Context(), userID, c.Query("app"), token)
+	} else {
+		err = h.deviceService.UnregisterDevice(c.Request.Context(), c.Query("app"), token)
+	}
 	if err != nil {
-		zap.L().Error("Failed to unregister device", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unregister device"})
+		respondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Device unregistered successfully"})
 }
 
+// UnregisterUserDevices godoc
+// @Summary Unregister all of a user's devices
+// @Description Unregister (soft delete) every device of a user, for logout-all, password resets and revoked sessions. except_token keeps one device, usually the caller's own; platform limits the platforms. Notifications already queued for the removed devices are dropped. End-user JWT callers can only act on themselves.
+// @Tags devices
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security UserToken
+// @Param user_id path string true "User ID"
+// @Param except_token query string false "Device token to keep"
+// @Param platform query []string false "Only unregister these platforms" collectionFormat(multi)
+// @Success 200 {object} models.UnregisterUserDevicesResult
+// @Failure 400 {object} models.ErrorResponse "Invalid platform"
+// @Failure 401 {object} models.ErrorResponse "Invalid API key or user token"
+// @Failure 403 {object} models.ErrorResponse "user_id does not match the authenticated user"
+// @Failure 500 {object} models.ErrorResponse "Failed to unregister devices"
+// @Router /v1/users/{user_id}/devices [delete]
+func (h *DeviceHandler) UnregisterUserDevices(c *gin.Context) {
+	userID, ok := resolveUserID(c, c.Param("user_id"))
+	if !ok {
+		return
+	}
+
+	filter := models.UserDeviceFilter{ExceptToken: c.Query("except_token")}
+	for _, platform := range c.QueryArray("platform") {
+		switch platform {
+		case "ios", "android", "web":
+			filter.Platforms = append(filter.Platforms, platform)
+		default:
+			respondError(c, service.ErrInvalidRequest.WithDetails("platform must be one of ios, android, web"))
+			return
+		}
+	}
+
+	result, err := h.deviceService.UnregisterUserDevices(c.Request.Context(), userID, filter)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// RegisterDevices godoc
+// @Summary Register devices in bulk
+// @Description Register up to DEVICES_BATCH_MAX_SIZE devices with one upsert, for app migrations and imports. Tokens are not validated with FCM at registration. Each entry is reported separately; entries that fail validation or repeat an earlier app and token are skipped.
+// @Tags devices
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body models.BatchRegisterDevicesRequest true "Devices to register"
+// @Success 200 {object} models.BatchDeviceResponse
+// @Failure 400 {object} models.ErrorResponse "Invalid request body, empty batch or batch_too_large"
+// @Failure 500 {object} models.ErrorResponse "Failed to register devices"
+// @Router /v1/devices/batch [post]
+func (h *DeviceHandler) RegisterDevices(c *gin.Context) {
+	var req models.BatchRegisterDevicesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalidRequest(c, err)
+		return
+	}
+
+	resp, err := h.deviceService.RegisterDevices(c.Request.Context(), req.Devices)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// UnregisterDevices godoc
+// @Summary Unregister devices in bulk
+// @Description Unregister up to DEVICES_BATCH_MAX_SIZE device tokens (soft delete) in one statement. Tokens with no registered device are reported as device_not_found.
+// @Tags devices
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body models.BatchUnregisterDevicesRequest true "Device tokens to unregister"
+// @Success 200 {object} models.BatchDeviceResponse
+// @Failure 400 {object} models.ErrorResponse "Invalid request body, empty batch or batch_too_large"
+// @Failure 500 {object} models.ErrorResponse "Failed to unregister devices"
+// @Router /v1/devices/batch [delete]
+func (h *DeviceHandler) UnregisterDevices(c *gin.Context) {
+	var req models.BatchUnregisterDevicesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalidRequest(c, err)
+		return
+	}
+
+	resp, err := h.deviceService.UnregisterDevices(c.Request.Context(), req.Tokens)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// SetDeviceTags godoc
+// @Summary Set device tags
+// @Description Replace all tags on a device, used for segment targeting
+// @Tags devices
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param token path string true "Device token"
+// @Param app query string false "App the token is registered in (default: default)"
+// @Param request body models.SetDeviceTagsRequest true "Device tags"
+// @Success 200 {object} map[string]interface{} "Device tags updated successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid request body"
+// @Failure 404 {object} models.ErrorResponse "Device not found (device_not_found)"
+// @Failure 500 {object} models.ErrorResponse "Failed to set device tags"
+// @Router /v1/devices/{token}/tags [put]
+func (h *DeviceHandler) SetDeviceTags(c *gin.Context) {
+	token := c.Param("token")
+	if token == "" {
+		respondError(c, errDeviceTokenRequired)
+		return
+	}
+
+	var req models.SetDeviceTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalidRequest(c, err)
+		return
+	}
+
+	if err := h.deviceService.SetDeviceTags(c.Request.Context(), c.Query("app"), token, req.Tags); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Device tags updated successfully",
+		"tags":    req.Tags,
+	})
+}
+
 // GetUserDevices godoc
 // @Summary Get user devices
-// @Description Get all registered devices for a user
+// @Description Get all registered devices for a user. End-user JWT callers get their own devices and may omit user_id.
 // @Tags devices
 // @Accept json
 // @Produce json
-// @Param user_id query string true "User ID"
+// @Security ApiKeyAuth
+// @Security UserToken
+// @Param user_id query string false "User ID (required for API key callers)"
 // @Success 200 {object} GetUserDevicesResponse
-// @Failure 400 {object} map[string]string "User ID is required"
-// @Failure 500 {object} map[string]string "Failed to get user devices"
+// @Failure 400 {object} models.ErrorResponse "User ID is required"
+// @Failure 401 {object} models.ErrorResponse "Invalid API key or user token"
+// @Failure 403 {object} models.ErrorResponse "user_id does not match the authenticated user"
+// @Failure 500 {object} models.ErrorResponse "Failed to get user devices"
 // @Router /v1/devices [get]
 func (h *DeviceHandler) GetUserDevices(c *gin.Context) {
-	userID := c.Query("user_id")
-	if userID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User ID is required"})
+	userID, ok := resolveUserID(c, c.Query("user_id"))
+	if !ok {
 		return
 	}
 
 	devices, err := h.deviceService.GetUserDevices(c.Request.Context(), userID)
 	if err != nil {
-		zap.L().Error("Failed to get user devices", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get user devices"})
+		respondError(c, err)
 		return
 	}
 
@@ -130,4 +323,129 @@ func (h *DeviceHandler) GetUserDevices(c *gin.Context) {
 		"devices": devices,
 		"count":   len(devices),
 	})
-}
\ No newline at end of file
+}
+
+// SearchDevices godoc
+// @Summary Search devices
+// @Description Search all devices with filters, sorting and keyset pagination (admin)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param user_id query string false "User ID"
+// @Param platform query string false "Platform" Enums(ios, android, web)
+// @Param app query string false "App"
+// @Param active query bool false "Active flag"
+// @Param created_after query string false "Created at or after (RFC3339)"
+// @Param created_before query string false "Created before (RFC3339)"
+// @Param last_seen_after query string false "Last seen at or after (RFC3339)"
+// @Param last_seen_before query string false "Last seen before (RFC3339)"
+// @Param meta query []string false "Metadata filter as key:op:value, op one of eq, ne, lt, lte, gt, gte (e.g. app_version:lt:3.2)" collectionFormat(multi)
+// @Param sort query string false "Sort column" Enums(created_at, last_seen_at)
+// @Param order query string false "Sort order" Enums(asc, desc)
+// @Param cursor query string false "Cursor from the previous page"
+// @Param limit query int false "Page size (default 50, max 500)"
+// @Success 200 {object} SearchDevicesResponse
+// @Failure 400 {object} models.ErrorResponse "Invalid query (invalid_request) or cursor (invalid_cursor)"
+// @Failure 500 {object} models.ErrorResponse "Failed to search devices"
+// @Router /v1/admin/devices [get]
+func (h *DeviceHandler) SearchDevices(c *gin.Context) {
+	query, err := parseDeviceQuery(c)
+	if err != nil {
+		respondInvalidRequest(c, err)
+		return
+	}
+
+	page, err := h.deviceService.SearchDevices(c.Request.Context(), *query)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SearchDevicesResponse{
+		Devices:    page.Devices,
+		Count:      len(page.Devices),
+		NextCursor: page.NextCursor,
+	})
+}
+
+// parseDeviceQuery reads admin device search parameters from the query string
+func parseDeviceQuery(c *gin.Context) (*models.DeviceQuery, error) {
+	query := &models.DeviceQuery{
+		UserID:   c.Query("user_id"),
+		Platform: c.Query("platform"),
+		App:      c.Query("app"),
+		Cursor:   c.Query("cursor"),
+	}
+
+	switch query.Platform {
+	case "", "ios", "android", "web":
+	default:
+		return nil, fmt.Errorf("platform must be one of ios, android, web")
+	}
+
+	if active := c.Query("active"); active != "" {
+		isActive, err := strconv.ParseBool(active)
+		if err != nil {
+			return nil, fmt.Errorf("active must be a boolean")
+		}
+		query.IsActive = &isActive
+	}
+
+	timeParams := map[string]**time.Time{
+		"created_after":    &query.CreatedAfter,
+		"created_before":   &query.CreatedBefore,
+		"last_seen_after":  &query.LastSeenAfter,
+		"last_seen_before": &query.LastSeenBefore,
+	}
+	for name, target := range timeParams {
+		value := c.Query(name)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be an RFC3339 timestamp", name)
+		}
+		*target = &t
+	}
+
+	for _, meta := range c.QueryArray("meta") {
+		parts := strings.SplitN(meta, ":", 3)
+		if len(parts) != 3 || parts[0] == "" {
+			return nil, fmt.Errorf("meta must have the form key:op:value")
+		}
+		switch parts[1] {
+		case models.MetadataOpEq, models.MetadataOpNe, models.MetadataOpLt,
+			models.MetadataOpLte, models.MetadataOpGt, models.MetadataOpGte:
+		default:
+			return nil, fmt.Errorf("unsupported metadata operator: %s", parts[1])
+		}
+		query.Metadata = append(query.Metadata, models.MetadataFilter{Key: parts[0], Op: parts[1], Value: parts[2]})
+	}
+
+	switch sortBy := c.DefaultQuery("sort", models.DeviceSortCreatedAt); sortBy {
+	case models.DeviceSortCreatedAt, models.DeviceSortLastSeenAt:
+		query.SortBy = sortBy
+	default:
+		return nil, fmt.Errorf("sort must be created_at or last_seen_at")
+	}
+
+	switch order := c.DefaultQuery("order", "asc"); order {
+	case "asc":
+	case "desc":
+		query.Descending = true
+	default:
+		return nil, fmt.Errorf("order must be asc or desc")
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("limit must be a positive integer")
+		}
+		query.Limit = n
+	}
+
+	return query, nil
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"push-service/internal/config"
+	"push-service/internal/middleware"
+	"push-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+)
+
+const testHMACSecret = "0123456789abcdef0123456789abcdef"
+
+func userToken(t *testing.T, userID string) string {
+	t.Helper()
+
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.HS256, Key: []byte(testHMACSecret)}, (&jose.SignerOptions{}).WithType("JWT"))
+	if err != nil {
+		t.Fatalf("failed to create signer: %v", err)
+	}
+	raw, err := jwt.Signed(signer).Claims(jwt.Claims{
+		Subject: userID,
+		Expiry:  jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}).Serialize()
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return raw
+}
+
+func TestResolveUserID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cfg := &config.AuthConfig{
+		Enabled: true,
+		JWT:     config.JWTConfig{Enabled: true, HMACSecret: testHMACSecret, UserIDClaim: "sub"},
+	}
+	verifier, err := middleware.NewUserTokenVerifier(&cfg.JWT)
+	if err != nil {
+		t.Fatalf("failed to create verifier: %v", err)
+	}
+	auth := middleware.NewAuthenticator(nil, verifier, cfg)
+
+	router := gin.New()
+	router.GET("/devices", auth.RequireScopeOrUser(models.ScopeDevicesRead), func(c *gin.Context) {
+		userID, ok := resolveUserID(c, c.Query("user_id"))
+		if !ok {
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"user_id": userID})
+	})
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantUserID string
+		wantCode   string
+	}{
+		{name: "own user ID", query: "?user_id=user-a", wantStatus: http.StatusOK, wantUserID: "user-a"},
+		{name: "user ID taken from the token", wantStatus: http.StatusOK, wantUserID: "user-a"},
+		{name: "another user's ID", query: "?user_id=user-b", wantStatus: http.StatusForbidden, wantCode: "user_mismatch"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/devices"+tt.query, nil)
+			req.Header.Set("Authorization", "Bearer "+userToken(t, "user-a"))
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body)
+			}
+
+			var body struct {
+				UserID string `json:"user_id"`
+				Code   string `json:"code"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if body.UserID != tt.wantUserID || body.Code != tt.wantCode {
+				t.Errorf("expected user_id %q and code %q, got %s", tt.wantUserID, tt.wantCode, rec.Body)
+			}
+		})
+	}
+}
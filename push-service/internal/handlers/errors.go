@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"push-service/internal/middleware"
+	"push-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Errors raised by request validation in handlers
+var (
+	errUserIDRequired      = service.NewError(service.KindInvalid, "user_id_required", "User ID is required")
+	errDeviceTokenRequired = service.NewError(service.KindInvalid, "device_token_required", "Device token is required")
+	errUserMismatch        = service.NewError(service.KindForbidden, "user_mismatch", "user_id does not match the authenticated user")
+)
+
+// respondError writes err as an ErrorResponse with the status of its kind
+func respondError(c *gin.Context, err error) {
+	middleware.RespondError(c, err)
+}
+
+// respondInvalidRequest reports a request that failed binding or validation
+func respondInvalidRequest(c *gin.Context, err error) {
+	respondError(c, service.ErrInvalidRequest.WithDetails(err.Error()))
+}
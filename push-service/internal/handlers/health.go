@@ -2,7 +2,7 @@ package handlers
 
 import (
 	"net/http"
-	"push-service/pkg/database"
+	"push-service/internal/health"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -10,54 +10,60 @@ import (
 
 // HealthResponse represents the health check response
 type HealthResponse struct {
-	Status    string `json:"status" example:"healthy"`
-	Timestamp string `json:"timestamp" example:"2025-01-01T00:00:00Z"`
-	Database  string `json:"database,omitempty" example:"healthy"`
+	Status    string                        `json:"status" example:"healthy"`
+	Timestamp string                        `json:"timestamp" example:"2025-01-01T00:00:00Z"`
+	Checks    map[string]health.CheckResult `json:"checks,omitempty"`
 }
 
 // HealthCheck godoc
-// @Summary Health check endpoint
-// @Description Returns the health status of the service
+// @Summary Liveness check endpoint
+// @Description Returns whether the service is alive. Fails when the push worker has stalled: a queue has messages waiting but none were processed within the stall timeout.
 // @Tags health
 // @Accept json
 // @Produce json
 // @Success 200 {object} HealthResponse
+// @Failure 503 {object} HealthResponse
+// @Router /live [get]
 // @Router /health [get]
-func HealthCheck(c *gin.Context) {
-	c.JSON(http.StatusOK, HealthResponse{
-		Status:    "healthy",
-		Timestamp: time.Now().UTC().Format(time.RFC3339),
-	})
+func HealthCheck(registry *health.Registry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		healthy, checks := registry.Run(c.Request.Context())
+
+		status, body := http.StatusOK, "healthy"
+		if !healthy {
+			status, body = http.StatusServiceUnavailable, "unhealthy"
+		}
+
+		c.JSON(status, HealthResponse{
+			Status:    body,
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			Checks:    checks,
+		})
+	}
 }
 
 // ReadinessCheck godoc
 // @Summary Readiness check endpoint
-// @Description Returns the readiness status of the service including database connectivity
+// @Description Returns whether the service can take traffic: Postgres, the RabbitMQ connection and channel, the queue consumers, Redis when enabled and FCM credentials. Each check reports its latency and last error.
 // @Tags health
 // @Accept json
 // @Produce json
 // @Success 200 {object} HealthResponse
 // @Failure 503 {object} HealthResponse
 // @Router /ready [get]
-func ReadinessCheck(db *database.DB) gin.HandlerFunc {
+func ReadinessCheck(registry *health.Registry) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		var dbStatus string
-
-		if err := db.Pool.Ping(c.Request.Context()); err != nil {
-			dbStatus = "unhealthy"
-		} else {
-			dbStatus = "healthy"
-		}
+		ready, checks := registry.Run(c.Request.Context())
 
-		status := http.StatusOK
-		if dbStatus != "healthy" {
-			status = http.StatusServiceUnavailable
+		status, body := http.StatusOK, "ready"
+		if !ready {
+			status, body = http.StatusServiceUnavailable, "not_ready"
 		}
 
 		c.JSON(status, HealthResponse{
-			Status:    "ready",
+			Status:    body,
 			Timestamp: time.Now().UTC().Format(time.RFC3339),
-			Database:  dbStatus,
+			Checks:    checks,
 		})
 	}
 }
@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"context"
 	"net/http"
 	"push-service/internal/models"
 	"push-service/internal/service"
@@ -20,35 +19,60 @@ func NewPushHandler(pushService service.PushService) *PushHandler {
 
 // SendPush godoc
 // @Summary Send push notification
-// @Description Send a push notification to a user's devices via RabbitMQ queue
+// @Description Send a push notification to a user's devices via RabbitMQ queue. With mode=sync (or "sync": true) the queue is bypassed and the response reports the outcome for each device. With template_id the title and body come from a stored template; {{variable}} placeholders are filled from variables, data and the user's attributes.
 // @Tags push
 // @Accept json
 // @Produce json
+// @Security ApiKeyAuth
 // @Param request body models.SendPushRequest true "Push notification request"
-// @Success 200 {object} map[string]string "Push notification enqueued successfully"
-// @Failure 400 {object} map[string]string "Invalid request body"
-// @Failure 500 {object} map[string]string "Failed to send push notification"
+// @Param mode query string false "Delivery mode" Enums(async, sync)
+// @Param Idempotency-Key header string false "Client-chosen key; retries with the same key replay the original response"
+// @Success 200 {object} models.SyncPushResult "Per-device results of a synchronous send; asynchronous sends return a message, user_id and notification_id"
+// @Failure 400 {object} models.ErrorResponse "Invalid request body (invalid_request)"
+// @Failure 404 {object} models.ErrorResponse "The user has no devices (no_devices) or the template does not exist (template_not_found)"
+// @Failure 409 {object} models.ErrorResponse "A request with this Idempotency-Key is in progress (idempotency_key_in_progress)"
+// @Failure 422 {object} models.ErrorResponse "No devices match the requested platforms (no_matching_platforms), the template could not be rendered (template_render_failed) or the Idempotency-Key was used for a different request (idempotency_key_mismatch)"
+// @Failure 429 {object} models.ErrorResponse "Rate limit exceeded (rate_limited)"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Failure 503 {object} models.ErrorResponse "Notification queue unavailable (queue_unavailable)"
 // @Router /v1/push/send [post]
 func (h *PushHandler) SendPush(c *gin.Context) {
 	var req models.SendPushRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		zap.L().Warn("Invalid push request", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		respondInvalidRequest(c, err)
 		return
 	}
 
-	if err := h.pushService.SendPush(c.Request.Context(), req); err != nil {
-		zap.L().Error("Failed to send push", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to send push notification",
-			"details": err.Error(),
-		})
+	switch mode := c.DefaultQuery("mode", "async"); mode {
+	case "async":
+	case "sync":
+		req.Sync = true
+	default:
+		respondError(c, service.ErrInvalidRequest.WithDetails("mode must be async or sync"))
+		return
+	}
+
+	if req.Sync {
+		result, err := h.pushService.SendPushSync(c.Request.Context(), req)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, result)
+		return
+	}
+
+	notificationID, err := h.pushService.SendPush(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Push notification sent successfully",
-		"user_id": req.UserID,
+		"message":         "Push notification sent successfully",
+		"user_id":         req.UserID,
+		"notification_id": notificationID,
 	})
 }
 
@@ -58,22 +82,26 @@ func (h *PushHandler) SendPush(c *gin.Context) {
 // @Tags push
 // @Accept json
 // @Produce json
+// @Security ApiKeyAuth
 // @Param request body models.BulkPushRequest true "Bulk push notification request"
+// @Param Idempotency-Key header string false "Client-chosen key; retries with the same key replay the original response"
 // @Success 200 {object} map[string]interface{} "Bulk push notifications enqueued successfully"
-// @Failure 400 {object} map[string]string "Invalid request body"
-// @Failure 500 {object} map[string]string "Failed to send bulk push notifications"
+// @Failure 400 {object} models.ErrorResponse "Invalid request body (invalid_request)"
+// @Failure 409 {object} models.ErrorResponse "A request with this Idempotency-Key is in progress (idempotency_key_in_progress)"
+// @Failure 422 {object} models.ErrorResponse "Idempotency-Key was already used for a different request (idempotency_key_mismatch)"
+// @Failure 429 {object} models.ErrorResponse "Rate limit exceeded (rate_limited)"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
 // @Router /v1/push/send-bulk [post]
 func (h *PushHandler) SendBulkPush(c *gin.Context) {
 	var req models.BulkPushRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		zap.L().Warn("Invalid bulk push request", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		respondInvalidRequest(c, err)
 		return
 	}
 
 	if err := h.pushService.SendBulkPush(c.Request.Context(), req); err != nil {
-		zap.L().Error("Failed to send bulk push", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send bulk push notifications"})
+		respondError(c, err)
 		return
 	}
 
@@ -83,23 +111,54 @@ func (h *PushHandler) SendBulkPush(c *gin.Context) {
 	})
 }
 
+// SendSegmentPush godoc
+// @Summary Send push notification to a segment
+// @Description Send a push notification to every active device matching a segment definition, e.g. "platform = ios AND tag:beta AND app_version >= 3.0"
+// @Tags push
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body models.SendSegmentPushRequest true "Segment push notification request"
+// @Param Idempotency-Key header string false "Client-chosen key; retries with the same key replay the original response"
+// @Success 200 {object} models.SegmentPushResult
+// @Failure 400 {object} models.ErrorResponse "Invalid request body (invalid_request) or segment (invalid_segment)"
+// @Failure 409 {object} models.ErrorResponse "A request with this Idempotency-Key is in progress (idempotency_key_in_progress)"
+// @Failure 422 {object} models.ErrorResponse "Idempotency-Key was already used for a different request (idempotency_key_mismatch)"
+// @Failure 429 {object} models.ErrorResponse "Rate limit exceeded (rate_limited)"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Failure 503 {object} models.ErrorResponse "Notification queue unavailable (queue_unavailable)"
+// @Router /v1/push/send-segment [post]
+func (h *PushHandler) SendSegmentPush(c *gin.Context) {
+	var req models.SendSegmentPushRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		zap.L().Warn("Invalid segment push request", zap.Error(err))
+		respondInvalidRequest(c, err)
+		return
+	}
+
+	result, err := h.pushService.SendSegmentPush(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
 // GetQueueStats godoc
 // @Summary Get queue statistics
 // @Description Get statistics for all push notification queues (main, retry, dead letter)
 // @Tags queue
 // @Accept json
 // @Produce json
+// @Security ApiKeyAuth
 // @Success 200 {object} map[string]interface{} "Queue statistics"
-// @Failure 500 {object} map[string]string "Failed to get queue statistics"
+// @Failure 503 {object} models.ErrorResponse "Notification queue unavailable (queue_unavailable)"
 // @Router /v1/queue/stats [get]
 func (h *PushHandler) GetQueueStats(c *gin.Context) {
 	stats, err := h.pushService.GetQueueStats(c.Request.Context())
 	if err != nil {
-		zap.L().Error("Failed to get queue stats", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to get queue statistics",
-			"details": err.Error(),
-		})
+		respondError(c, service.ErrQueueUnavailable.Wrap(err))
 		return
 	}
 
@@ -107,62 +166,3 @@ func (h *PushHandler) GetQueueStats(c *gin.Context) {
 		"queues": stats,
 	})
 }
-
-// TestDirectSend godoc
-// @Summary Test direct FCM send
-// @Description Send a test push notification directly via FCM (bypasses queue, for testing only)
-// @Tags push
-// @Accept json
-// @Produce json
-// @Param request body object true "Direct send request" example({"token":"fcm_token","title":"Test","body":"Test message"})
-// @Success 200 {object} map[string]string "FCM test message sent successfully"
-// @Failure 400 {object} map[string]string "Invalid request body"
-// @Failure 500 {object} map[string]string "FCM send failed"
-// @Router /v1/push/test-direct [post]
-func (h *PushHandler) TestDirectSend(c *gin.Context) {
-	var req struct {
-		Token string         `json:"token" binding:"required"`
-		Title string         `json:"title" binding:"required"`
-		Body  string         `json:"body" binding:"required"`
-		Data  map[string]any `json:"data,omitempty"`
-	}
-
-	if err := c.ShouldBindJSON(&req); err != nil {
-		zap.L().Warn("Invalid direct send request", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
-		return
-	}
-
-	zap.L().Info("🔧 Testing FCM direct send",
-		zap.String("token", req.Token),
-		zap.String("title", req.Title),
-	)
-
-	notification := models.PushNotification{
-		Title: req.Title,
-		Body:  req.Body,
-		Data:  req.Data,
-	}
-
-	// Use the FCM client directly
-	err := h.pushService.(interface {
-		SendDirect(ctx context.Context, token string, notification models.PushNotification) error
-	}).SendDirect(c.Request.Context(), req.Token, notification)
-
-	if err != nil {
-		zap.L().Error("💥 FCM direct send failed",
-			zap.String("token", req.Token),
-			zap.Error(err),
-		)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "FCM send failed",
-			"details": err.Error(),
-		})
-		return
-	}
-
-	zap.L().Info("✅ FCM direct send successful")
-	c.JSON(http.StatusOK, gin.H{
-		"message": "FCM test message sent successfully",
-	})
-}
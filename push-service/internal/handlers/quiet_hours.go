@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"net/http"
+	"push-service/internal/models"
+	"push-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type QuietHoursHandler struct {
+	quietHoursService service.QuietHoursService
+}
+
+func NewQuietHoursHandler(quietHoursService service.QuietHoursService) *QuietHoursHandler {
+	return &QuietHoursHandler{quietHoursService: quietHoursService}
+}
+
+// GetUserQuietHours godoc
+// @Summary Get a user's quiet hours
+// @Description Get the daily window, in each device's local time, during which the user's non-urgent notifications are held
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security UserToken
+// @Param user_id path string true "User ID"
+// @Success 200 {object} models.QuietHours
+// @Failure 401 {object} models.ErrorResponse "Invalid API key or user token"
+// @Failure 403 {object} models.ErrorResponse "user_id does not match the authenticated user"
+// @Failure 404 {object} models.ErrorResponse "Quiet hours not set (quiet_hours_not_found)"
+// @Failure 500 {object} models.ErrorResponse "Failed to get quiet hours"
+// @Router /v1/users/{user_id}/quiet-hours [get]
+func (h *QuietHoursHandler) GetUserQuietHours(c *gin.Context) {
+	userID, ok := resolveUserID(c, c.Param("user_id"))
+	if !ok {
+		return
+	}
+	h.get(c, models.QuietHoursScopeUser, userID)
+}
+
+// SetUserQuietHours godoc
+// @Summary Set a user's quiet hours
+// @Description Hold the user's non-urgent notifications between start and end, HH:MM in each device's time zone, and deliver them when the window ends. A window that ends before it starts spans midnight. The user's quiet hours replace their apps'.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security UserToken
+// @Param user_id path string true "User ID"
+// @Param request body models.SetQuietHoursRequest true "Quiet hours"
+// @Success 200 {object} models.QuietHours
+// @Failure 400 {object} models.ErrorResponse "Invalid request body"
+// @Failure 401 {object} models.ErrorResponse "Invalid API key or user token"
+// @Failure 403 {object} models.ErrorResponse "user_id does not match the authenticated user"
+// @Failure 500 {object} models.ErrorResponse "Failed to set quiet hours"
+// @Router /v1/users/{user_id}/quiet-hours [put]
+func (h *QuietHoursHandler) SetUserQuietHours(c *gin.Context) {
+	userID, ok := resolveUserID(c, c.Param("user_id"))
+	if !ok {
+		return
+	}
+	h.set(c, models.QuietHoursScopeUser, userID)
+}
+
+// DeleteUserQuietHours godoc
+// @Summary Delete a user's quiet hours
+// @Description Remove the user's quiet hours, so their apps' quiet hours apply. Notifications already held are delivered when their window ends.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security UserToken
+// @Param user_id path string true "User ID"
+// @Success 200 {object} map[string]string "Quiet hours deleted successfully"
+// @Failure 401 {object} models.ErrorResponse "Invalid API key or user token"
+// @Failure 403 {object} models.ErrorResponse "user_id does not match the authenticated user"
+// @Failure 404 {object} models.ErrorResponse "Quiet hours not set (quiet_hours_not_found)"
+// @Failure 500 {object} models.ErrorResponse "Failed to delete quiet hours"
+// @Router /v1/users/{user_id}/quiet-hours [delete]
+func (h *QuietHoursHandler) DeleteUserQuietHours(c *gin.Context) {
+	userID, ok := resolveUserID(c, c.Param("user_id"))
+	if !ok {
+		return
+	}
+	h.delete(c, models.QuietHoursScopeUser, userID)
+}
+
+// GetAppQuietHours godoc
+// @Summary Get an app's quiet hours
+// @Description Get the quiet hours applied to devices registered for the app whose users have not set their own
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param app path string true "App"
+// @Success 200 {object} models.QuietHours
+// @Failure 404 {object} models.ErrorResponse "Quiet hours not set (quiet_hours_not_found)"
+// @Failure 500 {object} models.ErrorResponse "Failed to get quiet hours"
+// @Router /v1/admin/apps/{app}/quiet-hours [get]
+func (h *QuietHoursHandler) GetAppQuietHours(c *gin.Context) {
+	h.get(c, models.QuietHoursScopeApp, c.Param("app"))
+}
+
+// SetAppQuietHours godoc
+// @Summary Set an app's quiet hours
+// @Description Hold non-urgent notifications to the app's devices between start and end, HH:MM in each device's time zone, unless the user has set their own quiet hours
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param app path string true "App"
+// @Param request body models.SetQuietHoursRequest true "Quiet hours"
+// @Success 200 {object} models.QuietHours
+// @Failure 400 {object} models.ErrorResponse "Invalid request body"
+// @Failure 500 {object} models.ErrorResponse "Failed to set quiet hours"
+// @Router /v1/admin/apps/{app}/quiet-hours [put]
+func (h *QuietHoursHandler) SetAppQuietHours(c *gin.Context) {
+	h.set(c, models.QuietHoursScopeApp, c.Param("app"))
+}
+
+// DeleteAppQuietHours godoc
+// @Summary Delete an app's quiet hours
+// @Description Remove the app's quiet hours. Notifications already held are delivered when their window ends.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param app path string true "App"
+// @Success 200 {object} map[string]string "Quiet hours deleted successfully"
+// @Failure 404 {object} models.ErrorResponse "Quiet hours not set (quiet_hours_not_found)"
+// @Failure 500 {object} models.ErrorResponse "Failed to delete quiet hours"
+// @Router /v1/admin/apps/{app}/quiet-hours [delete]
+func (h *QuietHoursHandler) DeleteAppQuietHours(c *gin.Context) {
+	h.delete(c, models.QuietHoursScopeApp, c.Param("app"))
+}
+
+func (h *QuietHoursHandler) get(c *gin.Context, scope, scopeID string) {
+	quietHours, err := h.quietHoursService.Get(c.Request.Context(), scope, scopeID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, quietHours)
+}
+
+func (h *QuietHoursHandler) set(c *gin.Context, scope, scopeID string) {
+	var req models.SetQuietHoursRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalidRequest(c, err)
+		return
+	}
+
+	quietHours, err := h.quietHoursService.Set(c.Request.Context(), scope, scopeID, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, quietHours)
+}
+
+func (h *QuietHoursHandler) delete(c *gin.Context, scope, scopeID string) {
+	if err := h.quietHoursService.Delete(c.Request.Context(), scope, scopeID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Quiet hours deleted successfully"})
+}
@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"net/http"
+	"push-service/internal/models"
+	"push-service/internal/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TemplateHandler struct {
+	templateService service.TemplateService
+}
+
+func NewTemplateHandler(templateService service.TemplateService) *TemplateHandler {
+	return &TemplateHandler{templateService: templateService}
+}
+
+// CreateTemplate godoc
+// @Summary Create template
+// @Description Create a push template at version 1, with content for one or more locales
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body models.CreateTemplateRequest true "Template"
+// @Success 201 {object} models.PushTemplate
+// @Failure 400 {object} models.ErrorResponse "Invalid request body or placeholder (invalid_template)"
+// @Failure 409 {object} models.ErrorResponse "Template key already exists (template_exists)"
+// @Failure 500 {object} models.ErrorResponse "Failed to create template"
+// @Router /v1/templates [post]
+func (h *TemplateHandler) CreateTemplate(c *gin.Context) {
+	var req models.CreateTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalidRequest(c, err)
+		return
+	}
+
+	template, err := h.templateService.CreateTemplate(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, template)
+}
+
+// ListTemplates godoc
+// @Summary List templates
+// @Description List the latest version of every push template
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]interface{} "Templates"
+// @Failure 500 {object} models.ErrorResponse "Failed to list templates"
+// @Router /v1/templates [get]
+func (h *TemplateHandler) ListTemplates(c *gin.Context) {
+	templates, err := h.templateService.ListTemplates(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"templates": templates,
+		"count":     len(templates),
+	})
+}
+
+// GetTemplate godoc
+// @Summary Get template
+// @Description Get the latest version of a push template, or the version given
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param key path string true "Template key"
+// @Param version query int false "Template version"
+// @Success 200 {object} models.PushTemplate
+// @Failure 400 {object} models.ErrorResponse "Invalid version"
+// @Failure 404 {object} models.ErrorResponse "Template or version not found (template_not_found)"
+// @Failure 500 {object} models.ErrorResponse "Failed to get template"
+// @Router /v1/templates/{key} [get]
+func (h *TemplateHandler) GetTemplate(c *gin.Context) {
+	version := 0
+	if value := c.Query("version"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			respondError(c, service.ErrInvalidRequest.WithDetails("version must be a positive integer"))
+			return
+		}
+		version = parsed
+	}
+
+	template, err := h.templateService.GetTemplate(c.Request.Context(), c.Param("key"), version)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// ListTemplateVersions godoc
+// @Summary List template versions
+// @Description List the versions of a push template, newest first
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param key path string true "Template key"
+// @Success 200 {object} map[string]interface{} "Versions"
+// @Failure 404 {object} models.ErrorResponse "Template not found (template_not_found)"
+// @Failure 500 {object} models.ErrorResponse "Failed to list versions"
+// @Router /v1/templates/{key}/versions [get]
+func (h *TemplateHandler) ListTemplateVersions(c *gin.Context) {
+	versions, err := h.templateService.ListVersions(c.Request.Context(), c.Param("key"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"versions": versions,
+		"count":    len(versions),
+	})
+}
+
+// UpdateTemplate godoc
+// @Summary Update template
+// @Description Replace a push template's content, adding a new version. Sends use the new version at once on this instance and within templates.cache_ttl elsewhere.
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param key path string true "Template key"
+// @Param request body models.UpdateTemplateRequest true "Template content"
+// @Success 200 {object} models.PushTemplate
+// @Failure 400 {object} models.ErrorResponse "Invalid request body or placeholder (invalid_template)"
+// @Failure 404 {object} models.ErrorResponse "Template not found (template_not_found)"
+// @Failure 500 {object} models.ErrorResponse "Failed to update template"
+// @Router /v1/templates/{key} [put]
+func (h *TemplateHandler) UpdateTemplate(c *gin.Context) {
+	var req models.UpdateTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalidRequest(c, err)
+		return
+	}
+
+	template, err := h.templateService.UpdateTemplate(c.Request.Context(), c.Param("key"), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// DeleteTemplate godoc
+// @Summary Delete template
+// @Description Delete a push template and all its versions
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param key path string true "Template key"
+// @Success 200 {object} map[string]string "Template deleted successfully"
+// @Failure 404 {object} models.ErrorResponse "Template not found (template_not_found)"
+// @Failure 500 {object} models.ErrorResponse "Failed to delete template"
+// @Router /v1/templates/{key} [delete]
+func (h *TemplateHandler) DeleteTemplate(c *gin.Context) {
+	if err := h.templateService.DeleteTemplate(c.Request.Context(), c.Param("key")); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Template deleted successfully"})
+}
+
+// PreviewTemplate godoc
+// @Summary Preview template
+// @Description Render a push template with sample variables and show the FCM message each platform would be sent. Nothing is sent.
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param key path string true "Template key"
+// @Param request body models.PreviewTemplateRequest false "Sample variables"
+// @Success 200 {object} models.TemplatePreview
+// @Failure 400 {object} models.ErrorResponse "Invalid request body"
+// @Failure 404 {object} models.ErrorResponse "Template or version not found (template_not_found)"
+// @Failure 422 {object} models.ErrorResponse "Template could not be rendered (template_render_failed)"
+// @Failure 500 {object} models.ErrorResponse "Failed to preview template"
+// @Router /v1/templates/{key}/preview [post]
+func (h *TemplateHandler) PreviewTemplate(c *gin.Context) {
+	var req models.PreviewTemplateRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondInvalidRequest(c, err)
+			return
+		}
+	}
+
+	preview, err := h.templateService.PreviewTemplate(c.Request.Context(), c.Param("key"), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, preview)
+}
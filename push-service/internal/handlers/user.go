@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"net/http"
+	"push-service/internal/models"
+	"push-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UserHandler struct {
+	userService service.UserService
+}
+
+func NewUserHandler(userService service.UserService) *UserHandler {
+	return &UserHandler{userService: userService}
+}
+
+// EraseUser godoc
+// @Summary Erase user data
+// @Description Hard-delete all devices and notification history for a user and publish an erasure audit event
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param user_id path string true "User ID"
+// @Success 200 {object} models.UserErasureResult
+// @Failure 400 {object} models.ErrorResponse "User ID is required"
+// @Failure 500 {object} models.ErrorResponse "Failed to erase user data"
+// @Router /v1/users/{user_id} [delete]
+func (h *UserHandler) EraseUser(c *gin.Context) {
+	userID := c.Param("user_id")
+	if userID == "" {
+		respondError(c, errUserIDRequired)
+		return
+	}
+
+	result, err := h.userService.EraseUserData(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ExportUser godoc
+// @Summary Export user data
+// @Description Export all device and notification records held for a user
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param user_id path string true "User ID"
+// @Success 200 {object} models.UserDataExport
+// @Failure 400 {object} models.ErrorResponse "User ID is required"
+// @Failure 500 {object} models.ErrorResponse "Failed to export user data"
+// @Router /v1/users/{user_id}/export [get]
+func (h *UserHandler) ExportUser(c *gin.Context) {
+	userID := c.Param("user_id")
+	if userID == "" {
+		respondError(c, errUserIDRequired)
+		return
+	}
+
+	export, err := h.userService.ExportUserData(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, export)
+}
+
+// GetUserAttributes godoc
+// @Summary Get user attributes
+// @Description Get the tags and key/value attributes used to target a user in segments
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param user_id path string true "User ID"
+// @Success 200 {object} models.UserAttributes
+// @Failure 500 {object} models.ErrorResponse "Failed to get user attributes"
+// @Router /v1/users/{user_id}/attributes [get]
+func (h *UserHandler) GetUserAttributes(c *gin.Context) {
+	attributes, err := h.userService.GetUserAttributes(c.Request.Context(), c.Param("user_id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, attributes)
+}
+
+// SetUserAttributes godoc
+// @Summary Set user attributes
+// @Description Replace the tags and key/value attributes used to target a user in segments
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param user_id path string true "User ID"
+// @Param request body models.SetUserAttributesRequest true "User attributes"
+// @Success 200 {object} models.UserAttributes
+// @Failure 400 {object} models.ErrorResponse "Invalid request body"
+// @Failure 500 {object} models.ErrorResponse "Failed to set user attributes"
+// @Router /v1/users/{user_id}/attributes [put]
+func (h *UserHandler) SetUserAttributes(c *gin.Context) {
+	var req models.SetUserAttributesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalidRequest(c, err)
+		return
+	}
+
+	attributes, err := h.userService.SetUserAttributes(c.Request.Context(), c.Param("user_id"), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, attributes)
+}
+
+// GetPreferences godoc
+// @Summary Get notification preferences
+// @Description List the categories a user has opted in to or out of, for all devices or one. Categories without a preference are enabled.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security UserToken
+// @Param user_id path string true "User ID"
+// @Success 200 {object} models.UserPreferences
+// @Failure 401 {object} models.ErrorResponse "Invalid API key or user token"
+// @Failure 403 {object} models.ErrorResponse "user_id does not match the authenticated user"
+// @Failure 500 {object} models.ErrorResponse "Failed to get preferences"
+// @Router /v1/users/{user_id}/preferences [get]
+func (h *UserHandler) GetPreferences(c *gin.Context) {
+	userID, ok := resolveUserID(c, c.Param("user_id"))
+	if !ok {
+		return
+	}
+
+	preferences, err := h.userService.GetPreferences(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, preferences)
+}
+
+// SetPreferences godoc
+// @Summary Set notification preferences
+// @Description Opt a user in to or out of categories, for all devices or, with device_token, one. Other preferences are kept. Transactional and security notifications cannot be turned off.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security UserToken
+// @Param user_id path string true "User ID"
+// @Param request body models.SetPreferencesRequest true "Preferences"
+// @Success 200 {object} models.UserPreferences
+// @Failure 400 {object} models.ErrorResponse "Invalid request body"
+// @Failure 401 {object} models.ErrorResponse "Invalid API key or user token"
+// @Failure 403 {object} models.ErrorResponse "user_id does not match the authenticated user"
+// @Failure 500 {object} models.ErrorResponse "Failed to set preferences"
+// @Router /v1/users/{user_id}/preferences [put]
+func (h *UserHandler) SetPreferences(c *gin.Context) {
+	userID, ok := resolveUserID(c, c.Param("user_id"))
+	if !ok {
+		return
+	}
+
+	var req models.SetPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalidRequest(c, err)
+		return
+	}
+
+	preferences, err := h.userService.SetPreferences(c.Request.Context(), userID, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, preferences)
+}
+
+// DeletePreference godoc
+// @Summary Delete a notification preference
+// @Description Remove a user-wide preference, or with device_token a device's, so the category falls back to the user-wide preference or the default
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security UserToken
+// @Param user_id path string true "User ID"
+// @Param category path string true "Category"
+// @Param device_token query string false "Device token"
+// @Success 200 {object} map[string]string "Preference deleted successfully"
+// @Failure 401 {object} models.ErrorResponse "Invalid API key or user token"
+// @Failure 403 {object} models.ErrorResponse "user_id does not match the authenticated user"
+// @Failure 404 {object} models.ErrorResponse "Preference not found (preference_not_found)"
+// @Failure 500 {object} models.ErrorResponse "Failed to delete preference"
+// @Router /v1/users/{user_id}/preferences/{category} [delete]
+func (h *UserHandler) DeletePreference(c *gin.Context) {
+	userID, ok := resolveUserID(c, c.Param("user_id"))
+	if !ok {
+		return
+	}
+
+	err := h.userService.DeletePreference(c.Request.Context(), userID, c.Param("category"), c.Query("device_token"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Preference deleted successfully"})
+}
@@ -0,0 +1,120 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// QueueDepthFunc returns the number of messages waiting in a queue
+type QueueDepthFunc func(ctx context.Context, queue string) (int64, error)
+
+// Consumers tracks the worker's queue consumers, for the readiness check that
+// each is still registered and the liveness check that none has stalled
+type Consumers struct {
+	mu     sync.Mutex
+	queues []string
+	state  map[string]*consumerState
+}
+
+type consumerState struct {
+	registered bool
+	// idleSince is when the consumer last processed a delivery, or when
+	// tracking started if it has processed none
+	idleSince time.Time
+}
+
+// NewConsumers tracks a consumer for each of queues, initially unregistered
+func NewConsumers(queues ...string) *Consumers {
+	now := time.Now()
+	state := make(map[string]*consumerState, len(queues))
+	for _, queue := range queues {
+		state[queue] = &consumerState{idleSince: now}
+	}
+	return &Consumers{queues: queues, state: state}
+}
+
+// Registered records that the consumer of queue is receiving deliveries
+func (c *Consumers) Registered(queue string) {
+	c.update(queue, func(s *consumerState) {
+		s.registered = true
+		s.idleSince = time.Now()
+	})
+}
+
+// Unregistered records that the consumer of queue stopped, usually because
+// its channel closed
+func (c *Consumers) Unregistered(queue string) {
+	c.update(queue, func(s *consumerState) { s.registered = false })
+}
+
+// Processed records that the consumer of queue finished a delivery
+func (c *Consumers) Processed(queue string) {
+	c.update(queue, func(s *consumerState) { s.idleSince = time.Now() })
+}
+
+func (c *Consumers) update(queue string, fn func(*consumerState)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if s, ok := c.state[queue]; ok {
+		fn(s)
+	}
+}
+
+// CheckRegistered fails while any tracked consumer is not registered
+func (c *Consumers) CheckRegistered(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var missing []string
+	for _, queue := range c.queues {
+		if !c.state[queue].registered {
+			missing = append(missing, queue)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("no consumer registered for %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// CheckStalled returns a check that fails when a queue has messages waiting
+// but its consumer has processed none for stallTimeout. Queues whose depth
+// cannot be read are skipped, since restarting would not help.
+func (c *Consumers) CheckStalled(depth QueueDepthFunc, stallTimeout time.Duration) Check {
+	return func(ctx context.Context) error {
+		c.mu.Lock()
+		idle := make(map[string]time.Duration, len(c.queues))
+		for _, queue := range c.queues {
+			idle[queue] = time.Since(c.state[queue].idleSince)
+		}
+		c.mu.Unlock()
+
+		var stalled []string
+		for _, queue := range c.queues {
+			if idle[queue] < stallTimeout {
+				continue
+			}
+			waiting, err := depth(ctx, queue)
+			if err != nil {
+				zap.L().Warn("Failed to get queue depth for stall check",
+					zap.String("queue", queue),
+					zap.Error(err),
+				)
+				continue
+			}
+			if waiting > 0 {
+				stalled = append(stalled, fmt.Sprintf("%s has %d messages waiting and none processed for %s",
+					queue, waiting, idle[queue].Truncate(time.Second)))
+			}
+		}
+		if len(stalled) > 0 {
+			return fmt.Errorf("worker stalled: %s", strings.Join(stalled, "; "))
+		}
+		return nil
+	}
+}
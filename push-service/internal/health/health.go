@@ -0,0 +1,130 @@
+// Package health runs the dependency checks behind the readiness and
+// liveness endpoints
+package health
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Check statuses
+const (
+	StatusHealthy   = "healthy"
+	StatusUnhealthy = "unhealthy"
+)
+
+// Check reports whether a dependency is healthy; a nil error means healthy
+type Check func(ctx context.Context) error
+
+// CheckResult is the outcome of one check
+type CheckResult struct {
+	Status    string  `json:"status" example:"healthy"`
+	LatencyMS float64 `json:"latency_ms" example:"1.25"`
+	Error     string  `json:"error,omitempty" example:"connection is closed"`
+	// LastError is the most recent failure, kept after the check recovers
+	LastError   string     `json:"last_error,omitempty" example:"connection is closed"`
+	LastErrorAt *time.Time `json:"last_error_at,omitempty"`
+}
+
+// Registry runs a set of named checks concurrently, each bounded by a timeout
+type Registry struct {
+	timeout time.Duration
+
+	mu          sync.Mutex
+	checks      map[string]Check
+	lastError   map[string]string
+	lastErrorAt map[string]time.Time
+}
+
+func NewRegistry(timeout time.Duration) *Registry {
+	return &Registry{
+		timeout:     timeout,
+		checks:      make(map[string]Check),
+		lastError:   make(map[string]string),
+		lastErrorAt: make(map[string]time.Time),
+	}
+}
+
+// Register adds a check, replacing any check with the same name
+func (r *Registry) Register(name string, check Check) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks[name] = check
+}
+
+// Run runs every check and reports whether all of them passed
+func (r *Registry) Run(ctx context.Context) (bool, map[string]CheckResult) {
+	r.mu.Lock()
+	names := make([]string, 0, len(r.checks))
+	for name := range r.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	checks := make([]Check, len(names))
+	for i, name := range names {
+		checks[i] = r.checks[name]
+	}
+	r.mu.Unlock()
+
+	errs := make([]error, len(checks))
+	latencies := make([]time.Duration, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			errs[i] = r.run(ctx, check)
+			latencies[i] = time.Since(start)
+		}()
+	}
+	wg.Wait()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	healthy := true
+	results := make(map[string]CheckResult, len(names))
+	for i, name := range names {
+		result := CheckResult{
+			Status:    StatusHealthy,
+			LatencyMS: float64(latencies[i].Microseconds()) / 1000,
+		}
+		if errs[i] != nil {
+			healthy = false
+			result.Status = StatusUnhealthy
+			result.Error = errs[i].Error()
+			r.lastError[name] = result.Error
+			r.lastErrorAt[name] = time.Now().UTC()
+		}
+		if lastError, ok := r.lastError[name]; ok {
+			lastErrorAt := r.lastErrorAt[name]
+			result.LastError = lastError
+			result.LastErrorAt = &lastErrorAt
+		}
+		results[name] = result
+	}
+
+	return healthy, results
+}
+
+// run runs one check, returning the context's error if the check outlives
+// the timeout without returning
+func (r *Registry) run(ctx context.Context, check Check) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- check(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
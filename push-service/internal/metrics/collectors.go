@@ -0,0 +1,90 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// queueStatsTimeout bounds the broker calls made on each scrape
+const queueStatsTimeout = 5 * time.Second
+
+var (
+	dbAcquiredConns = prometheus.NewDesc(namespace+"_db_pool_acquired_conns", "Connections currently in use.", nil, nil)
+	dbIdleConns     = prometheus.NewDesc(namespace+"_db_pool_idle_conns", "Idle connections.", nil, nil)
+	dbTotalConns    = prometheus.NewDesc(namespace+"_db_pool_total_conns", "Open connections.", nil, nil)
+	dbMaxConns      = prometheus.NewDesc(namespace+"_db_pool_max_conns", "Maximum pool size.", nil, nil)
+	dbAcquires      = prometheus.NewDesc(namespace+"_db_pool_acquires_total", "Successful connection acquires.", nil, nil)
+	dbEmptyAcquires = prometheus.NewDesc(namespace+"_db_pool_empty_acquires_total", "Acquires that waited for a connection because the pool was empty.", nil, nil)
+	dbCanceled      = prometheus.NewDesc(namespace+"_db_pool_canceled_acquires_total", "Acquires canceled by their context.", nil, nil)
+	dbAcquireTime   = prometheus.NewDesc(namespace+"_db_pool_acquire_duration_seconds_total", "Total time spent acquiring connections.", nil, nil)
+
+	queueDepth = prometheus.NewDesc(namespace+"_queue_depth", "Messages waiting in each queue.", []string{"queue"}, nil)
+)
+
+// dbPoolCollector reports pgxpool statistics at scrape time
+type dbPoolCollector struct {
+	pool *pgxpool.Pool
+}
+
+// NewDBPoolCollector returns a collector for the pool's connection statistics
+func NewDBPoolCollector(pool *pgxpool.Pool) prometheus.Collector {
+	return &dbPoolCollector{pool: pool}
+}
+
+func (c *dbPoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- dbAcquiredConns
+	ch <- dbIdleConns
+	ch <- dbTotalConns
+	ch <- dbMaxConns
+	ch <- dbAcquires
+	ch <- dbEmptyAcquires
+	ch <- dbCanceled
+	ch <- dbAcquireTime
+}
+
+func (c *dbPoolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := c.pool.Stat()
+	ch <- prometheus.MustNewConstMetric(dbAcquiredConns, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(dbIdleConns, prometheus.GaugeValue, float64(stat.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(dbTotalConns, prometheus.GaugeValue, float64(stat.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(dbMaxConns, prometheus.GaugeValue, float64(stat.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(dbAcquires, prometheus.CounterValue, float64(stat.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(dbEmptyAcquires, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(dbCanceled, prometheus.CounterValue, float64(stat.CanceledAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(dbAcquireTime, prometheus.CounterValue, stat.AcquireDuration().Seconds())
+}
+
+// QueueStatsFunc reports the number of messages in each queue
+type QueueStatsFunc func(ctx context.Context) (map[string]int64, error)
+
+// queueDepthCollector reads queue depths from the broker at scrape time
+type queueDepthCollector struct {
+	stats QueueStatsFunc
+}
+
+// NewQueueDepthCollector returns a collector reporting the depths from stats
+func NewQueueDepthCollector(stats QueueStatsFunc) prometheus.Collector {
+	return &queueDepthCollector{stats: stats}
+}
+
+func (c *queueDepthCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- queueDepth
+}
+
+func (c *queueDepthCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), queueStatsTimeout)
+	defer cancel()
+
+	depths, err := c.stats(ctx)
+	if err != nil {
+		zap.L().Warn("Failed to collect queue depths", zap.Error(err))
+		return
+	}
+	for queue, depth := range depths {
+		ch <- prometheus.MustNewConstMetric(queueDepth, prometheus.GaugeValue, float64(depth), queue)
+	}
+}
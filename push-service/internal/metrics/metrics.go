@@ -0,0 +1,127 @@
+// Package metrics defines the Prometheus metrics exposed on /metrics
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "push"
+
+// Queue message events
+const (
+	QueueEnqueued     = "enqueued"
+	QueueConsumed     = "consumed"
+	QueueAcked        = "acked"
+	QueueNacked       = "nacked"
+	QueueRetried      = "retried"
+	QueueDeadLettered = "dead_lettered"
+)
+
+// Token validation results and the stage that validated the token
+const (
+	ValidationValid   = "valid"
+	ValidationInvalid = "invalid"
+
+	StageRegistration = "registration"
+	StageSend         = "send"
+)
+
+// Registry holds every metric of the service plus Go runtime and process metrics
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+var (
+	HTTPRequests = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route and status.",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method, route and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	QueueMessages = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "queue_messages_total",
+		Help:      "Queue messages by queue and event (enqueued, consumed, acked, nacked, retried, dead_lettered).",
+	}, []string{"queue", "event"})
+
+	FCMSends = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "fcm_sends_total",
+		Help:      "FCM sends by platform and error code; error_code is empty for successful sends.",
+	}, []string{"platform", "error_code"})
+
+	FCMSendDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "fcm_send_duration_seconds",
+		Help:      "FCM send latency by platform.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"platform"})
+
+	TokenValidations = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "token_validations_total",
+		Help:      "Device token validations by stage (registration, send) and result (valid, invalid).",
+	}, []string{"stage", "result"})
+
+	DeviceRegistrations = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "device_registrations_total",
+		Help:      "Device registrations by platform.",
+	}, []string{"platform"})
+
+	PreferenceSuppressions = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "preference_suppressions_total",
+		Help:      "Devices not sent to because they opted out of the notification's category, by category.",
+	}, []string{"category"})
+
+	QuietHoursDeferrals = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "quiet_hours_deferrals_total",
+		Help:      "Devices whose notification was held until their quiet hours end, by category.",
+	}, []string{"category"})
+
+	QuietHoursReleased = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "quiet_hours_released_total",
+		Help:      "Held notifications published to the push queue after quiet hours ended.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// QueueMessage counts one message event on a queue
+func QueueMessage(queue, event string) {
+	QueueMessages.WithLabelValues(queue, event).Inc()
+}
+
+// TokenValidation counts the outcome of validating a device token
+func TokenValidation(stage string, err error) {
+	result := ValidationValid
+	if err != nil {
+		result = ValidationInvalid
+	}
+	TokenValidations.WithLabelValues(stage, result).Inc()
+}
+
+// Handler serves the registry in the Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
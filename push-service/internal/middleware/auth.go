@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"errors"
+	"push-service/internal/config"
+	"push-service/internal/models"
+	"push-service/internal/service"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Gin context keys set by the authenticator
+const (
+	apiKeyContextKey = "api_key"      // authenticated *models.APIKey
+	userIDContextKey = "auth_user_id" // user ID from a verified user token
+)
+
+// APIKeyHeader is the header carrying an API key. Keys are also accepted as
+// "Authorization: Bearer <key>".
+const APIKeyHeader = "X-API-Key"
+
+type Authenticator struct {
+	apiKeyService service.APIKeyService
+	userTokens    *UserTokenVerifier
+	cfg           *config.AuthConfig
+}
+
+// NewAuthenticator builds the auth middleware. userTokens may be nil when end-user
+// JWTs are not accepted.
+func NewAuthenticator(apiKeyService service.APIKeyService, userTokens *UserTokenVerifier, cfg *config.AuthConfig) *Authenticator {
+	if !cfg.Enabled {
+		zap.L().Warn("API authentication is disabled")
+	}
+	return &Authenticator{
+		apiKeyService: apiKeyService,
+		userTokens:    userTokens,
+		cfg:           cfg,
+	}
+}
+
+// apiKeyFromRequest extracts the raw key from the X-API-Key or Authorization header
+func apiKeyFromRequest(c *gin.Context) string {
+	if key := c.GetHeader(APIKeyHeader); key != "" {
+		return key
+	}
+	if auth := c.GetHeader("Authorization"); len(auth) > 7 && strings.EqualFold(auth[:7], "Bearer ") {
+		return strings.TrimSpace(auth[7:])
+	}
+	return ""
+}
+
+// RequireScope rejects requests without a valid API key granting scope
+func (a *Authenticator) RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !a.cfg.Enabled {
+			c.Next()
+			return
+		}
+
+		a.authenticateAPIKey(c, scope)
+	}
+}
+
+// RequireScopeOrUser accepts either an API key granting scope or a verified
+// end-user JWT. Handlers restrict JWT callers to their own user ID via
+// UserIDFromContext.
+func (a *Authenticator) RequireScopeOrUser(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !a.cfg.Enabled {
+			c.Next()
+			return
+		}
+
+		if raw := apiKeyFromRequest(c); a.userTokens != nil && looksLikeJWT(raw) {
+			userID, err := a.userTokens.Verify(raw)
+			if err != nil {
+				zap.L().Warn("Rejected user token", zap.Error(err))
+				RespondError(c, errInvalidUserToken)
+				return
+			}
+
+			c.Set(userIDContextKey, userID)
+			c.Next()
+			return
+		}
+
+		a.authenticateAPIKey(c, scope)
+	}
+}
+
+// authenticateAPIKey verifies the request's API key and scope, aborting on failure
+func (a *Authenticator) authenticateAPIKey(c *gin.Context, scope string) {
+	rawKey := apiKeyFromRequest(c)
+	if rawKey == "" {
+		RespondError(c, errAPIKeyRequired)
+		return
+	}
+
+	key, err := a.apiKeyService.Authenticate(c.Request.Context(), rawKey)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidAPIKey) {
+			RespondError(c, err)
+			return
+		}
+		RespondError(c, errAuthUnavailable.Wrap(err))
+		return
+	}
+
+	if !key.HasScope(scope) {
+		zap.L().Warn("API key missing required scope",
+			zap.String("key_id", key.ID),
+			zap.String("required_scope", scope),
+		)
+		RespondError(c, errInsufficientScope.WithDetails(map[string]string{"required_scope": scope}))
+		return
+	}
+
+	c.Set(apiKeyContextKey, key)
+	c.Next()
+}
+
+// UserIDFromContext returns the user ID of an end-user JWT caller. ok is false
+// for API key callers, which may act on any user.
+func UserIDFromContext(c *gin.Context) (userID string, ok bool) {
+	userID = c.GetString(userIDContextKey)
+	return userID, userID != ""
+}
+
+// APIKeyFromContext returns the API key authenticated for the request, if any
+func APIKeyFromContext(c *gin.Context) *models.APIKey {
+	if value, ok := c.Get(apiKeyContextKey); ok {
+		if key, ok := value.(*models.APIKey); ok {
+			return key
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"push-service/internal/models"
+	"push-service/internal/service"
+	"push-service/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Errors raised by the middleware itself
+var (
+	errAPIKeyRequired       = service.NewError(service.KindUnauthorized, "api_key_required", "API key is required")
+	errInvalidUserToken     = service.NewError(service.KindUnauthorized, "invalid_user_token", "Invalid user token")
+	errInsufficientScope    = service.NewError(service.KindForbidden, "insufficient_scope", "Insufficient scope")
+	errAuthUnavailable      = service.NewError(service.KindUnavailable, "auth_unavailable", "Authentication unavailable")
+	errRateLimited          = service.NewError(service.KindRateLimited, "rate_limited", "Rate limit exceeded")
+	errUnreadableBody       = service.NewError(service.KindInvalid, "invalid_request", "Failed to read request body")
+	errIdempotencyKeyLength = service.NewError(service.KindInvalid, "invalid_idempotency_key", "Idempotency-Key must be at most 255 characters")
+	errIdempotencyCheck     = service.NewError(service.KindUnavailable, "idempotency_unavailable", "Idempotency check unavailable")
+)
+
+// StatusCode maps an error kind to its HTTP status
+func StatusCode(kind service.Kind) int {
+	switch kind {
+	case service.KindInvalid:
+		return http.StatusBadRequest
+	case service.KindUnauthorized:
+		return http.StatusUnauthorized
+	case service.KindForbidden:
+		return http.StatusForbidden
+	case service.KindNotFound:
+		return http.StatusNotFound
+	case service.KindConflict:
+		return http.StatusConflict
+	case service.KindUnprocessable:
+		return http.StatusUnprocessableEntity
+	case service.KindRateLimited:
+		return http.StatusTooManyRequests
+	case service.KindUnavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// RespondError aborts the request with err as an ErrorResponse. Domain errors
+// use their own status and code; any other error is logged and reported as a
+// generic 500 so internal details never reach the client.
+func RespondError(c *gin.Context, err error) {
+	var domainErr *service.Error
+	if !errors.As(err, &domainErr) {
+		domainErr = service.ErrInternal
+	}
+
+	status := StatusCode(domainErr.Kind)
+	if status >= http.StatusInternalServerError {
+		logger.FromContext(c.Request.Context()).Error("Request failed",
+			zap.String("method", c.Request.Method),
+			zap.String("route", c.FullPath()),
+			zap.String("code", domainErr.Code),
+			zap.Error(err),
+		)
+	}
+
+	response := models.ErrorResponse{
+		Code:      domainErr.Code,
+		Message:   domainErr.Message,
+		RequestID: RequestID(c),
+	}
+	// Details of server errors may describe internals, so only client errors carry them
+	if status < http.StatusInternalServerError {
+		response.Details = domainErr.Details
+	}
+
+	c.AbortWithStatusJSON(status, response)
+}
@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"push-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// IdempotencyKeyHeader names the client-chosen key making a send request safe to retry
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses replayed from a stored result
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
+// Idempotency replays the stored response for requests repeating an
+// Idempotency-Key. It must run after the authenticator, as keys are scoped
+// to the caller.
+type Idempotency struct {
+	idempotencyService service.IdempotencyService
+}
+
+func NewIdempotency(idempotencyService service.IdempotencyService) *Idempotency {
+	return &Idempotency{idempotencyService: idempotencyService}
+}
+
+// capturingWriter keeps a copy of the response body for storage
+type capturingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *capturingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *capturingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// hashRequest fingerprints the route and body so a key cannot be reused for a different request
+func hashRequest(route string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(route))
+	h.Write([]byte{'\n'})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// requestUserIDs returns the users a send request is addressed to. Malformed
+// bodies are left for the handler to reject.
+func requestUserIDs(body []byte) []string {
+	var target struct {
+		UserID  string   `json:"user_id"`
+		UserIDs []string `json:"user_ids"`
+	}
+	if json.Unmarshal(body, &target) != nil {
+		return nil
+	}
+	if target.UserID != "" {
+		return append(target.UserIDs, target.UserID)
+	}
+	return target.UserIDs
+}
+
+func (i *Idempotency) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			RespondError(c, errIdempotencyKeyLength)
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			RespondError(c, errUnreadableBody)
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		scope := callerID(c)
+		route := c.Request.Method + " " + c.FullPath()
+
+		record, err := i.idempotencyService.Begin(c.Request.Context(), scope, key, route, hashRequest(route, body), requestUserIDs(body))
+		switch {
+		case errors.Is(err, service.ErrIdempotencyKeyMismatch), errors.Is(err, service.ErrIdempotencyKeyInProgress):
+			RespondError(c, err)
+			return
+		case err != nil:
+			RespondError(c, errIdempotencyCheck.Wrap(err))
+			return
+		case record.StatusCode != nil:
+			c.Header(IdempotentReplayedHeader, "true")
+			c.Data(*record.StatusCode, "application/json; charset=utf-8", record.Response)
+			c.Abort()
+			return
+		}
+
+		// Store the outcome even if the client went away; server errors are
+		// released so the request can be retried. A panicking handler releases
+		// the key before the panic is passed on to the recovery middleware.
+		ctx := context.WithoutCancel(c.Request.Context())
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				i.idempotencyService.Release(ctx, record)
+				panic(recovered)
+			}
+		}()
+
+		writer := &capturingWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		if status := writer.Status(); status >= http.StatusInternalServerError {
+			i.idempotencyService.Release(ctx, record)
+		} else {
+			i.idempotencyService.Complete(ctx, record, status, writer.body.Bytes())
+		}
+	}
+}
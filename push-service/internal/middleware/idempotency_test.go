@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"push-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fakeIdempotencyService reserves every key and records what became of it
+type fakeIdempotencyService struct {
+	released  []string
+	completed []string
+}
+
+func (s *fakeIdempotencyService) Begin(ctx context.Context, scope, key, route, requestHash string, userIDs []string) (*models.IdempotencyRecord, error) {
+	return &models.IdempotencyRecord{Scope: scope, Key: key, Route: route, RequestHash: requestHash}, nil
+}
+
+func (s *fakeIdempotencyService) Complete(ctx context.Context, reservation *models.IdempotencyRecord, statusCode int, response []byte) error {
+	s.completed = append(s.completed, reservation.Key)
+	return nil
+}
+
+func (s *fakeIdempotencyService) Release(ctx context.Context, reservation *models.IdempotencyRecord) error {
+	s.released = append(s.released, reservation.Key)
+	return nil
+}
+
+func TestIdempotencyReleasesKeyWhenHandlerPanics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	idempotencyService := &fakeIdempotencyService{}
+
+	router := gin.New()
+	router.Use(gin.Recovery())
+	router.POST("/v1/push/send", NewIdempotency(idempotencyService).Handle(), func(c *gin.Context) {
+		panic("handler failed")
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/push/send", strings.NewReader(`{"user_id":"user-a"}`))
+	req.Header.Set(IdempotencyKeyHeader, "order-1")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("expected the panic to be recovered as %d, got %d", http.StatusInternalServerError, rec.Code)
+	}
+	if len(idempotencyService.released) != 1 || idempotencyService.released[0] != "order-1" {
+		t.Errorf("expected key order-1 to be released, got %v", idempotencyService.released)
+	}
+	if len(idempotencyService.completed) != 0 {
+		t.Errorf("expected no stored response, got %v", idempotencyService.completed)
+	}
+}
+
+func TestRequestUserIDs(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{name: "single send", body: `{"user_id":"user-a"}`, want: []string{"user-a"}},
+		{name: "bulk send", body: `{"user_ids":["user-a","user-b"]}`, want: []string{"user-a", "user-b"}},
+		{name: "segment send", body: `{"segment":"platform = ios"}`, want: nil},
+		{name: "malformed body", body: `{"user_id":`, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := requestUserIDs([]byte(tt.body)); !slices.Equal(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
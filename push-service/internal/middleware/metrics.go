@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"push-service/internal/metrics"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// unmatchedRoute labels requests that matched no route, keeping label cardinality bounded
+const unmatchedRoute = "unmatched"
+
+// Metrics records the count and latency of each request by route template and status
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		status := strconv.Itoa(c.Writer.Status())
+
+		metrics.HTTPRequests.WithLabelValues(c.Request.Method, route, status).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(c.Request.Method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
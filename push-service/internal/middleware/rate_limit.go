@@ -0,0 +1,133 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math"
+	"push-service/internal/config"
+	"push-service/internal/ratelimit"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// RateLimiter enforces per-caller and per-target-user request limits. It must
+// run after the authenticator so callers are identified by API key or user.
+type RateLimiter struct {
+	limiter ratelimit.Limiter
+	cfg     *config.RateLimitConfig
+}
+
+func NewRateLimiter(limiter ratelimit.Limiter, cfg *config.RateLimitConfig) *RateLimiter {
+	return &RateLimiter{
+		limiter: limiter,
+		cfg:     cfg,
+	}
+}
+
+// callerID identifies who is making the request
+func callerID(c *gin.Context) string {
+	if key := APIKeyFromContext(c); key != nil {
+		return "key:" + key.ID
+	}
+	if userID, ok := UserIDFromContext(c); ok {
+		return "jwt:" + userID
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// callerLimit resolves the limit for the matched route. Route limits apply to
+// every caller; otherwise a key's own limit replaces the default.
+func (r *RateLimiter) callerLimit(c *gin.Context) int {
+	if limit, ok := r.cfg.RouteLimit(c.Request.Method, c.FullPath()); ok {
+		return limit
+	}
+	if key := APIKeyFromContext(c); key != nil && key.RateLimit != nil {
+		return *key.RateLimit
+	}
+	return r.cfg.PerKey
+}
+
+// Limit applies the caller's limit for the matched route and sets RateLimit-* headers
+func (r *RateLimiter) Limit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !r.cfg.Enabled {
+			c.Next()
+			return
+		}
+
+		limit := r.callerLimit(c)
+		route := c.Request.Method + " " + c.FullPath()
+		if r.allow(c, "caller:"+callerID(c)+":"+route, limit, true) {
+			c.Next()
+		}
+	}
+}
+
+// LimitTargetUser limits sends addressed to the user_id in the JSON body,
+// across all callers. The body is restored for the handler.
+func (r *RateLimiter) LimitTargetUser() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !r.cfg.Enabled || r.cfg.PerUser <= 0 {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			RespondError(c, errUnreadableBody)
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		// Malformed bodies are left for the handler to reject
+		var target struct {
+			UserID string `json:"user_id"`
+		}
+		if json.Unmarshal(body, &target) != nil || target.UserID == "" {
+			c.Next()
+			return
+		}
+
+		if r.allow(c, "user:"+target.UserID, r.cfg.PerUser, false) {
+			c.Next()
+		}
+	}
+}
+
+// allow checks one limit and aborts with 429 when it is exceeded. Limiter
+// errors fail open so an outage cannot block sends.
+func (r *RateLimiter) allow(c *gin.Context, key string, limit int, setHeaders bool) bool {
+	if limit <= 0 {
+		return true
+	}
+
+	result, err := r.limiter.Allow(c.Request.Context(), key, limit, r.cfg.Window)
+	if err != nil {
+		zap.L().Error("Rate limit check failed", zap.Error(err))
+		return true
+	}
+
+	if setHeaders {
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter)))
+	}
+
+	if result.Allowed {
+		return true
+	}
+
+	retryAfter := ceilSeconds(result.RetryAfter)
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	RespondError(c, errRateLimited.WithDetails(map[string]int{"retry_after": retryAfter}))
+	return false
+}
+
+// ceilSeconds rounds d up to whole seconds, with a minimum of one
+func ceilSeconds(d time.Duration) int {
+	return max(1, int(math.Ceil(d.Seconds())))
+}
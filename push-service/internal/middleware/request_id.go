@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"push-service/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader carries the caller's request ID, echoed in responses and error bodies
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDContextKey is the gin context key holding the request ID
+const requestIDContextKey = "request_id"
+
+// AssignRequestID accepts the caller's X-Request-ID, or generates one, and
+// stores it in the request context so logs and queued messages carry it
+func AssignRequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := logger.RequestIDOrNew(c.GetHeader(RequestIDHeader))
+
+		c.Set(requestIDContextKey, requestID)
+		c.Header(RequestIDHeader, requestID)
+		ctx := logger.WithComponent(c.Request.Context(), logger.ComponentHTTP)
+		c.Request = c.Request.WithContext(logger.WithRequestID(ctx, requestID))
+
+		c.Next()
+	}
+}
+
+// RequestID returns the ID of the current request, if one is known
+func RequestID(c *gin.Context) string {
+	if id := c.GetString(requestIDContextKey); id != "" {
+		return id
+	}
+	return c.GetHeader(RequestIDHeader)
+}
@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"push-service/internal/config"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+)
+
+// ErrInvalidUserToken is returned for user tokens that fail verification
+var ErrInvalidUserToken = errors.New("invalid user token")
+
+// UserTokenVerifier verifies end-user JWTs signed with HS256 or RS256
+type UserTokenVerifier struct {
+	cfg *config.JWTConfig
+	// keys is replaced when the JWKS file is reloaded
+	keys atomic.Pointer[verificationKeys]
+}
+
+// verificationKeys are the keys and algorithms accepted for user tokens
+type verificationKeys struct {
+	hmacKey    []byte
+	jwks       *jose.JSONWebKeySet
+	algorithms []jose.SignatureAlgorithm
+}
+
+// NewUserTokenVerifier loads the verification keys named in cfg
+func NewUserTokenVerifier(cfg *config.JWTConfig) (*UserTokenVerifier, error) {
+	keys, err := loadVerificationKeys(cfg)
+	if err != nil {
+		return nil, err
+	}
+	v := &UserTokenVerifier{cfg: cfg}
+	v.keys.Store(keys)
+	return v, nil
+}
+
+// Reload reads the JWKS file again, so rotated signing keys are accepted
+// without a restart. The current keys are kept if the file fails to load.
+func (v *UserTokenVerifier) Reload() error {
+	keys, err := loadVerificationKeys(v.cfg)
+	if err != nil {
+		return err
+	}
+	v.keys.Store(keys)
+	return nil
+}
+
+func loadVerificationKeys(cfg *config.JWTConfig) (*verificationKeys, error) {
+	keys := &verificationKeys{}
+
+	if cfg.HMACSecret != "" {
+		// RFC 7518 requires HS256 keys of at least 256 bits
+		if len(cfg.HMACSecret) < 32 {
+			return nil, fmt.Errorf("JWT HMAC secret must be at least 32 bytes")
+		}
+		keys.hmacKey = []byte(cfg.HMACSecret)
+		keys.algorithms = append(keys.algorithms, jose.HS256)
+	}
+
+	if cfg.JWKSFile != "" {
+		data, err := os.ReadFile(cfg.JWKSFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JWKS file: %w", err)
+		}
+		var jwks jose.JSONWebKeySet
+		if err := json.Unmarshal(data, &jwks); err != nil {
+			return nil, fmt.Errorf("failed to parse JWKS file: %w", err)
+		}
+		if len(jwks.Keys) == 0 {
+			return nil, fmt.Errorf("JWKS file %s contains no keys", cfg.JWKSFile)
+		}
+		keys.jwks = &jwks
+		keys.algorithms = append(keys.algorithms, jose.RS256)
+	}
+
+	if len(keys.algorithms) == 0 {
+		return nil, fmt.Errorf("JWT auth requires an HMAC secret or a JWKS file")
+	}
+
+	return keys, nil
+}
+
+// looksLikeJWT distinguishes compact JWTs from API keys, which contain no dots
+func looksLikeJWT(raw string) bool {
+	return strings.Count(raw, ".") == 2
+}
+
+// Verify checks the token signature and registered claims and returns the
+// user ID taken from the configured claim
+func (v *UserTokenVerifier) Verify(raw string) (string, error) {
+	keys := v.keys.Load()
+	token, err := jwt.ParseSigned(raw, keys.algorithms)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidUserToken, err)
+	}
+
+	var key interface{} = keys.jwks
+	if token.Headers[0].Algorithm == string(jose.HS256) {
+		key = keys.hmacKey
+	}
+
+	var claims jwt.Claims
+	custom := make(map[string]interface{})
+	if err := token.Claims(key, &claims, &custom); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidUserToken, err)
+	}
+
+	if claims.Expiry == nil {
+		return "", fmt.Errorf("%w: missing exp claim", ErrInvalidUserToken)
+	}
+
+	expected := jwt.Expected{
+		Issuer: v.cfg.Issuer,
+		Time:   time.Now(),
+	}
+	if v.cfg.Audience != "" {
+		expected.AnyAudience = jwt.Audience{v.cfg.Audience}
+	}
+	if err := claims.ValidateWithLeeway(expected, v.cfg.Leeway); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidUserToken, err)
+	}
+
+	userID, _ := custom[v.cfg.UserIDClaim].(string)
+	if userID == "" {
+		return "", fmt.Errorf("%w: missing %s claim", ErrInvalidUserToken, v.cfg.UserIDClaim)
+	}
+
+	return userID, nil
+}
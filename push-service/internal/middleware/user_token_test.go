@@ -0,0 +1,206 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"push-service/internal/config"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+)
+
+const testHMACSecret = "0123456789abcdef0123456789abcdef"
+
+// testClaims are the claims of a valid token for user-a
+type testClaims struct {
+	jwt.Claims
+	UserID string `json:"sub,omitempty"`
+}
+
+func validClaims() testClaims {
+	return testClaims{
+		Claims: jwt.Claims{
+			Issuer: "https://auth.example.com",
+			Expiry: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+		UserID: "user-a",
+	}
+}
+
+func signToken(t *testing.T, key jose.SigningKey, kid string, claims any) string {
+	t.Helper()
+
+	options := (&jose.SignerOptions{}).WithType("JWT")
+	if kid != "" {
+		options = options.WithHeader(jose.HeaderKey("kid"), kid)
+	}
+	signer, err := jose.NewSigner(key, options)
+	if err != nil {
+		t.Fatalf("failed to create signer: %v", err)
+	}
+	raw, err := jwt.Signed(signer).Claims(claims).Serialize()
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return raw
+}
+
+func newTestVerifier(t *testing.T, cfg config.JWTConfig) *UserTokenVerifier {
+	t.Helper()
+
+	if cfg.Issuer == "" {
+		cfg.Issuer = "https://auth.example.com"
+	}
+	if cfg.UserIDClaim == "" {
+		cfg.UserIDClaim = "sub"
+	}
+	v, err := NewUserTokenVerifier(&cfg)
+	if err != nil {
+		t.Fatalf("failed to create verifier: %v", err)
+	}
+	return v
+}
+
+// writeJWKS writes the public half of key to a JWKS file under kid
+func writeJWKS(t *testing.T, key *rsa.PrivateKey, kid string) string {
+	t.Helper()
+
+	jwks := jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{Key: &key.PublicKey, KeyID: kid, Algorithm: string(jose.RS256), Use: "sig"}}}
+	data, err := json.Marshal(jwks)
+	if err != nil {
+		t.Fatalf("failed to marshal JWKS: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("failed to write JWKS: %v", err)
+	}
+	return path
+}
+
+func TestNewUserTokenVerifierRejectsShortHMACSecret(t *testing.T) {
+	_, err := NewUserTokenVerifier(&config.JWTConfig{HMACSecret: testHMACSecret[:31], UserIDClaim: "sub"})
+	if err == nil || !strings.Contains(err.Error(), "at least 32 bytes") {
+		t.Fatalf("expected a 31-byte secret to be rejected, got %v", err)
+	}
+}
+
+func TestVerifyHS256(t *testing.T) {
+	hmacKey := jose.SigningKey{Algorithm: jose.HS256, Key: []byte(testHMACSecret)}
+
+	expired := validClaims()
+	expired.Expiry = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+
+	withinLeeway := validClaims()
+	withinLeeway.Expiry = jwt.NewNumericDate(time.Now().Add(-10 * time.Second))
+
+	noExpiry := validClaims()
+	noExpiry.Expiry = nil
+
+	wrongIssuer := validClaims()
+	wrongIssuer.Issuer = "https://evil.example.com"
+
+	noSubject := validClaims()
+	noSubject.UserID = ""
+
+	// alg=none tokens carry no signature
+	encode := func(v string) string { return base64.RawURLEncoding.EncodeToString([]byte(v)) }
+	unsigned := encode(`{"alg":"none","typ":"JWT"}`) + "." +
+		encode(`{"iss":"https://auth.example.com","sub":"user-a","exp":`+strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)+`}`) + "."
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr string
+	}{
+		{name: "valid", token: signToken(t, hmacKey, "", validClaims())},
+		{name: "expired within leeway", token: signToken(t, hmacKey, "", withinLeeway)},
+		{name: "expired", token: signToken(t, hmacKey, "", expired), wantErr: "expired"},
+		{name: "missing exp", token: signToken(t, hmacKey, "", noExpiry), wantErr: "missing exp claim"},
+		{name: "wrong issuer", token: signToken(t, hmacKey, "", wrongIssuer), wantErr: "issuer"},
+		{name: "missing subject", token: signToken(t, hmacKey, "", noSubject), wantErr: "missing sub claim"},
+		{
+			name:    "wrong secret",
+			token:   signToken(t, jose.SigningKey{Algorithm: jose.HS256, Key: []byte(strings.ToUpper(testHMACSecret))}, "", validClaims()),
+			wantErr: "invalid user token",
+		},
+		{
+			name:    "wrong algorithm",
+			token:   signToken(t, jose.SigningKey{Algorithm: jose.HS384, Key: []byte(testHMACSecret + testHMACSecret)}, "", validClaims()),
+			wantErr: "unexpected signature algorithm",
+		},
+		{name: "alg none", token: unsigned, wantErr: "unexpected signature algorithm"},
+	}
+
+	v := newTestVerifier(t, config.JWTConfig{HMACSecret: testHMACSecret, Leeway: 30 * time.Second})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID, err := v.Verify(tt.token)
+			if tt.wantErr == "" {
+				if err != nil || userID != "user-a" {
+					t.Fatalf("expected user-a, got %q, %v", userID, err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidUserToken) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected an invalid user token error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestVerifyRS256(t *testing.T) {
+	signingKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	rsaKey := jose.SigningKey{Algorithm: jose.RS256, Key: signingKey}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr string
+	}{
+		{name: "valid", token: signToken(t, rsaKey, "key-1", validClaims())},
+		{name: "unknown kid", token: signToken(t, rsaKey, "key-2", validClaims()), wantErr: "invalid user token"},
+		{
+			name:    "key not in the set",
+			token:   signToken(t, jose.SigningKey{Algorithm: jose.RS256, Key: otherKey}, "key-1", validClaims()),
+			wantErr: "invalid user token",
+		},
+		{
+			name:    "HS256 without an HMAC secret",
+			token:   signToken(t, jose.SigningKey{Algorithm: jose.HS256, Key: []byte(testHMACSecret)}, "key-1", validClaims()),
+			wantErr: "unexpected signature algorithm",
+		},
+	}
+
+	v := newTestVerifier(t, config.JWTConfig{JWKSFile: writeJWKS(t, signingKey, "key-1")})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID, err := v.Verify(tt.token)
+			if tt.wantErr == "" {
+				if err != nil || userID != "user-a" {
+					t.Fatalf("expected user-a, got %q, %v", userID, err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidUserToken) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected an invalid user token error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+// API key scopes. ScopeAdmin grants every other scope.
+const (
+	ScopeDevicesRead    = "devices:read"
+	ScopeDevicesWrite   = "devices:write"
+	ScopePushSend       = "push:send"
+	ScopePushBulk       = "push:bulk"
+	ScopeTemplatesRead  = "templates:read"
+	ScopeTemplatesWrite = "templates:write"
+	ScopeAdmin          = "admin"
+)
+
+// Scopes lists every valid API key scope
+var Scopes = []string{ScopeDevicesRead, ScopeDevicesWrite, ScopePushSend, ScopePushBulk, ScopeTemplatesRead, ScopeTemplatesWrite, ScopeAdmin}
+
+// IsValidScope reports whether scope is a known API key scope
+func IsValidScope(scope string) bool {
+	return slices.Contains(Scopes, scope)
+}
+
+// APIKey is a stored API key. The plaintext key is only returned on creation.
+type APIKey struct {
+	ID          string     `json:"id" db:"id"`
+	Name        string     `json:"name" db:"name"`
+	Prefix      string     `json:"prefix" db:"prefix"`
+	Scopes      []string   `json:"scopes" db:"scopes"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty" db:"last_used_at"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	RotatedFrom *string    `json:"rotated_from,omitempty" db:"rotated_from"`
+	// RateLimit overrides the default requests per rate limit window
+	RateLimit *int `json:"rate_limit,omitempty" db:"rate_limit"`
+}
+
+// HasScope reports whether the key grants scope
+func (k *APIKey) HasScope(scope string) bool {
+	return slices.Contains(k.Scopes, ScopeAdmin) || slices.Contains(k.Scopes, scope)
+}
+
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" binding:"required" example:"billing-service"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1" example:"push:send"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	RateLimit *int       `json:"rate_limit,omitempty" binding:"omitempty,min=1" example:"1200"`
+}
+
+type RotateAPIKeyRequest struct {
+	// GracePeriodSeconds keeps the old key valid for this long after rotation
+	GracePeriodSeconds int `json:"grace_period_seconds,omitempty" example:"3600"`
+}
+
+// CreatedAPIKey is returned once when a key is created or rotated
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key" example:"psk_1a2b3c4d_..."`
+}
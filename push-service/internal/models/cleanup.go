@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// CleanupPolicy describes what the stale device janitor removes.
+// A value of 0 days disables the corresponding policy.
+type CleanupPolicy struct {
+	DeactivateAfterDays     int `json:"deactivate_after_days"`
+	DeleteInactiveAfterDays int `json:"delete_inactive_after_days"`
+	HistoryRetentionDays    int `json:"history_retention_days"`
+}
+
+// CleanupResult reports the rows affected by a janitor run, or the rows that
+// would be affected when DryRun is set
+type CleanupResult struct {
+	DryRun                bool      `json:"dry_run"`
+	Skipped               bool      `json:"skipped"` // Another replica held the lock
+	DevicesDeactivated    int64     `json:"devices_deactivated"`
+	DevicesDeleted        int64     `json:"devices_deleted"`
+	NotificationsPurged   int64     `json:"notifications_purged"`
+	IdempotencyKeysPurged int64     `json:"idempotency_keys_purged"`
+	GatewayMessagesPurged int64     `json:"gateway_messages_purged"`
+	StartedAt             time.Time `json:"started_at"`
+	FinishedAt            time.Time `json:"finished_at"`
+	Error                 string    `json:"error,omitempty"`
+}
@@ -4,26 +4,153 @@ import (
 	"time"
 )
 
+// DefaultApp is used when a registration does not name an app
+const DefaultApp = "default"
+
 type Device struct {
-	ID        string    `json:"id" db:"id"`
-	UserID    string    `json:"user_id" db:"user_id"`
-	Token     string    `json:"token" db:"token"`
-	Platform  string    `json:"platform" db:"platform"`
-	IsActive  bool      `json:"is_active" db:"is_active"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	ID         string            `json:"id" db:"id"`
+	UserID     string            `json:"user_id" db:"user_id"`
+	App        string            `json:"app" db:"app"`
+	Token      string            `json:"token" db:"token"`
+	Platform   string            `json:"platform" db:"platform"`
+	Locale     string            `json:"locale,omitempty" db:"locale"`
+	Timezone   string            `json:"timezone,omitempty" db:"timezone"`
+	IsActive   bool              `json:"is_active" db:"is_active"`
+	Metadata   map[string]string `json:"metadata,omitempty" db:"metadata"`
+	Tags       []string          `json:"tags,omitempty" db:"tags"`
+	LastSeenAt time.Time         `json:"last_seen_at" db:"last_seen_at"`
+	CreatedAt  time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at" db:"updated_at"`
 }
 
 type CreateDeviceRequest struct {
-	UserID   string `json:"user_id" binding:"required"`
+	UserID   string `json:"user_id"`       // Required unless taken from an end-user JWT
+	App      string `json:"app,omitempty"` // Defaults to DefaultApp
 	Token    string `json:"token" binding:"required"`
 	Platform string `json:"platform" binding:"required,oneof=ios android web"`
+	// Locale is the device's language, such as pt-BR. An existing device
+	// keeps its locale when this is empty.
+	Locale string `json:"locale,omitempty" binding:"omitempty,bcp47_language_tag" example:"pt-BR"`
+	// Timezone is the device's IANA time zone, used for quiet hours. An
+	// existing device keeps its time zone when this is empty.
+	Timezone string `json:"timezone,omitempty" binding:"omitempty,timezone" example:"America/Sao_Paulo"`
+	// Metadata such as app_version or os_version, merged into any existing metadata
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// Tags are added to any existing device tags
+	Tags []string `json:"tags,omitempty"`
+}
+
+// UserDeviceFilter narrows which of a user's devices are unregistered
+type UserDeviceFilter struct {
+	ExceptToken string   // Keep this device, usually the caller's current session
+	Platforms   []string // Only these platforms; empty means all
+}
+
+// TokenRegistration describes a registered token as seen by a queued push
+type TokenRegistration struct {
+	App      string
+	Platform string
+	Locale   string
+	Timezone string
+	Active   bool // Registered and active for the push's user
+}
+
+// UnregisterUserDevicesResult reports a per-user unregister
+type UnregisterUserDevicesResult struct {
+	UserID            string `json:"user_id" example:"user123"`
+	UnregisteredCount int64  `json:"unregistered_count" example:"3"`
+}
+
+// SetDeviceTagsRequest replaces all tags on a device
+type SetDeviceTagsRequest struct {
+	Tags []string `json:"tags"`
 }
 
 type DeviceResponse struct {
-	ID       string `json:"id"`
-	UserID   string `json:"user_id"`
-	Token    string `json:"token"`
-	Platform string `json:"platform"`
-	IsActive bool   `json:"is_active"`
-}
\ No newline at end of file
+	ID       string            `json:"id"`
+	UserID   string            `json:"user_id"`
+	App      string            `json:"app"`
+	Token    string            `json:"token"`
+	Platform string            `json:"platform"`
+	Locale   string            `json:"locale,omitempty"`
+	Timezone string            `json:"timezone,omitempty"`
+	IsActive bool              `json:"is_active"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+	Tags     []string          `json:"tags,omitempty"`
+}
+
+// Device search sort columns
+const (
+	DeviceSortCreatedAt  = "created_at"
+	DeviceSortLastSeenAt = "last_seen_at"
+)
+
+// Metadata filter operators. Ordering operators compare dotted numeric
+// values such as "3.10" as versions and anything else as text.
+const (
+	MetadataOpEq  = "eq"
+	MetadataOpNe  = "ne"
+	MetadataOpLt  = "lt"
+	MetadataOpLte = "lte"
+	MetadataOpGt  = "gt"
+	MetadataOpGte = "gte"
+)
+
+// MetadataFilter matches a single device metadata key
+type MetadataFilter struct {
+	Key   string `json:"key"`
+	Op    string `json:"op"`
+	Value string `json:"value"`
+}
+
+// DeviceQuery filters, sorts and paginates an admin device search.
+// Pagination is keyset based: pass the previous page's NextCursor as Cursor.
+type DeviceQuery struct {
+	UserID         string
+	Platform       string
+	App            string
+	IsActive       *bool
+	CreatedAfter   *time.Time
+	CreatedBefore  *time.Time
+	LastSeenAfter  *time.Time
+	LastSeenBefore *time.Time
+	Metadata       []MetadataFilter
+	SortBy         string
+	Descending     bool
+	Cursor         string
+	Limit          int
+}
+
+// DevicePage is one page of device search results
+type DevicePage struct {
+	Devices    []Device `json:"devices"`
+	NextCursor string   `json:"next_cursor,omitempty"`
+}
+
+// BatchRegisterDevicesRequest registers many devices in one call. Items are
+// validated and reported individually, so one bad entry does not fail the rest.
+type BatchRegisterDevicesRequest struct {
+	Devices []CreateDeviceRequest `json:"devices" binding:"required"`
+}
+
+// BatchUnregisterDevicesRequest unregisters many device tokens in one call
+type BatchUnregisterDevicesRequest struct {
+	Tokens []string `json:"tokens" binding:"required"`
+}
+
+// BatchDeviceResult is the outcome for one entry of a batch request, in request order
+type BatchDeviceResult struct {
+	Index     int             `json:"index"`
+	Token     string          `json:"token"`
+	Success   bool            `json:"success"`
+	Device    *DeviceResponse `json:"device,omitempty"`
+	ErrorCode string          `json:"error_code,omitempty" example:"device_not_found"`
+	Error     string          `json:"error,omitempty"`
+}
+
+// BatchDeviceResponse reports every entry of a batch request
+type BatchDeviceResponse struct {
+	SuccessCount int                 `json:"success_count"`
+	FailureCount int                 `json:"failure_count"`
+	Results      []BatchDeviceResult `json:"results"`
+}
@@ -0,0 +1,10 @@
+package models
+
+// ErrorResponse is the body of every API error
+// @Description API error
+type ErrorResponse struct {
+	Code      string `json:"code" example:"device_not_found"`
+	Message   string `json:"message" example:"Device not found"`
+	Details   any    `json:"details,omitempty" swaggertype:"object"`
+	RequestID string `json:"request_id,omitempty" example:"6f1c2a9e-3b7d-4c1e-9a51-0d2f8e4b7c63"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// IdempotencyRecord stores the outcome of a request sent with an
+// Idempotency-Key. StatusCode is nil while the request is in flight. UserIDs
+// are the users the request was addressed to.
+type IdempotencyRecord struct {
+	Scope       string          `json:"scope"`
+	Key         string          `json:"key"`
+	Route       string          `json:"route"`
+	RequestHash string          `json:"request_hash"`
+	UserIDs     []string        `json:"user_ids,omitempty"`
+	StatusCode  *int            `json:"status_code,omitempty"`
+	Response    json.RawMessage `json:"response,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	ExpiresAt   time.Time       `json:"expires_at"`
+}
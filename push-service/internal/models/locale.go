@@ -0,0 +1,34 @@
+package models
+
+import "strings"
+
+// MatchLocale returns the key of variants that best matches locale: the
+// locale itself, then each less specific form of it, so "pt-BR" falls back to
+// "pt". Tags are compared case-insensitively, with "_" read as "-". ok is
+// false when nothing matches and the caller should use its default.
+func MatchLocale[V any](variants map[string]V, locale string) (key string, ok bool) {
+	if locale == "" || len(variants) == 0 {
+		return "", false
+	}
+
+	normalized := make(map[string]string, len(variants))
+	for key := range variants {
+		normalized[normalizeLocale(key)] = key
+	}
+
+	candidate := normalizeLocale(locale)
+	for {
+		if key, ok := normalized[candidate]; ok {
+			return key, true
+		}
+		i := strings.LastIndexByte(candidate, '-')
+		if i < 0 {
+			return "", false
+		}
+		candidate = candidate[:i]
+	}
+}
+
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// Notification delivery states. Sent, failed and suppressed are final;
+// suppressed means every device had opted out of the notification's category.
+// Deferred means every device was in quiet hours and the notification is held
+// until they end.
+const (
+	NotificationStatusQueued     = "queued"
+	NotificationStatusSending    = "sending"
+	NotificationStatusRetrying   = "retrying"
+	NotificationStatusDeferred   = "deferred"
+	NotificationStatusSent       = "sent"
+	NotificationStatusFailed     = "failed"
+	NotificationStatusSuppressed = "suppressed"
+)
+
+// IsFinalNotificationStatus reports whether a notification will change no further
+func IsFinalNotificationStatus(status string) bool {
+	return status == NotificationStatusSent || status == NotificationStatusFailed || status == NotificationStatusSuppressed
+}
+
+// NotificationStatus is the delivery progress of one notification
+type NotificationStatus struct {
+	ID              string     `json:"id"`
+	UserID          string     `json:"user_id"`
+	Category        string     `json:"category" example:"transactional"`
+	Status          string     `json:"status" example:"sent"`
+	DeviceCount     int        `json:"device_count"`
+	SuccessCount    int        `json:"success_count"`
+	FailureCount    int        `json:"failure_count"`
+	SuppressedCount int        `json:"suppressed_count"`
+	ErrorMessage    *string    `json:"error_message,omitempty"`
+	SentAt          *time.Time `json:"sent_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// NotificationStatusUpdate moves a notification to a new state
+type NotificationStatusUpdate struct {
+	Status          string
+	SuccessCount    int
+	FailureCount    int
+	SuppressedCount int // Added to the devices already suppressed
+	ErrorMessage    *string
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+// Notification categories. Sends without a category are transactional.
+const (
+	CategoryTransactional = "transactional"
+	CategorySecurity      = "security"
+	CategoryMarketing     = "marketing"
+	CategoryPromotions    = "promotions"
+	CategorySocial        = "social"
+	CategoryReminders     = "reminders"
+)
+
+// Categories lists every notification category
+var Categories = []string{CategoryTransactional, CategorySecurity, CategoryMarketing, CategoryPromotions, CategorySocial, CategoryReminders}
+
+// IsMandatoryCategory reports whether notifications in category are sent
+// regardless of preferences. Users cannot opt out of them.
+func IsMandatoryCategory(category string) bool {
+	return category == "" || slices.Contains([]string{CategoryTransactional, CategorySecurity}, category)
+}
+
+// NotificationPreference opts a user in or out of a category, on all of their
+// devices or, with DeviceToken, on one. A device's own preference takes
+// precedence over the user's; without either, the category is enabled.
+type NotificationPreference struct {
+	Category    string    `json:"category" example:"promotions"`
+	DeviceToken string    `json:"device_token,omitempty"`
+	Enabled     bool      `json:"enabled"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// UserPreferences lists the preferences a user has set
+type UserPreferences struct {
+	UserID      string                   `json:"user_id"`
+	Preferences []NotificationPreference `json:"preferences"`
+}
+
+// PreferenceUpdate sets one preference. Transactional and security
+// notifications cannot be turned off.
+type PreferenceUpdate struct {
+	Category    string `json:"category" binding:"required,oneof=marketing promotions social reminders" example:"promotions"`
+	DeviceToken string `json:"device_token,omitempty"`
+	Enabled     *bool  `json:"enabled" binding:"required" example:"false"`
+}
+
+// SetPreferencesRequest sets some of a user's preferences, leaving the others
+type SetPreferencesRequest struct {
+	Preferences []PreferenceUpdate `json:"preferences" binding:"required,min=1,dive"`
+}
@@ -2,6 +2,35 @@ package models
 
 import "time"
 
+// LocalizedContent is push content in one language. Every text field may
+// contain {{variable}} placeholders when it is rendered.
+type LocalizedContent struct {
+	Title string  `json:"title" binding:"required" example:"Your order shipped"`
+	Body  string  `json:"body" binding:"required" example:"Hi {{name}}, order {{order_id}} is on its way"`
+	Image *string `json:"image,omitempty"`
+	Link  *string `json:"link,omitempty" example:"https://example.com/orders/{{order_id}}"`
+	// PlatformOverrides replace fields on ios, android or web devices
+	PlatformOverrides map[string]ContentOverride `json:"platform_overrides,omitempty" binding:"omitempty,dive,keys,oneof=ios android web,endkeys"`
+}
+
+// ContentOverride replaces the fields it sets on one platform
+type ContentOverride struct {
+	Title *string `json:"title,omitempty"`
+	Body  *string `json:"body,omitempty"`
+	Image *string `json:"image,omitempty"`
+	Link  *string `json:"link,omitempty"`
+}
+
+// LocalizationKeys name strings in the app's own resources, for apps that
+// localize notifications on the device. They are sent to Android and APNs;
+// web devices show the title and body.
+type LocalizationKeys struct {
+	TitleLocKey  string   `json:"title_loc_key,omitempty" example:"order_shipped_title"`
+	TitleLocArgs []string `json:"title_loc_args,omitempty"`
+	BodyLocKey   string   `json:"body_loc_key,omitempty" example:"order_shipped_body"`
+	BodyLocArgs  []string `json:"body_loc_args,omitempty" example:"A-1042"`
+}
+
 type PushNotification struct {
 	ID           string         `json:"id" db:"id"`
 	DeviceID     *string        `json:"device_id,omitempty" db:"device_id"`
@@ -11,25 +40,132 @@ type PushNotification struct {
 	Image        *string        `json:"image,omitempty" db:"image"`
 	Link         *string        `json:"link,omitempty" db:"link"`
 	Data         map[string]any `json:"data,omitempty" db:"data"`
+	Category     string         `json:"category,omitempty" db:"category"`
 	Status       string         `json:"status" db:"status"`
 	ErrorMessage *string        `json:"error_message,omitempty" db:"error_message"`
 	SentAt       *time.Time     `json:"sent_at,omitempty" db:"sent_at"`
 	CreatedAt    time.Time      `json:"created_at" db:"created_at"`
+
+	// PlatformOverrides and Locales are applied per device by ForDevice
+	PlatformOverrides map[string]ContentOverride  `json:"platform_overrides,omitempty" db:"-"`
+	Locales           map[string]LocalizedContent `json:"locales,omitempty" db:"-"`
+	Localization      *LocalizationKeys           `json:"localization,omitempty" db:"-"`
+}
+
+// ForDevice returns the notification as sent to a device: the variant best
+// matching the device's locale, if any, with the overrides for its platform
+func (n PushNotification) ForDevice(platform, locale string) PushNotification {
+	if key, ok := MatchLocale(n.Locales, locale); ok {
+		variant := n.Locales[key]
+		n.Title, n.Body = variant.Title, variant.Body
+		if variant.Image != nil {
+			n.Image = variant.Image
+		}
+		if variant.Link != nil {
+			n.Link = variant.Link
+		}
+		// The default content's overrides are in the default language
+		n.PlatformOverrides = variant.PlatformOverrides
+	}
+	return n.ForPlatform(platform)
+}
+
+// ForPlatform returns the notification with the overrides for platform applied
+func (n PushNotification) ForPlatform(platform string) PushNotification {
+	override, ok := n.PlatformOverrides[platform]
+	if !ok {
+		return n
+	}
+	if override.Title != nil {
+		n.Title = *override.Title
+	}
+	if override.Body != nil {
+		n.Body = *override.Body
+	}
+	if override.Image != nil {
+		n.Image = override.Image
+	}
+	if override.Link != nil {
+		n.Link = override.Link
+	}
+	return n
 }
 
 type SendPushRequest struct {
 	UserID    string         `json:"user_id" binding:"required"`
-	Title     string         `json:"title" binding:"required"`
-	Body      string         `json:"body" binding:"required"`
+	Title     string         `json:"title" binding:"required_without=TemplateID"`
+	Body      string         `json:"body" binding:"required_without=TemplateID"`
 	Image     *string        `json:"image,omitempty"`
 	Link      *string        `json:"link,omitempty"`
 	Data      map[string]any `json:"data,omitempty"`
 	Platforms []string       `json:"platforms,omitempty"` // Filter by specific platforms
+	Sync      bool           `json:"sync,omitempty"`      // Send now, bypassing the queue, and report per-device results
+	// Category is checked against the user's preferences; it defaults to transactional
+	Category string `json:"category,omitempty" binding:"omitempty,oneof=transactional security marketing promotions social reminders" example:"promotions"`
+	// TemplateID renders the content from a stored template instead. Image
+	// and link, when given, take precedence over the template's.
+	TemplateID string `json:"template_id,omitempty" example:"order_shipped"`
+	// TemplateVersion pins a template version; the latest is used by default
+	TemplateVersion int `json:"template_version,omitempty" binding:"omitempty,min=1"`
+	// Variables fill {{variable}} placeholders, taking precedence over data
+	// and user attributes. With variables, an inline title and body are
+	// rendered too.
+	Variables map[string]any `json:"variables,omitempty"`
+	// Locales are title and body variants, picked per device by its locale.
+	// Title and body are sent to devices matching none of them.
+	Locales map[string]LocalizedContent `json:"locales,omitempty" binding:"omitempty,dive,keys,bcp47_language_tag,endkeys"`
+	// Localization sends keys for the app to localize instead
+	Localization *LocalizationKeys `json:"localization,omitempty"`
 }
 
 type BulkPushRequest struct {
-	UserIDs []string       `json:"user_ids" binding:"required"`
+	UserIDs  []string       `json:"user_ids" binding:"required"`
+	Title    string         `json:"title" binding:"required"`
+	Body     string         `json:"body" binding:"required"`
+	Data     map[string]any `json:"data,omitempty"`
+	Category string         `json:"category,omitempty" binding:"omitempty,oneof=transactional security marketing promotions social reminders" example:"marketing"`
+}
+
+// SendSegmentPushRequest sends a notification to every active device matching a segment
+type SendSegmentPushRequest struct {
+	Segment string         `json:"segment" binding:"required" example:"platform = ios AND tag:beta AND app_version >= 3.0"`
 	Title   string         `json:"title" binding:"required"`
 	Body    string         `json:"body" binding:"required"`
+	Image   *string        `json:"image,omitempty"`
+	Link    *string        `json:"link,omitempty"`
 	Data    map[string]any `json:"data,omitempty"`
+	// Category is checked against each user's preferences; it defaults to transactional
+	Category string `json:"category,omitempty" binding:"omitempty,oneof=transactional security marketing promotions social reminders" example:"marketing"`
+}
+
+// SegmentPushResult reports how many devices a segment send reached and how
+// many queue messages were published for them
+type SegmentPushResult struct {
+	Segment       string `json:"segment"`
+	DeviceCount   int    `json:"device_count"`
+	EnqueuedCount int    `json:"enqueued_count"`
+}
+
+// DeviceSendResult is the outcome of a synchronous send to one device.
+// ErrorCode is one of the fcm.ErrorCode values.
+type DeviceSendResult struct {
+	DeviceID  string `json:"device_id"`
+	Platform  string `json:"platform"`
+	Token     string `json:"token"`
+	Success   bool   `json:"success"`
+	MessageID string `json:"message_id,omitempty"`
+	ErrorCode string `json:"error_code,omitempty" example:"unregistered"`
+	Error     string `json:"error,omitempty"`
+	// Suppressed is set when the device opted out of the category and was not sent to
+	Suppressed bool `json:"suppressed,omitempty"`
+}
+
+// SyncPushResult reports the per-device outcomes of a synchronous send
+type SyncPushResult struct {
+	NotificationID  string             `json:"notification_id"`
+	UserID          string             `json:"user_id"`
+	SuccessCount    int                `json:"success_count"`
+	FailureCount    int                `json:"failure_count"`
+	SuppressedCount int                `json:"suppressed_count"`
+	Results         []DeviceSendResult `json:"results"`
 }
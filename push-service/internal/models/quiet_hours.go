@@ -0,0 +1,77 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Quiet hours are set for a user or for every user of an app
+const (
+	QuietHoursScopeUser = "user"
+	QuietHoursScopeApp  = "app"
+)
+
+// QuietHours is a daily window, in each device's local time, during which
+// notifications that are not urgent are held until it ends. A window that
+// ends before it starts spans midnight.
+type QuietHours struct {
+	Scope     string    `json:"scope" example:"user"`
+	ScopeID   string    `json:"scope_id" example:"user123"`
+	Start     string    `json:"start" example:"22:00"`
+	End       string    `json:"end" example:"07:00"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SetQuietHoursRequest sets a daily quiet window as HH:MM local times
+type SetQuietHoursRequest struct {
+	Start string `json:"start" binding:"required,datetime=15:04" example:"22:00"`
+	End   string `json:"end" binding:"required,datetime=15:04,nefield=Start" example:"07:00"`
+}
+
+// Until reports whether t falls within the quiet hours and, if it does, when
+// they end, in t's location. Daylight saving changes are taken into account.
+func (q QuietHours) Until(t time.Time) (time.Time, bool) {
+	start, err := minuteOfDay(q.Start)
+	if err != nil {
+		return time.Time{}, false
+	}
+	end, err := minuteOfDay(q.End)
+	if err != nil || start == end {
+		return time.Time{}, false
+	}
+
+	now := t.Hour()*60 + t.Minute()
+	var quiet bool
+	if start < end {
+		quiet = now >= start && now < end
+	} else {
+		quiet = now >= start || now < end
+	}
+	if !quiet {
+		return time.Time{}, false
+	}
+
+	until := time.Date(t.Year(), t.Month(), t.Day(), end/60, end%60, 0, 0, t.Location())
+	if !until.After(t) {
+		until = time.Date(t.Year(), t.Month(), t.Day()+1, end/60, end%60, 0, 0, t.Location())
+	}
+	return until, true
+}
+
+// minuteOfDay parses an HH:MM time into minutes since midnight
+func minuteOfDay(value string) (int, error) {
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q: %w", value, err)
+	}
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
+
+// DeferredPush is a queue message held until DeliverAt
+type DeferredPush struct {
+	ID        string          `json:"id"`
+	UserID    string          `json:"user_id"`
+	Message   json.RawMessage `json:"message" swaggertype:"object"`
+	DeliverAt time.Time       `json:"deliver_at"`
+}
@@ -0,0 +1,124 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuietHoursUntil(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("failed to load time zone: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		start     string
+		end       string
+		at        time.Time
+		wantQuiet bool
+		wantUntil time.Time
+	}{
+		{
+			name:      "inside a window within the day",
+			start:     "13:00",
+			end:       "14:00",
+			at:        time.Date(2026, 5, 4, 13, 30, 0, 0, time.UTC),
+			wantQuiet: true,
+			wantUntil: time.Date(2026, 5, 4, 14, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "outside a window within the day",
+			start: "13:00",
+			end:   "14:00",
+			at:    time.Date(2026, 5, 4, 12, 59, 0, 0, time.UTC),
+		},
+		{
+			name:      "before midnight in a window spanning it",
+			start:     "22:00",
+			end:       "07:00",
+			at:        time.Date(2026, 5, 4, 23, 15, 0, 0, time.UTC),
+			wantQuiet: true,
+			wantUntil: time.Date(2026, 5, 5, 7, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "after midnight in a window spanning it",
+			start:     "22:00",
+			end:       "07:00",
+			at:        time.Date(2026, 5, 5, 3, 0, 0, 0, time.UTC),
+			wantQuiet: true,
+			wantUntil: time.Date(2026, 5, 5, 7, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "midday in a window spanning midnight",
+			start: "22:00",
+			end:   "07:00",
+			at:    time.Date(2026, 5, 4, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "exactly at the start",
+			start:     "22:00",
+			end:       "07:00",
+			at:        time.Date(2026, 5, 4, 22, 0, 0, 0, time.UTC),
+			wantQuiet: true,
+			wantUntil: time.Date(2026, 5, 5, 7, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "exactly at the end",
+			start: "22:00",
+			end:   "07:00",
+			at:    time.Date(2026, 5, 5, 7, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "in the device's time zone",
+			start:     "22:00",
+			end:       "07:00",
+			at:        time.Date(2026, 5, 4, 23, 0, 0, 0, newYork),
+			wantQuiet: true,
+			wantUntil: time.Date(2026, 5, 5, 11, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "across the start of daylight saving time",
+			start:     "22:00",
+			end:       "07:00",
+			at:        time.Date(2026, 3, 7, 23, 30, 0, 0, newYork),
+			wantQuiet: true,
+			// 07:00 EDT, six and a half hours later
+			wantUntil: time.Date(2026, 3, 8, 11, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "across the end of daylight saving time",
+			start:     "22:00",
+			end:       "07:00",
+			at:        time.Date(2026, 10, 31, 23, 30, 0, 0, newYork),
+			wantQuiet: true,
+			// 07:00 EST, eight and a half hours later
+			wantUntil: time.Date(2026, 11, 1, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "empty window",
+			start: "22:00",
+			end:   "22:00",
+			at:    time.Date(2026, 5, 4, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "invalid time",
+			start: "25:00",
+			end:   "07:00",
+			at:    time.Date(2026, 5, 4, 3, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quietHours := QuietHours{Start: tt.start, End: tt.end}
+			until, quiet := quietHours.Until(tt.at)
+			if quiet != tt.wantQuiet {
+				t.Fatalf("expected quiet %v, got %v", tt.wantQuiet, quiet)
+			}
+			if quiet && !until.Equal(tt.wantUntil) {
+				t.Errorf("expected quiet hours to end at %s, got %s", tt.wantUntil, until.UTC())
+			}
+		})
+	}
+}
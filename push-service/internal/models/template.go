@@ -0,0 +1,93 @@
+package models
+
+import "time"
+
+// PushTemplate is one version of stored push content, with a variant per
+// locale. Updating a template adds a version; earlier versions stay readable.
+type PushTemplate struct {
+	Key           string `json:"key" example:"order_shipped"`
+	Description   string `json:"description,omitempty"`
+	Version       int    `json:"version" example:"3"`
+	LatestVersion int    `json:"latest_version" example:"3"`
+	// DefaultLocale is used for devices whose locale has no variant
+	DefaultLocale string                      `json:"default_locale" example:"en"`
+	Locales       map[string]LocalizedContent `json:"locales"`
+	CreatedAt     time.Time                   `json:"created_at"`
+	UpdatedAt     time.Time                   `json:"updated_at"`
+}
+
+// Variant returns the content that best matches locale, falling back to the
+// default locale's content, and the locale it is for
+func (t *PushTemplate) Variant(locale string) (string, LocalizedContent) {
+	if key, ok := MatchLocale(t.Locales, locale); ok {
+		return key, t.Locales[key]
+	}
+	return t.DefaultLocale, t.Locales[t.DefaultLocale]
+}
+
+// TemplateVersion summarizes one version of a template
+type TemplateVersion struct {
+	Version       int       `json:"version" example:"2"`
+	DefaultLocale string    `json:"default_locale" example:"en"`
+	Locales       []string  `json:"locales" example:"en,pt-BR"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// UpdateTemplateRequest replaces a template's content, adding a version
+type UpdateTemplateRequest struct {
+	Description   string                      `json:"description,omitempty"`
+	DefaultLocale string                      `json:"default_locale" binding:"required,bcp47_language_tag" example:"en"`
+	Locales       map[string]LocalizedContent `json:"locales" binding:"required,min=1,dive,keys,bcp47_language_tag,endkeys"`
+}
+
+// CreateTemplateRequest creates a template at version 1
+type CreateTemplateRequest struct {
+	Key string `json:"key" binding:"required,max=255" example:"order_shipped"`
+	UpdateTemplateRequest
+}
+
+// PreviewTemplateRequest renders a template with sample variables
+type PreviewTemplateRequest struct {
+	// Version defaults to the latest
+	Version int    `json:"version,omitempty" binding:"omitempty,min=1"`
+	Locale  string `json:"locale,omitempty" example:"pt-BR"`
+	// UserID adds the user's attributes to the variables
+	UserID    string         `json:"user_id,omitempty"`
+	Name      string         `json:"name,omitempty"`
+	Data      map[string]any `json:"data,omitempty"`
+	Variables map[string]any `json:"variables,omitempty"`
+}
+
+// TemplatePreview is a rendered template and the FCM message sent for it on
+// each platform, with a placeholder device token
+type TemplatePreview struct {
+	Key      string           `json:"key"`
+	Version  int              `json:"version"`
+	Locale   string           `json:"locale"`
+	Content  LocalizedContent `json:"content"`
+	Payloads map[string]any   `json:"payloads"`
+}
+
+// RenderRequest is content to render for one user: a stored template when
+// TemplateID is set, otherwise Title, Body and their Locales variants
+type RenderRequest struct {
+	UserID     string
+	TemplateID string
+	// Version pins a template version; zero renders the latest
+	Version int
+	Title   string
+	Body    string
+	Locales map[string]LocalizedContent
+	// Name is the recipient's name sent by the API gateway
+	Name string
+	Data map[string]any
+	// Variables take precedence over Data, Name and user attributes
+	Variables map[string]any
+}
+
+// RenderedContent is rendered push content: the default content and a
+// variant for each other locale
+type RenderedContent struct {
+	LocalizedContent
+	Locales map[string]LocalizedContent `json:"locales,omitempty"`
+}
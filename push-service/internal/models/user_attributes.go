@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// UserAttributes holds user-level tags and key/value attributes used for segmentation
+type UserAttributes struct {
+	UserID     string            `json:"user_id" db:"user_id"`
+	Tags       []string          `json:"tags" db:"tags"`
+	Attributes map[string]string `json:"attributes" db:"attributes"`
+	UpdatedAt  time.Time         `json:"updated_at" db:"updated_at"`
+}
+
+// SetUserAttributesRequest replaces a user's tags and attributes
+type SetUserAttributesRequest struct {
+	Tags       []string          `json:"tags"`
+	Attributes map[string]string `json:"attributes"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// UserDataExport holds everything the service stores about a user
+type UserDataExport struct {
+	UserID         string                   `json:"user_id"`
+	Devices        []Device                 `json:"devices"`
+	Notifications  []PushNotification       `json:"notifications"`
+	Attributes     *UserAttributes          `json:"attributes,omitempty"`
+	Preferences    []NotificationPreference `json:"preferences"`
+	QuietHours     *QuietHours              `json:"quiet_hours,omitempty"`
+	DeferredPushes []DeferredPush           `json:"deferred_pushes"`
+	ExportedAt     time.Time                `json:"exported_at"`
+}
+
+// UserErasureResult reports the rows removed by a user data erasure
+type UserErasureResult struct {
+	UserID                 string    `json:"user_id"`
+	DevicesDeleted         int64     `json:"devices_deleted"`
+	NotificationsDeleted   int64     `json:"notifications_deleted"`
+	AttributesDeleted      bool      `json:"attributes_deleted"`
+	PreferencesDeleted     int64     `json:"preferences_deleted"`
+	QuietHoursDeleted      bool      `json:"quiet_hours_deleted"`
+	DeferredPushesDeleted  int64     `json:"deferred_pushes_deleted"`
+	IdempotencyKeysDeleted int64     `json:"idempotency_keys_deleted"`
+	GatewayMessagesDeleted int64     `json:"gateway_messages_deleted"`
+	AuditEventPublished    bool      `json:"audit_event_published"`
+	ErasedAt               time.Time `json:"erased_at"`
+}
+
+// AuditEvent is published to the audit exchange for compliance-relevant actions
+type AuditEvent struct {
+	Type       string         `json:"type"`
+	UserID     string         `json:"user_id"`
+	Details    map[string]any `json:"details,omitempty"`
+	OccurredAt time.Time      `json:"occurred_at"`
+}
+
+const AuditEventUserErased = "user.erased"
@@ -2,10 +2,13 @@ package fcm
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"push-service/internal/config"
 	"push-service/internal/models"
+	"push-service/pkg/logger"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	firebase "firebase.google.com/go"
@@ -15,19 +18,37 @@ import (
 )
 
 type FCMClient interface {
-	Send(ctx context.Context, deviceToken string, notification models.PushNotification) error
+	Send(ctx context.Context, deviceToken string, notification models.PushNotification) (string, error)
 	SendMultiple(ctx context.Context, deviceTokens []string, notification models.PushNotification) (int, int, error)
 	SendMulticast(ctx context.Context, deviceTokens []string, notification models.PushNotification) (*messaging.BatchResponse, error)
 	ValidateToken(ctx context.Context, deviceToken string) error
+	CheckCredentials(ctx context.Context) error
+	ReloadCredentials(ctx context.Context) error
 }
 
 type fcmClient struct {
-	client *messaging.Client
+	cfg *config.FCMConfig
+	// client is replaced when credentials are reloaded
+	client atomic.Pointer[messaging.Client]
 }
 
 func NewFCMClient(cfg *config.FCMConfig) (FCMClient, error) {
-	ctx := context.Background()
+	client, err := newMessagingClient(context.Background(), cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	zap.L().Named(logger.ComponentFCM).Info("FCM client initialized successfully",
+		zap.String("project_id", cfg.ProjectID),
+		zap.Bool("using_file", cfg.UseFile),
+	)
+	f := &fcmClient{cfg: cfg}
+	f.client.Store(client)
+	return f, nil
+}
 
+// newMessagingClient creates a messaging client from the configured credentials
+func newMessagingClient(ctx context.Context, cfg *config.FCMConfig) (*messaging.Client, error) {
 	// Get credentials from config
 	credentials, err := cfg.GetFCMCredentials()
 	if err != nil {
@@ -50,15 +71,52 @@ func NewFCMClient(cfg *config.FCMConfig) (FCMClient, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to create FCM client: %w", err)
 	}
+	return client, nil
+}
 
-	zap.L().Info("FCM client initialized successfully",
-		zap.String("project_id", cfg.ProjectID),
-		zap.Bool("using_file", cfg.UseFile),
+// ReloadCredentials reads the credentials again, so a rotated service account
+// file takes effect without a restart. The current client is kept if the new
+// credentials fail to load.
+func (f *fcmClient) ReloadCredentials(ctx context.Context) error {
+	client, err := newMessagingClient(ctx, f.cfg)
+	if err != nil {
+		return err
+	}
+	f.client.Store(client)
+	return nil
+}
+
+// CheckCredentials reports whether credentials have loaded and a messaging
+// client is available
+func (f *fcmClient) CheckCredentials(ctx context.Context) error {
+	if f.client.Load() == nil {
+		return fmt.Errorf("FCM credentials are not loaded")
+	}
+	return nil
+}
+
+// Send delivers a notification to one device and returns the FCM message ID
+func (f *fcmClient) Send(ctx context.Context, deviceToken string, notification models.PushNotification) (string, error) {
+	message := BuildMessage(deviceToken, notification)
+
+	response, err := f.client.Load().Send(ctx, message)
+	if err != nil {
+		log(ctx).Error("Failed to send FCM message",
+			logger.Token("token", deviceToken),
+			zap.Error(err),
+		)
+		return "", err
+	}
+
+	log(ctx).Info("FCM message sent successfully",
+		zap.String("message_id", response),
+		logger.Token("token", deviceToken),
 	)
-	return &fcmClient{client: client}, nil
+	return response, nil
 }
 
-func (f *fcmClient) Send(ctx context.Context, deviceToken string, notification models.PushNotification) error {
+// BuildMessage returns the FCM message that Send delivers for a notification
+func BuildMessage(deviceToken string, notification models.PushNotification) *messaging.Message {
 	// Convert map[string]any to map[string]string for FCM
 	data := convertDataToStringMap(notification.Data)
 
@@ -110,20 +168,34 @@ func (f *fcmClient) Send(ctx context.Context, deviceToken string, notification m
 		message.Webpush = webpushConfig
 	}
 
-	response, err := f.client.Send(ctx, message)
-	if err != nil {
-		zap.L().Error("Failed to send FCM message",
-			zap.String("token", deviceToken),
-			zap.Error(err),
-		)
-		return err
+	// Localization keys let the app localize on the device; the title and
+	// body remain as the fallback and for web
+	if keys := notification.Localization; keys != nil {
+		message.Android = &messaging.AndroidConfig{
+			Notification: &messaging.AndroidNotification{
+				TitleLocKey:  keys.TitleLocKey,
+				TitleLocArgs: keys.TitleLocArgs,
+				BodyLocKey:   keys.BodyLocKey,
+				BodyLocArgs:  keys.BodyLocArgs,
+			},
+		}
+		message.APNS = &messaging.APNSConfig{
+			Payload: &messaging.APNSPayload{
+				Aps: &messaging.Aps{
+					Alert: &messaging.ApsAlert{
+						Title:        notification.Title,
+						Body:         notification.Body,
+						TitleLocKey:  keys.TitleLocKey,
+						TitleLocArgs: keys.TitleLocArgs,
+						LocKey:       keys.BodyLocKey,
+						LocArgs:      keys.BodyLocArgs,
+					},
+				},
+			},
+		}
 	}
 
-	zap.L().Info("FCM message sent successfully",
-		zap.String("message_id", response),
-		zap.String("token", deviceToken),
-	)
-	return nil
+	return message
 }
 
 func (f *fcmClient) SendMultiple(ctx context.Context, deviceTokens []string, notification models.PushNotification) (int, int, error) {
@@ -183,10 +255,10 @@ func (f *fcmClient) SendMultiple(ctx context.Context, deviceTokens []string, not
 			message.Webpush = webpushConfig
 		}
 
-		_, err := f.client.Send(ctx, message)
+		_, err := f.client.Load().Send(ctx, message)
 		if err != nil {
-			zap.L().Error("Failed to send FCM message to device",
-				zap.String("token", token),
+			log(ctx).Error("Failed to send FCM message to device",
+				logger.Token("token", token),
 				zap.Error(err),
 			)
 			failureCount++
@@ -196,7 +268,7 @@ func (f *fcmClient) SendMultiple(ctx context.Context, deviceTokens []string, not
 		successCount++
 	}
 
-	zap.L().Info("Batch FCM messages completed",
+	log(ctx).Info("Batch FCM messages completed",
 		zap.Int("success_count", successCount),
 		zap.Int("failure_count", failureCount),
 		zap.Int("total", len(deviceTokens)),
@@ -255,9 +327,9 @@ func (f *fcmClient) SendMulticast(ctx context.Context, deviceTokens []string, no
 		Webpush:      webpushConfig,
 	}
 
-	response, err := f.client.SendMulticast(ctx, message)
+	response, err := f.client.Load().SendMulticast(ctx, message)
 	if err != nil {
-		zap.L().Error("Failed to send multicast FCM message",
+		log(ctx).Error("Failed to send multicast FCM message",
 			zap.Int("device_count", len(deviceTokens)),
 			zap.Error(err),
 		)
@@ -268,15 +340,15 @@ func (f *fcmClient) SendMulticast(ctx context.Context, deviceTokens []string, no
 	if response.FailureCount > 0 {
 		for i, resp := range response.Responses {
 			if resp.Error != nil {
-				zap.L().Warn("Individual FCM send failed",
-					zap.String("token", deviceTokens[i]),
+				log(ctx).Warn("Individual FCM send failed",
+					logger.Token("token", deviceTokens[i]),
 					zap.Error(resp.Error),
 				)
 			}
 		}
 	}
 
-	zap.L().Info("Multicast FCM messages completed",
+	log(ctx).Info("Multicast FCM messages completed",
 		zap.Int("success_count", response.SuccessCount),
 		zap.Int("failure_count", response.FailureCount),
 		zap.Int("total", len(deviceTokens)),
@@ -328,7 +400,7 @@ func (f *fcmClient) ValidateToken(ctx context.Context, deviceToken string) error
 	defer cancel()
 
 	// Attempt to send - if it fails with certain errors, token is invalid
-	err := f.Send(validationCtx, deviceToken, testNotification)
+	_, err := f.Send(validationCtx, deviceToken, testNotification)
 	if err != nil {
 		// Check for specific invalid token errors
 		errStr := strings.ToLower(err.Error())
@@ -338,8 +410,8 @@ func (f *fcmClient) ValidateToken(ctx context.Context, deviceToken string) error
 		}
 		// For other errors (network, etc.), we consider the token potentially valid
 		// since the error might be transient
-		zap.L().Debug("Token validation encountered non-fatal error",
-			zap.String("token", maskToken(deviceToken)),
+		log(ctx).Debug("Token validation encountered non-fatal error",
+			logger.Token("token", deviceToken),
 			zap.Error(err),
 		)
 	}
@@ -347,10 +419,44 @@ func (f *fcmClient) ValidateToken(ctx context.Context, deviceToken string) error
 	return nil
 }
 
-// maskToken masks a token for logging (shows first 10 and last 10 chars)
-func maskToken(token string) string {
-	if len(token) <= 20 {
-		return "***"
+// Error codes reported for failed sends
+const (
+	ErrorCodeUnregistered       = "unregistered"
+	ErrorCodeInvalidArgument    = "invalid_argument"
+	ErrorCodeCredentialMismatch = "credential_mismatch"
+	ErrorCodeRateExceeded       = "rate_exceeded"
+	ErrorCodeUnavailable        = "unavailable"
+	ErrorCodeInternal           = "internal"
+	ErrorCodeTimeout            = "timeout"
+	ErrorCodeUnknown            = "unknown"
+)
+
+// ErrorCode classifies a send error so callers can decide whether to retry
+// or drop the token
+func ErrorCode(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return ErrorCodeTimeout
+	case messaging.IsRegistrationTokenNotRegistered(err):
+		return ErrorCodeUnregistered
+	case messaging.IsInvalidArgument(err):
+		return ErrorCodeInvalidArgument
+	case messaging.IsMismatchedCredential(err), messaging.IsInvalidAPNSCredentials(err):
+		return ErrorCodeCredentialMismatch
+	case messaging.IsMessageRateExceeded(err):
+		return ErrorCodeRateExceeded
+	case messaging.IsServerUnavailable(err):
+		return ErrorCodeUnavailable
+	case messaging.IsInternal(err):
+		return ErrorCodeInternal
+	default:
+		return ErrorCodeUnknown
 	}
-	return token[:10] + "..." + token[len(token)-10:]
+}
+
+// log returns the context's logger named for the FCM component
+func log(ctx context.Context) *zap.Logger {
+	return logger.FromContext(ctx).Named(logger.ComponentFCM)
 }
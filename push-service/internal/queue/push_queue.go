@@ -2,12 +2,19 @@ package queue
 
 import (
 	"context"
+	"encoding/json"
 	"push-service/internal/config"
+	"push-service/internal/metrics"
 	"push-service/internal/models"
+	"push-service/pkg/logger"
 	"push-service/pkg/rabbitmq"
+	"push-service/pkg/tracing"
 	"time"
 
 	amqp "github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
@@ -19,6 +26,7 @@ const (
 	DeadLetterExchange   = "push_dlx"
 	GatewayPushQueueName = "push.queue"
 	GatewayExchangeName  = "notifications.direct"
+	AuditExchangeName    = "push_audit"
 )
 
 type PushQueue struct {
@@ -39,6 +47,11 @@ func NewPushQueue(rabbitmqClient *rabbitmq.RabbitMQClient, cfg *config.QueueConf
 		return nil, err
 	}
 
+	// Set up audit exchange; consumers bind their own queues by event type
+	if err := rabbitmqClient.EnsureExchange(ctx, AuditExchangeName, "topic"); err != nil {
+		return nil, err
+	}
+
 	// Set up dead letter queue with arguments
 	dlqArgs := amqp.Table{
 		"x-message-ttl": int64(7 * 24 * time.Hour / time.Millisecond), // 7 days
@@ -74,7 +87,7 @@ func NewPushQueue(rabbitmqClient *rabbitmq.RabbitMQClient, cfg *config.QueueConf
 		return nil, err
 	}
 
-	zap.L().Info("Push queue initialized with RabbitMQ",
+	zap.L().Named(logger.ComponentQueue).Info("Push queue initialized with RabbitMQ",
 		zap.String("exchange", PushExchangeName),
 		zap.String("queue", PushQueueName),
 	)
@@ -89,6 +102,17 @@ type PushMessage struct {
 	Notification models.PushNotification `json:"notification"`
 	DeviceTokens []string                `json:"device_tokens"`
 	RetryCount   int                     `json:"retry_count"`
+	// RequestID correlates worker logs with the request that queued the message
+	RequestID string `json:"request_id,omitempty"`
+	// Deferred marks a message released after its devices' quiet hours ended,
+	// so it is not held again
+	Deferred bool `json:"deferred,omitempty"`
+}
+
+// DeliveryRequestID returns the request ID header of a delivery, if present
+func DeliveryRequestID(delivery amqp.Delivery) string {
+	requestID, _ := delivery.Headers[rabbitmq.RequestIDHeader].(string)
+	return requestID
 }
 
 func (q *PushQueue) EnqueuePush(ctx context.Context, notification models.PushNotification, deviceTokens []string) error {
@@ -96,16 +120,32 @@ func (q *PushQueue) EnqueuePush(ctx context.Context, notification models.PushNot
 		Notification: notification,
 		DeviceTokens: deviceTokens,
 		RetryCount:   0,
+		RequestID:    logger.RequestIDFromContext(ctx),
 	}
 
-	if err := q.rabbitmqClient.Enqueue(ctx, PushExchangeName, PushQueueName, message); err != nil {
-		zap.L().Error("Failed to enqueue push message", zap.Error(err))
+	// The message's trace context is taken from this span, so the consumer's
+	// span follows it in the same trace
+	ctx, span := tracing.Tracer().Start(ctx, "PushQueue.EnqueuePush",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystemRabbitMQ,
+			semconv.MessagingOperationTypeSend,
+			semconv.MessagingDestinationName(PushExchangeName),
+			semconv.MessagingRabbitMQDestinationRoutingKey(PushQueueName),
+			attribute.Int("push.device_count", len(deviceTokens)),
+		),
+	)
+	err := q.rabbitmqClient.Enqueue(ctx, PushExchangeName, PushQueueName, message)
+	tracing.End(span, err)
+	if err != nil {
+		log(ctx).Error("Failed to enqueue push message", zap.Error(err))
 		return err
 	}
+	metrics.QueueMessage(PushQueueName, metrics.QueueEnqueued)
 
-	zap.L().Info("Push message enqueued",
+	log(ctx).Info("Push message enqueued",
 		zap.Int("device_count", len(deviceTokens)),
-		zap.String("title", notification.Title),
+		logger.Content("title", notification.Title),
 	)
 	return nil
 }
@@ -118,21 +158,34 @@ func (q *PushQueue) ConsumePush(ctx context.Context) (<-chan amqp.Delivery, erro
 	return q.rabbitmqClient.Consume(ctx, PushQueueName, prefetchCount)
 }
 
+func (q *PushQueue) maxRetries() int {
+	if q.cfg.Retry.MaxRetries == 0 {
+		return 5 // default
+	}
+	return q.cfg.Retry.MaxRetries
+}
+
+// RetriesExhausted reports whether EnqueueRetry will dead-letter message
+// rather than schedule another attempt
+func (q *PushQueue) RetriesExhausted(message PushMessage) bool {
+	return message.RetryCount >= q.maxRetries()
+}
+
 func (q *PushQueue) EnqueueRetry(ctx context.Context, message PushMessage) error {
 	message.RetryCount++
 
-	maxRetries := q.cfg.Retry.MaxRetries
-	if maxRetries == 0 {
-		maxRetries = 5 // default
-	}
-
+	maxRetries := q.maxRetries()
 	if message.RetryCount > maxRetries {
 		// Move to dead letter queue after max retries
-		zap.L().Warn("Message exceeded max retries, moving to dead letter queue",
+		log(ctx).Warn("Message exceeded max retries, moving to dead letter queue",
 			zap.Int("retry_count", message.RetryCount),
 			zap.Int("max_retries", maxRetries),
 		)
-		return q.rabbitmqClient.Enqueue(ctx, DeadLetterExchange, "dead_letter", message)
+		if err := q.rabbitmqClient.Enqueue(ctx, DeadLetterExchange, "dead_letter", message); err != nil {
+			return err
+		}
+		metrics.QueueMessage(DeadLetterQueue, metrics.QueueDeadLettered)
+		return nil
 	}
 
 	// Calculate backoff delay
@@ -142,13 +195,39 @@ func (q *PushQueue) EnqueueRetry(ctx context.Context, message PushMessage) error
 	}
 	delay := time.Duration(message.RetryCount) * backoff
 
-	zap.L().Info("Enqueuing retry",
+	log(ctx).Info("Enqueuing retry",
 		zap.Int("retry_count", message.RetryCount),
 		zap.Duration("delay", delay),
 	)
 
 	// Publish to retry queue with delay
-	return q.rabbitmqClient.EnqueueWithDelay(ctx, PushExchangeName, RetryQueueName, message, delay)
+	if err := q.rabbitmqClient.EnqueueWithDelay(ctx, PushExchangeName, RetryQueueName, message, delay); err != nil {
+		return err
+	}
+	metrics.QueueMessage(RetryQueueName, metrics.QueueRetried)
+	return nil
+}
+
+// EnqueueDeferred publishes a message that was held during quiet hours to the
+// push queue as it was stored
+func (q *PushQueue) EnqueueDeferred(ctx context.Context, body []byte) error {
+	if err := q.rabbitmqClient.Enqueue(ctx, PushExchangeName, PushQueueName, json.RawMessage(body)); err != nil {
+		log(ctx).Error("Failed to enqueue deferred push message", zap.Error(err))
+		return err
+	}
+	metrics.QueueMessage(PushQueueName, metrics.QueueEnqueued)
+	return nil
+}
+
+// DeadLetter publishes a message that can never be processed, such as a
+// gateway message whose template fails to render, to the dead letter queue
+// as it was received
+func (q *PushQueue) DeadLetter(ctx context.Context, body []byte) error {
+	if err := q.rabbitmqClient.Enqueue(ctx, DeadLetterExchange, "dead_letter", json.RawMessage(body)); err != nil {
+		return err
+	}
+	metrics.QueueMessage(DeadLetterQueue, metrics.QueueDeadLettered)
+	return nil
 }
 
 func (q *PushQueue) GetQueueStats(ctx context.Context) (map[string]int64, error) {
@@ -158,7 +237,7 @@ func (q *PushQueue) GetQueueStats(ctx context.Context) (map[string]int64, error)
 	for _, queueName := range queues {
 		length, err := q.rabbitmqClient.QueueLength(ctx, queueName)
 		if err != nil {
-			zap.L().Warn("Failed to get queue length",
+			log(ctx).Warn("Failed to get queue length",
 				zap.String("queue", queueName),
 				zap.Error(err),
 			)
@@ -172,11 +251,33 @@ func (q *PushQueue) GetQueueStats(ctx context.Context) (map[string]int64, error)
 	return stats, nil
 }
 
+// PublishAudit publishes an audit event routed by its type
+func (q *PushQueue) PublishAudit(ctx context.Context, event models.AuditEvent) error {
+	if err := q.rabbitmqClient.Enqueue(ctx, AuditExchangeName, event.Type, event); err != nil {
+		log(ctx).Error("Failed to publish audit event",
+			zap.String("type", event.Type),
+			zap.Error(err),
+		)
+		return err
+	}
+	return nil
+}
+
 // GetRabbitMQClient returns the underlying RabbitMQ client for ack/nack operations
 func (q *PushQueue) GetRabbitMQClient() *rabbitmq.RabbitMQClient {
 	return q.rabbitmqClient
 }
 
+// Ack acknowledges a delivery consumed from one of the queues
+func (q *PushQueue) Ack(delivery amqp.Delivery) error {
+	return q.rabbitmqClient.Ack(delivery.DeliveryTag, false)
+}
+
+// Nack rejects a delivery consumed from one of the queues, requeuing it if asked
+func (q *PushQueue) Nack(delivery amqp.Delivery, requeue bool) error {
+	return q.rabbitmqClient.Nack(delivery.DeliveryTag, false, requeue)
+}
+
 // ConsumeFromGateway consumes messages from the API Gateway's push.queue
 func (q *PushQueue) ConsumeFromGateway(ctx context.Context) (<-chan amqp.Delivery, error) {
 	// Ensure the gateway exchange exists
@@ -199,10 +300,15 @@ func (q *PushQueue) ConsumeFromGateway(ctx context.Context) (<-chan amqp.Deliver
 		prefetchCount = 10 // default
 	}
 
-	zap.L().Info("Gateway queue consumer initialized",
+	log(ctx).Info("Gateway queue consumer initialized",
 		zap.String("exchange", GatewayExchangeName),
 		zap.String("queue", GatewayPushQueueName),
 	)
 
 	return q.rabbitmqClient.Consume(ctx, GatewayPushQueueName, prefetchCount)
 }
+
+// log returns the context's logger named for the queue component
+func log(ctx context.Context) *zap.Logger {
+	return logger.FromContext(ctx).Named(logger.ComponentQueue)
+}
@@ -0,0 +1,56 @@
+package ratelimit
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// fallbackWarnInterval limits how often primary limiter failures are logged
+	fallbackWarnInterval = 30 * time.Second
+	// fallbackProbeInterval is how long the fallback is used after the primary
+	// fails before one request tries the primary again
+	fallbackProbeInterval = 5 * time.Second
+)
+
+// fallbackLimiter uses primary and switches to fallback while primary fails,
+// e.g. while Redis is unavailable. Once it fails, primary is probed by one
+// request every fallbackProbeInterval until it recovers.
+type fallbackLimiter struct {
+	primary   Limiter
+	fallback  Limiter
+	now       func() time.Time
+	lastWarn  atomic.Int64
+	downUntil atomic.Int64 // UnixNano before which primary is not tried
+}
+
+func NewFallbackLimiter(primary, fallback Limiter) Limiter {
+	return &fallbackLimiter{primary: primary, fallback: fallback, now: time.Now}
+}
+
+func (l *fallbackLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (Result, error) {
+	now := l.now().UnixNano()
+	downUntil := l.downUntil.Load()
+	if downUntil != 0 && (now < downUntil || !l.downUntil.CompareAndSwap(downUntil, now+int64(fallbackProbeInterval))) {
+		// Primary is down and another request is probing it
+		return l.fallback.Allow(ctx, key, limit, window)
+	}
+
+	result, err := l.primary.Allow(ctx, key, limit, window)
+	if err == nil {
+		if downUntil != 0 && l.downUntil.Swap(0) != 0 {
+			zap.L().Info("Rate limiter recovered, using shared limits")
+		}
+		return result, nil
+	}
+
+	l.downUntil.Store(now + int64(fallbackProbeInterval))
+	if last := l.lastWarn.Load(); now-last > int64(fallbackWarnInterval) && l.lastWarn.CompareAndSwap(last, now) {
+		zap.L().Warn("Rate limiter unavailable, using in-process limits", zap.Error(err))
+	}
+
+	return l.fallback.Allow(ctx, key, limit, window)
+}
@@ -0,0 +1,71 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// stubLimiter allows every request, or fails while err is set
+type stubLimiter struct {
+	err   error
+	calls int
+}
+
+func (l *stubLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (Result, error) {
+	l.calls++
+	if l.err != nil {
+		return Result{}, l.err
+	}
+	return Result{Allowed: true, Limit: limit}, nil
+}
+
+func TestFallbackLimiterProbesPrimaryWhileDown(t *testing.T) {
+	primary := &stubLimiter{err: errors.New("connection refused")}
+	fallback := &stubLimiter{}
+	now := time.Unix(1000, 0)
+	l := &fallbackLimiter{primary: primary, fallback: fallback, now: func() time.Time { return now }}
+	ctx := context.Background()
+
+	allow := func() {
+		t.Helper()
+		if result, err := l.Allow(ctx, "key", 10, time.Minute); err != nil || !result.Allowed {
+			t.Fatalf("expected the request to be allowed, got %+v, %v", result, err)
+		}
+	}
+
+	// A failure switches to the fallback
+	allow()
+	if primary.calls != 1 || fallback.calls != 1 {
+		t.Fatalf("expected the failed request to use the fallback, got %d primary and %d fallback calls", primary.calls, fallback.calls)
+	}
+
+	// The primary is left alone until the probe interval passes
+	now = now.Add(fallbackProbeInterval - time.Millisecond)
+	allow()
+	allow()
+	if primary.calls != 1 || fallback.calls != 3 {
+		t.Fatalf("expected the primary not to be tried, got %d primary and %d fallback calls", primary.calls, fallback.calls)
+	}
+
+	// A probe that fails keeps the fallback
+	now = now.Add(time.Millisecond)
+	allow()
+	if primary.calls != 2 || fallback.calls != 4 {
+		t.Fatalf("expected one probe of the primary, got %d primary and %d fallback calls", primary.calls, fallback.calls)
+	}
+	allow()
+	if primary.calls != 2 {
+		t.Fatalf("expected the next probe to wait, got %d primary calls", primary.calls)
+	}
+
+	// A probe that succeeds switches back
+	primary.err = nil
+	now = now.Add(fallbackProbeInterval)
+	allow()
+	allow()
+	if primary.calls != 4 || fallback.calls != 5 {
+		t.Fatalf("expected the primary to be used again, got %d primary and %d fallback calls", primary.calls, fallback.calls)
+	}
+}
@@ -0,0 +1,78 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Result describes a single rate limit decision
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// ResetAfter is the time until the current fixed window ends
+	ResetAfter time.Duration
+	// RetryAfter is set when the request is rejected
+	RetryAfter time.Duration
+}
+
+// Limiter counts requests for a key against a sliding window limit
+type Limiter interface {
+	Allow(ctx context.Context, key string, limit int, window time.Duration) (Result, error)
+}
+
+// windowPosition splits now into a fixed window index and the time elapsed in it
+func windowPosition(now time.Time, window time.Duration) (index int64, elapsed time.Duration) {
+	n := now.UnixNano()
+	index = n / int64(window)
+	elapsed = time.Duration(n % int64(window))
+	return index, elapsed
+}
+
+// previousWeight is the fraction of the previous fixed window still covered by
+// a sliding window ending now
+func previousWeight(elapsed, window time.Duration) float64 {
+	return float64(window-elapsed) / float64(window)
+}
+
+// evaluate applies the sliding window counter algorithm: requests in the
+// previous fixed window count in proportion to their overlap with the sliding
+// window. prev and cur are counts before this request.
+func evaluate(prev, cur, limit int, window, elapsed time.Duration) Result {
+	weighted := float64(prev)*previousWeight(elapsed, window) + float64(cur)
+	result := Result{
+		Limit:      limit,
+		ResetAfter: window - elapsed,
+	}
+
+	if weighted+1 <= float64(limit) {
+		result.Allowed = true
+		result.Remaining = int(math.Floor(float64(limit) - weighted - 1))
+		return result
+	}
+
+	result.RetryAfter = retryAfter(prev, cur, limit, window, elapsed)
+	return result
+}
+
+// retryAfter estimates how long until a request would be allowed, assuming no
+// other requests arrive meanwhile. Fractions of a nanosecond are rounded up so
+// a retry after exactly that long is not rejected.
+func retryAfter(prev, cur, limit int, window, elapsed time.Duration) time.Duration {
+	free := float64(limit - 1)
+	w := float64(window)
+
+	if float64(cur) > free {
+		// The current window alone is over the limit; wait for it to end and
+		// for enough of it to slide out
+		return window - elapsed + time.Duration(math.Ceil(w*(1-free/float64(cur))))
+	}
+
+	// Wait for the previous window's weight to decay enough
+	until := time.Duration(math.Ceil(w * (1 - (free-float64(cur))/float64(prev))))
+	if until < elapsed {
+		return 0
+	}
+	return until - elapsed
+}
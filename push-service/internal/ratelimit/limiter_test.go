@@ -0,0 +1,171 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestEvaluate(t *testing.T) {
+	const window = time.Minute
+
+	tests := []struct {
+		name          string
+		prev, cur     int
+		limit         int
+		elapsed       time.Duration
+		wantAllowed   bool
+		wantRemaining int
+		wantRetry     time.Duration
+	}{
+		{
+			name:          "empty windows",
+			limit:         10,
+			elapsed:       30 * time.Second,
+			wantAllowed:   true,
+			wantRemaining: 9,
+		},
+		{
+			name:          "previous window counts by its overlap",
+			prev:          10,
+			cur:           2,
+			limit:         10,
+			elapsed:       45 * time.Second,
+			wantAllowed:   true,
+			wantRemaining: 4, // 10 - (2.5 + 2) - 1, rounded down
+		},
+		{
+			name:          "last request under the limit",
+			cur:           9,
+			limit:         10,
+			elapsed:       10 * time.Second,
+			wantAllowed:   true,
+			wantRemaining: 0,
+		},
+		{
+			name:      "current window alone is full",
+			cur:       10,
+			limit:     10,
+			elapsed:   15 * time.Second,
+			wantRetry: 45*time.Second + 6*time.Second, // the window ends, then a tenth of it slides out
+		},
+		{
+			name:      "previous window decays",
+			prev:      10,
+			cur:       5,
+			limit:     10,
+			elapsed:   15 * time.Second,
+			wantRetry: 21 * time.Second, // until 36s, when 10 * 0.4 + 5 + 1 fits
+		},
+		{
+			name:      "limit of one",
+			cur:       1,
+			limit:     1,
+			elapsed:   0,
+			wantRetry: window + window,
+		},
+		{
+			name:      "at the window boundary",
+			prev:      10,
+			limit:     10,
+			elapsed:   0,
+			wantRetry: 6 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := evaluate(tt.prev, tt.cur, tt.limit, window, tt.elapsed)
+			if result.Allowed != tt.wantAllowed {
+				t.Fatalf("expected allowed %v, got %+v", tt.wantAllowed, result)
+			}
+			if result.Limit != tt.limit || result.ResetAfter != window-tt.elapsed {
+				t.Errorf("expected limit %d resetting after %s, got %+v", tt.limit, window-tt.elapsed, result)
+			}
+			if tt.wantAllowed && result.Remaining != tt.wantRemaining {
+				t.Errorf("expected %d remaining, got %d", tt.wantRemaining, result.Remaining)
+			}
+			if !tt.wantAllowed && result.RetryAfter != tt.wantRetry {
+				t.Errorf("expected retry after %s, got %s", tt.wantRetry, result.RetryAfter)
+			}
+		})
+	}
+}
+
+// TestEvaluateRetryAfterIsAccurate checks that a rejected request repeated
+// once RetryAfter has passed is allowed, and not a second earlier
+func TestEvaluateRetryAfterIsAccurate(t *testing.T) {
+	const window = time.Minute
+
+	for _, tt := range []struct{ prev, cur, limit int }{
+		{prev: 10, cur: 5, limit: 10},
+		{prev: 3, cur: 10, limit: 10},
+		{prev: 100, cur: 0, limit: 50},
+		{prev: 7, cur: 4, limit: 5},
+	} {
+		for _, elapsed := range []time.Duration{0, 15 * time.Second, 59 * time.Second} {
+			result := evaluate(tt.prev, tt.cur, tt.limit, window, elapsed)
+			if result.Allowed {
+				continue
+			}
+
+			// Replay the counters forward to the retry time
+			at := elapsed + result.RetryAfter
+			prev, cur := tt.prev, tt.cur
+			for at >= window {
+				prev, cur = cur, 0
+				at -= window
+			}
+			if !evaluate(prev, cur, tt.limit, window, at).Allowed {
+				t.Errorf("prev=%d cur=%d limit=%d elapsed=%s: expected a retry after %s to be allowed",
+					tt.prev, tt.cur, tt.limit, elapsed, result.RetryAfter)
+			}
+			if result.RetryAfter >= time.Second {
+				early := elapsed + result.RetryAfter - time.Second
+				prev, cur := tt.prev, tt.cur
+				for early >= window {
+					prev, cur = cur, 0
+					early -= window
+				}
+				if evaluate(prev, cur, tt.limit, window, early).Allowed {
+					t.Errorf("prev=%d cur=%d limit=%d elapsed=%s: expected a retry before %s to be rejected",
+						tt.prev, tt.cur, tt.limit, elapsed, result.RetryAfter)
+				}
+			}
+		}
+	}
+}
+
+func TestLocalLimiterSlidesWindow(t *testing.T) {
+	const window = time.Minute
+	now := time.Unix(0, 0).Add(100 * window)
+	l := &localLimiter{windows: make(map[string]*localWindow), now: func() time.Time { return now }}
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if result, _ := l.Allow(ctx, "key", 3, window); !result.Allowed {
+			t.Fatalf("expected request %d to be allowed", i+1)
+		}
+	}
+	if result, _ := l.Allow(ctx, "key", 3, window); result.Allowed {
+		t.Fatal("expected the fourth request to be rejected")
+	}
+	if result, _ := l.Allow(ctx, "other", 3, window); !result.Allowed {
+		t.Fatal("expected another key to have its own limit")
+	}
+
+	// Halfway through the next window, the previous one still counts 1.5
+	now = now.Add(window + window/2)
+	if result, _ := l.Allow(ctx, "key", 3, window); !result.Allowed {
+		t.Fatal("expected a request to be allowed once the window slid")
+	}
+	if result, _ := l.Allow(ctx, "key", 3, window); result.Allowed {
+		t.Fatal("expected the previous window to still count")
+	}
+
+	// Two windows later nothing counts
+	now = now.Add(2 * window)
+	if result, _ := l.Allow(ctx, "key", 3, window); !result.Allowed || result.Remaining != 2 {
+		t.Fatalf("expected stale windows to be forgotten, got %+v", result)
+	}
+}
@@ -0,0 +1,75 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepEvery is how many Allow calls pass between removals of stale windows
+const sweepEvery = 1024
+
+type localWindow struct {
+	window time.Duration
+	index  int64
+	prev   int
+	cur    int
+}
+
+// localLimiter keeps counters in process memory. Limits apply per replica.
+type localLimiter struct {
+	mu      sync.Mutex
+	windows map[string]*localWindow
+	calls   int
+	now     func() time.Time
+}
+
+func NewLocalLimiter() Limiter {
+	return &localLimiter{
+		windows: make(map[string]*localWindow),
+		now:     time.Now,
+	}
+}
+
+func (l *localLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (Result, error) {
+	now := l.now()
+	index, elapsed := windowPosition(now, window)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.calls++
+	if l.calls%sweepEvery == 0 {
+		l.sweep(now)
+	}
+
+	w, ok := l.windows[key]
+	if !ok || w.window != window {
+		w = &localWindow{window: window, index: index}
+		l.windows[key] = w
+	}
+
+	switch {
+	case w.index == index:
+	case w.index == index-1:
+		w.prev, w.cur, w.index = w.cur, 0, index
+	default:
+		w.prev, w.cur, w.index = 0, 0, index
+	}
+
+	result := evaluate(w.prev, w.cur, limit, window, elapsed)
+	if result.Allowed {
+		w.cur++
+	}
+
+	return result, nil
+}
+
+// sweep drops windows that no longer affect any decision
+func (l *localLimiter) sweep(now time.Time) {
+	for key, w := range l.windows {
+		if index, _ := windowPosition(now, w.window); w.index < index-1 {
+			delete(l.windows, key)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// slidingWindowScript checks the weighted count and increments the current
+// window in one round trip. It returns {allowed, cur, prev} with counts taken
+// before this request.
+var slidingWindowScript = redis.NewScript(`
+local cur = tonumber(redis.call('GET', KEYS[1]) or '0')
+local prev = tonumber(redis.call('GET', KEYS[2]) or '0')
+local limit = tonumber(ARGV[1])
+local weight = tonumber(ARGV[2])
+if prev * weight + cur + 1 > limit then
+  return {0, cur, prev}
+end
+redis.call('INCR', KEYS[1])
+redis.call('PEXPIRE', KEYS[1], ARGV[3])
+return {1, cur, prev}
+`)
+
+// redisLimiter shares counters between replicas through Redis
+type redisLimiter struct {
+	client *redis.Client
+	now    func() time.Time
+}
+
+func NewRedisLimiter(client *redis.Client) Limiter {
+	return &redisLimiter{client: client, now: time.Now}
+}
+
+func (l *redisLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (Result, error) {
+	index, elapsed := windowPosition(l.now(), window)
+
+	keys := []string{
+		fmt.Sprintf("ratelimit:%s:%d", key, index),
+		fmt.Sprintf("ratelimit:%s:%d", key, index-1),
+	}
+	// Keep each window long enough to be read as the previous one
+	ttl := (2 * window).Milliseconds()
+
+	values, err := slidingWindowScript.Run(ctx, l.client, keys,
+		limit,
+		strconv.FormatFloat(previousWeight(elapsed, window), 'f', 6, 64),
+		ttl,
+	).Int64Slice()
+	if err != nil {
+		return Result{}, fmt.Errorf("rate limit script failed: %w", err)
+	}
+	if len(values) != 3 {
+		return Result{}, fmt.Errorf("rate limit script returned %d values", len(values))
+	}
+
+	result := evaluate(int(values[2]), int(values[1]), limit, window, elapsed)
+	// The script's decision is authoritative if rounding puts them at odds
+	if allowed := values[0] == 1; allowed != result.Allowed {
+		result.Allowed = allowed
+		result.Remaining = 0
+		if allowed {
+			result.RetryAfter = 0
+		}
+	}
+
+	return result, nil
+}
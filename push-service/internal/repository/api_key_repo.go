@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"push-service/internal/models"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *models.APIKey, keyHash string) error
+	GetByHash(ctx context.Context, keyHash string) (*models.APIKey, error)
+	GetByID(ctx context.Context, id string) (*models.APIKey, error)
+	List(ctx context.Context) ([]models.APIKey, error)
+	Rotate(ctx context.Context, oldID string, oldValidUntil time.Time, newKey *models.APIKey, keyHash string) error
+	Revoke(ctx context.Context, id string) error
+	TouchLastUsed(ctx context.Context, id string) error
+}
+
+// apiKeyColumns is the column list scanned by scanAPIKey
+const apiKeyColumns = `id, name, prefix, scopes, created_at, last_used_at, expires_at, revoked_at, rotated_from, rate_limit`
+
+type apiKeyRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewAPIKeyRepository(db *pgxpool.Pool) APIKeyRepository {
+	return &apiKeyRepo{db: db}
+}
+
+func scanAPIKey(row pgx.Row) (*models.APIKey, error) {
+	var key models.APIKey
+	err := row.Scan(
+		&key.ID,
+		&key.Name,
+		&key.Prefix,
+		&key.Scopes,
+		&key.CreatedAt,
+		&key.LastUsedAt,
+		&key.ExpiresAt,
+		&key.RevokedAt,
+		&key.RotatedFrom,
+		&key.RateLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// apiKeyQuerier is satisfied by both the pool and a transaction
+type apiKeyQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+func insertAPIKey(ctx context.Context, q apiKeyQuerier, key *models.APIKey, keyHash string) error {
+	query := `
+		INSERT INTO api_keys (name, prefix, key_hash, scopes, expires_at, rotated_from, rate_limit)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`
+
+	return q.QueryRow(
+		ctx,
+		query,
+		key.Name,
+		key.Prefix,
+		keyHash,
+		key.Scopes,
+		key.ExpiresAt,
+		key.RotatedFrom,
+		key.RateLimit,
+	).Scan(&key.ID, &key.CreatedAt)
+}
+
+func (r *apiKeyRepo) Create(ctx context.Context, key *models.APIKey, keyHash string) error {
+	if err := insertAPIKey(ctx, r.db, key, keyHash); err != nil {
+		zap.L().Error("Failed to create API key", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// GetByHash returns the key with the given hash, or nil if there is none.
+// Revoked and expired keys are returned; callers decide whether they are usable.
+func (r *apiKeyRepo) GetByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE key_hash = $1`
+
+	key, err := scanAPIKey(r.db.QueryRow(ctx, query, keyHash))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		zap.L().Error("Failed to get API key by hash", zap.Error(err))
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// GetByID returns the key with the given id, or nil if there is none
+func (r *apiKeyRepo) GetByID(ctx context.Context, id string) (*models.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE id = $1`
+
+	key, err := scanAPIKey(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		zap.L().Error("Failed to get API key by id", zap.Error(err))
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func (r *apiKeyRepo) List(ctx context.Context) ([]models.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys ORDER BY created_at DESC`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		zap.L().Error("Failed to list API keys", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []models.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, *key)
+	}
+
+	return keys, rows.Err()
+}
+
+// Rotate creates newKey and limits the old key to oldValidUntil in one transaction
+func (r *apiKeyRepo) Rotate(ctx context.Context, oldID string, oldValidUntil time.Time, newKey *models.APIKey, keyHash string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin rotation transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, `
+		UPDATE api_keys
+		SET expires_at = LEAST(COALESCE(expires_at, $2), $2)
+		WHERE id = $1 AND revoked_at IS NULL
+	`, oldID, oldValidUntil)
+	if err != nil {
+		zap.L().Error("Failed to expire rotated API key", zap.Error(err))
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	newKey.RotatedFrom = &oldID
+	if err := insertAPIKey(ctx, tx, newKey, keyHash); err != nil {
+		zap.L().Error("Failed to create rotated API key", zap.Error(err))
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *apiKeyRepo) Revoke(ctx context.Context, id string) error {
+	query := `UPDATE api_keys SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL`
+
+	result, err := r.db.Exec(ctx, query, id)
+	if err != nil {
+		zap.L().Error("Failed to revoke API key", zap.Error(err))
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// TouchLastUsed records that the key was used, writing at most once a minute per key
+func (r *apiKeyRepo) TouchLastUsed(ctx context.Context, id string) error {
+	query := `
+		UPDATE api_keys
+		SET last_used_at = NOW()
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute')
+	`
+
+	if _, err := r.db.Exec(ctx, query, id); err != nil {
+		zap.L().Warn("Failed to update API key last used time", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
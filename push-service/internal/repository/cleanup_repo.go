@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"push-service/internal/models"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// cleanupLockKey is the Postgres advisory lock key guarding janitor runs
+const cleanupLockKey int64 = 0x70757368636c6e // "pushcln"
+
+// ErrCleanupLocked is returned when another replica is already running cleanup
+var ErrCleanupLocked = errors.New("cleanup is already running on another instance")
+
+type CleanupRepository interface {
+	Cleanup(ctx context.Context, policy models.CleanupPolicy, dryRun bool) (*models.CleanupResult, error)
+}
+
+type cleanupRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewCleanupRepository(db *pgxpool.Pool) CleanupRepository {
+	return &cleanupRepo{db: db}
+}
+
+// cleanupStep pairs the statement applying a policy with the query counting
+// the rows it would touch. Expiry steps always run with the current time as cutoff.
+type cleanupStep struct {
+	name   string
+	days   int
+	expiry bool
+	apply  string
+	count  string
+	result *int64
+}
+
+// Cleanup applies the policy in a single transaction holding a
+// transaction-scoped advisory lock, so only one replica runs it at a time.
+// A dry run counts the affected rows and rolls back.
+func (r *cleanupRepo) Cleanup(ctx context.Context, policy models.CleanupPolicy, dryRun bool) (*models.CleanupResult, error) {
+	result := &models.CleanupResult{DryRun: dryRun}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin cleanup transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var acquired bool
+	if err := tx.QueryRow(ctx, `SELECT pg_try_advisory_xact_lock($1)`, cleanupLockKey).Scan(&acquired); err != nil {
+		return nil, fmt.Errorf("failed to acquire cleanup lock: %w", err)
+	}
+	if !acquired {
+		return nil, ErrCleanupLocked
+	}
+
+	steps := []cleanupStep{
+		{
+			name:   "deactivate_stale_devices",
+			days:   policy.DeactivateAfterDays,
+			apply:  `UPDATE devices SET is_active = false, updated_at = NOW() WHERE is_active = true AND last_seen_at < $1`,
+			count:  `SELECT COUNT(*) FROM devices WHERE is_active = true AND last_seen_at < $1`,
+			result: &result.DevicesDeactivated,
+		},
+		{
+			name:   "delete_inactive_devices",
+			days:   policy.DeleteInactiveAfterDays,
+			apply:  `DELETE FROM devices WHERE is_active = false AND updated_at < $1`,
+			count:  `SELECT COUNT(*) FROM devices WHERE is_active = false AND updated_at < $1`,
+			result: &result.DevicesDeleted,
+		},
+		{
+			name:   "purge_notification_history",
+			days:   policy.HistoryRetentionDays,
+			apply:  `DELETE FROM push_notifications WHERE created_at < $1`,
+			count:  `SELECT COUNT(*) FROM push_notifications WHERE created_at < $1`,
+			result: &result.NotificationsPurged,
+		},
+		{
+			name:   "purge_expired_idempotency_keys",
+			expiry: true,
+			apply:  `DELETE FROM idempotency_keys WHERE expires_at < $1`,
+			count:  `SELECT COUNT(*) FROM idempotency_keys WHERE expires_at < $1`,
+			result: &result.IdempotencyKeysPurged,
+		},
+		{
+			name:   "purge_expired_gateway_messages",
+			expiry: true,
+			apply:  `DELETE FROM processed_gateway_messages WHERE expires_at < $1`,
+			count:  `SELECT COUNT(*) FROM processed_gateway_messages WHERE expires_at < $1`,
+			result: &result.GatewayMessagesPurged,
+		},
+	}
+
+	for _, step := range steps {
+		cutoff := time.Now()
+		if !step.expiry {
+			if step.days <= 0 {
+				continue
+			}
+			cutoff = cutoff.AddDate(0, 0, -step.days)
+		}
+
+		if err := runCleanupStep(ctx, tx, step, cutoff, dryRun); err != nil {
+			zap.L().Error("Cleanup step failed", zap.String("step", step.name), zap.Error(err))
+			return nil, fmt.Errorf("%s: %w", step.name, err)
+		}
+	}
+
+	if dryRun {
+		return result, nil
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit cleanup: %w", err)
+	}
+
+	return result, nil
+}
+
+func runCleanupStep(ctx context.Context, tx pgx.Tx, step cleanupStep, cutoff time.Time, dryRun bool) error {
+	if dryRun {
+		return tx.QueryRow(ctx, step.count, cutoff).Scan(step.result)
+	}
+
+	tag, err := tx.Exec(ctx, step.apply, cutoff)
+	if err != nil {
+		return err
+	}
+	*step.result = tag.RowsAffected()
+	return nil
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"push-service/internal/models"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+type DeferredPushRepository interface {
+	Create(ctx context.Context, pushes []models.DeferredPush) error
+	ReleaseDue(ctx context.Context, limit int, publish func(models.DeferredPush) error) (int, error)
+	ListByUser(ctx context.Context, userID string) ([]models.DeferredPush, error)
+}
+
+type deferredPushRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewDeferredPushRepository(db *pgxpool.Pool) DeferredPushRepository {
+	return &deferredPushRepo{db: db}
+}
+
+// Create stores all of pushes or none of them
+func (r *deferredPushRepo) Create(ctx context.Context, pushes []models.DeferredPush) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin deferred push transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO deferred_pushes (user_id, message, deliver_at)
+		VALUES ($1, $2, $3)
+		RETURNING id
+	`
+
+	for i := range pushes {
+		push := &pushes[i]
+		if err := tx.QueryRow(ctx, query, push.UserID, push.Message, push.DeliverAt).Scan(&push.ID); err != nil {
+			zap.L().Error("Failed to store deferred push", zap.Error(err))
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ListByUser returns the pushes held for a user, earliest delivery first
+func (r *deferredPushRepo) ListByUser(ctx context.Context, userID string) ([]models.DeferredPush, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		SELECT id, user_id, message, deliver_at
+		FROM deferred_pushes
+		WHERE user_id = $1
+		ORDER BY deliver_at
+	`, userID)
+	if err != nil {
+		zap.L().Error("Failed to list deferred pushes", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	pushes := []models.DeferredPush{}
+	for rows.Next() {
+		var push models.DeferredPush
+		if err := rows.Scan(&push.ID, &push.UserID, &push.Message, &push.DeliverAt); err != nil {
+			return nil, err
+		}
+		pushes = append(pushes, push)
+	}
+
+	return pushes, rows.Err()
+}
+
+// ReleaseDue calls publish for up to limit pushes whose delivery time has
+// passed, oldest first, and deletes those it published. Rows are locked with
+// SKIP LOCKED, so replicas release different pushes. A push is deleted only
+// after it was published; if the commit fails, it is published again later.
+func (r *deferredPushRepo) ReleaseDue(ctx context.Context, limit int, publish func(models.DeferredPush) error) (int, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin deferred push transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `
+		SELECT id, user_id, message, deliver_at
+		FROM deferred_pushes
+		WHERE deliver_at <= NOW()
+		ORDER BY deliver_at
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED
+	`, limit)
+	if err != nil {
+		zap.L().Error("Failed to get due deferred pushes", zap.Error(err))
+		return 0, err
+	}
+
+	var due []models.DeferredPush
+	for rows.Next() {
+		var push models.DeferredPush
+		if err := rows.Scan(&push.ID, &push.UserID, &push.Message, &push.DeliverAt); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		due = append(due, push)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	published := make([]string, 0, len(due))
+	var publishErr error
+	for _, push := range due {
+		if publishErr = publish(push); publishErr != nil {
+			break
+		}
+		published = append(published, push.ID)
+	}
+
+	if len(published) > 0 {
+		if _, err := tx.Exec(ctx, `DELETE FROM deferred_pushes WHERE id = ANY($1)`, published); err != nil {
+			zap.L().Error("Failed to delete released deferred pushes", zap.Error(err))
+			return 0, err
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit released deferred pushes: %w", err)
+	}
+
+	return len(published), publishErr
+}
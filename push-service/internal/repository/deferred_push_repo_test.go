@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"push-service/internal/models"
+)
+
+func TestReleaseDueKeepsFuturePushesAndUnpublishedOnes(t *testing.T) {
+	pool := newTestPool(t)
+	repo := NewDeferredPushRepository(pool)
+	ctx := context.Background()
+
+	userID := fmt.Sprintf("test-user-%d", time.Now().UnixNano())
+	t.Cleanup(func() {
+		pool.Exec(context.Background(), `DELETE FROM deferred_pushes WHERE user_id = $1`, userID)
+	})
+
+	now := time.Now()
+	err := repo.Create(ctx, []models.DeferredPush{
+		{UserID: userID, Message: json.RawMessage(`{"n":1}`), DeliverAt: now.Add(-2 * time.Minute)},
+		{UserID: userID, Message: json.RawMessage(`{"n":2}`), DeliverAt: now.Add(-time.Minute)},
+		{UserID: userID, Message: json.RawMessage(`{"n":3}`), DeliverAt: now.Add(time.Hour)},
+	})
+	if err != nil {
+		t.Fatalf("failed to store deferred pushes: %v", err)
+	}
+
+	remaining := func() int {
+		var count int
+		pool.QueryRow(ctx, `SELECT COUNT(*) FROM deferred_pushes WHERE user_id = $1`, userID).Scan(&count)
+		return count
+	}
+
+	// The second due push fails to publish, so only the first is released
+	errPublish := errors.New("publish failed")
+	calls := 0
+	released, err := repo.ReleaseDue(ctx, 10, func(push models.DeferredPush) error {
+		if push.UserID != userID {
+			return nil
+		}
+		calls++
+		if calls == 2 {
+			return errPublish
+		}
+		return nil
+	})
+	if !errors.Is(err, errPublish) {
+		t.Fatalf("expected the publish error, got %v", err)
+	}
+	if released < 1 || remaining() != 2 {
+		t.Errorf("expected the first push released and two kept, got %d released and %d kept", released, remaining())
+	}
+
+	if _, err := repo.ReleaseDue(ctx, 10, func(models.DeferredPush) error { return nil }); err != nil {
+		t.Fatalf("failed to release due pushes: %v", err)
+	}
+	if remaining() != 1 {
+		t.Errorf("expected only the future push kept, got %d", remaining())
+	}
+}
+
+func TestListByUserReturnsOnlyTheUsersPushes(t *testing.T) {
+	pool := newTestPool(t)
+	repo := NewDeferredPushRepository(pool)
+	ctx := context.Background()
+
+	userID := fmt.Sprintf("test-user-%d", time.Now().UnixNano())
+	otherUserID := userID + "-other"
+	t.Cleanup(func() {
+		pool.Exec(context.Background(), `DELETE FROM deferred_pushes WHERE user_id IN ($1, $2)`, userID, otherUserID)
+	})
+
+	now := time.Now()
+	err := repo.Create(ctx, []models.DeferredPush{
+		{UserID: userID, Message: json.RawMessage(`{"n":2}`), DeliverAt: now.Add(2 * time.Hour)},
+		{UserID: otherUserID, Message: json.RawMessage(`{"n":3}`), DeliverAt: now.Add(time.Hour)},
+		{UserID: userID, Message: json.RawMessage(`{"n":1}`), DeliverAt: now.Add(time.Hour)},
+	})
+	if err != nil {
+		t.Fatalf("failed to store deferred pushes: %v", err)
+	}
+
+	pushes, err := repo.ListByUser(ctx, userID)
+	if err != nil {
+		t.Fatalf("failed to list deferred pushes: %v", err)
+	}
+	if len(pushes) != 2 {
+		t.Fatalf("expected 2 deferred pushes, got %d", len(pushes))
+	}
+	if !pushes[0].DeliverAt.Before(pushes[1].DeliverAt) {
+		t.Errorf("expected the earliest push first, got %v then %v", pushes[0].DeliverAt, pushes[1].DeliverAt)
+	}
+}
@@ -2,7 +2,10 @@ package repository
 
 import (
 	"context"
+	"fmt"
 	"push-service/internal/models"
+	"push-service/internal/segment"
+	"strings"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -11,12 +14,25 @@ import (
 
 type DeviceRepository interface {
 	Create(ctx context.Context, device *models.Device) error
-	GetByToken(ctx context.Context, token string) (*models.Device, error)
+	Upsert(ctx context.Context, device *models.Device) error
+	UpsertBatch(ctx context.Context, devices []models.Device) error
+	GetByToken(ctx context.Context, app, token string) (*models.Device, error)
 	GetByUserID(ctx context.Context, userID string) ([]models.Device, error)
-	UpdateStatus(ctx context.Context, token string, isActive bool) error
-	Delete(ctx context.Context, token string) error
+	Search(ctx context.Context, query models.DeviceQuery) (*models.DevicePage, error)
+	StreamSegment(ctx context.Context, seg *segment.Segment, pageSize int, fn func([]models.Device) error) error
+	SetTags(ctx context.Context, app, token string, tags []string) error
+	UpdateStatus(ctx context.Context, app, token string, isActive bool) error
+	UpdateStatusForUser(ctx context.Context, userID, app, token string, isActive bool) error
+	UpdateStatusBatch(ctx context.Context, tokens []string, isActive bool) ([]string, error)
+	DeactivateUserDevices(ctx context.Context, userID string, filter models.UserDeviceFilter) (int64, error)
+	TokenRegistrations(ctx context.Context, userID string, tokens []string) (map[string]models.TokenRegistration, error)
+	MarkSeen(ctx context.Context, userID string, tokens []string) error
+	Delete(ctx context.Context, app, token string) error
 }
 
+// deviceColumns is the column list scanned by scanDevice
+const deviceColumns = `id, user_id, app, token, platform, locale, timezone, is_active, metadata, tags, last_seen_at, created_at, updated_at`
+
 type deviceRepo struct {
 	db *pgxpool.Pool
 }
@@ -25,21 +41,79 @@ func NewDeviceRepository(db *pgxpool.Pool) DeviceRepository {
 	return &deviceRepo{db: db}
 }
 
+// metadataOrEmpty avoids writing a JSON null into the NOT NULL metadata column
+func metadataOrEmpty(metadata map[string]string) map[string]string {
+	if metadata == nil {
+		return map[string]string{}
+	}
+	return metadata
+}
+
+// tagsOrEmpty avoids writing NULL into the NOT NULL tags column
+func tagsOrEmpty(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
+
+// scanDevice scans a row selected with deviceColumns
+func scanDevice(row pgx.Row) (*models.Device, error) {
+	var device models.Device
+	err := row.Scan(
+		&device.ID,
+		&device.UserID,
+		&device.App,
+		&device.Token,
+		&device.Platform,
+		&device.Locale,
+		&device.Timezone,
+		&device.IsActive,
+		&device.Metadata,
+		&device.Tags,
+		&device.LastSeenAt,
+		&device.CreatedAt,
+		&device.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &device, nil
+}
+
+// appOrDefault returns app, or DefaultApp if it is empty. Tokens are unique
+// per app, so lookups by token are made within one app.
+func appOrDefault(app string) string {
+	if app == "" {
+		return models.DefaultApp
+	}
+	return app
+}
+
 func (r *deviceRepo) Create(ctx context.Context, device *models.Device) error {
+	if device.App == "" {
+		device.App = models.DefaultApp
+	}
+
 	query := `
-		INSERT INTO devices (user_id, token, platform, is_active)
-		VALUES ($1, $2, $3, $4)
-		RETURNING id, created_at, updated_at
+		INSERT INTO devices (user_id, app, token, platform, locale, timezone, is_active, metadata, tags)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, last_seen_at, created_at, updated_at
 	`
 
 	err := r.db.QueryRow(
 		ctx,
 		query,
 		device.UserID,
+		device.App,
 		device.Token,
 		device.Platform,
+		device.Locale,
+		device.Timezone,
 		device.IsActive,
-	).Scan(&device.ID, &device.CreatedAt, &device.UpdatedAt)
+		metadataOrEmpty(device.Metadata),
+		tagsOrEmpty(device.Tags),
+	).Scan(&device.ID, &device.LastSeenAt, &device.CreatedAt, &device.UpdatedAt)
 
 	if err != nil {
 		zap.L().Error("Failed to create device", zap.Error(err))
@@ -49,24 +123,154 @@ func (r *deviceRepo) Create(ctx context.Context, device *models.Device) error {
 	return nil
 }
 
-func (r *deviceRepo) GetByToken(ctx context.Context, token string) (*models.Device, error) {
+// Upsert registers a device atomically. An existing (app, token) row is
+// reassigned to the new user and platform and reactivated, so a token never
+// appears more than once per app regardless of concurrent registrations.
+func (r *deviceRepo) Upsert(ctx context.Context, device *models.Device) error {
+	if device.App == "" {
+		device.App = models.DefaultApp
+	}
+
 	query := `
-		SELECT id, user_id, token, platform, is_active, created_at, updated_at
-		FROM devices
-		WHERE token = $1 AND is_active = true
+		INSERT INTO devices (user_id, app, token, platform, locale, timezone, is_active, metadata, tags)
+		VALUES ($1, $2, $3, $4, $5, $6, true, $7, $8)
+		ON CONFLICT (app, token) DO UPDATE
+		SET user_id = EXCLUDED.user_id,
+			platform = EXCLUDED.platform,
+			locale = COALESCE(NULLIF(EXCLUDED.locale, ''), devices.locale),
+			timezone = COALESCE(NULLIF(EXCLUDED.timezone, ''), devices.timezone),
+			is_active = true,
+			metadata = devices.metadata || EXCLUDED.metadata,
+			tags = ARRAY(SELECT DISTINCT unnest(devices.tags || EXCLUDED.tags)),
+			last_seen_at = NOW(),
+			updated_at = NOW()
+		RETURNING id, locale, timezone, is_active, metadata, tags, last_seen_at, created_at, updated_at
 	`
 
-	var device models.Device
-	err := r.db.QueryRow(ctx, query, token).Scan(
-		&device.ID,
-		&device.UserID,
-		&device.Token,
-		&device.Platform,
-		&device.IsActive,
-		&device.CreatedAt,
-		&device.UpdatedAt,
-	)
+	err := r.db.QueryRow(
+		ctx,
+		query,
+		device.UserID,
+		device.App,
+		device.Token,
+		device.Platform,
+		device.Locale,
+		device.Timezone,
+		metadataOrEmpty(device.Metadata),
+		tagsOrEmpty(device.Tags),
+	).Scan(&device.ID, &device.Locale, &device.Timezone, &device.IsActive, &device.Metadata, &device.Tags, &device.LastSeenAt, &device.CreatedAt, &device.UpdatedAt)
+
+	if err != nil {
+		zap.L().Error("Failed to upsert device", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// upsertBatchColumns is the number of parameters bound per device by UpsertBatch
+const upsertBatchColumns = 8
+
+// maxUpsertBatchRows keeps each UpsertBatch statement within Postgres's limit
+// of 65535 bound parameters
+const maxUpsertBatchRows = 65535 / upsertBatchColumns
+
+// UpsertBatch registers many devices with multi-row upserts, using the same
+// conflict handling as Upsert. Each (app, token) pair may appear only once.
+// Batches too large for one statement are split, in a single transaction.
+// Devices are updated in place with their stored values.
+func (r *deviceRepo) UpsertBatch(ctx context.Context, devices []models.Device) error {
+	if len(devices) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin device batch transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for start := 0; start < len(devices); start += maxUpsertBatchRows {
+		end := min(start+maxUpsertBatchRows, len(devices))
+		if err := upsertDeviceChunk(ctx, tx, devices[start:end]); err != nil {
+			zap.L().Error("Failed to upsert device batch", zap.Int("count", len(devices)), zap.Error(err))
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
 
+// upsertDeviceChunk upserts devices with one statement and updates them in
+// place with their stored values
+func upsertDeviceChunk(ctx context.Context, tx pgx.Tx, devices []models.Device) error {
+	values := make([]string, 0, len(devices))
+	args := make([]any, 0, len(devices)*upsertBatchColumns)
+	index := make(map[string]int, len(devices))
+
+	for i := range devices {
+		device := &devices[i]
+		if device.App == "" {
+			device.App = models.DefaultApp
+		}
+		index[device.App+"\x00"+device.Token] = i
+
+		n := len(args)
+		values = append(values, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, true, $%d, $%d)", n+1, n+2, n+3, n+4, n+5, n+6, n+7, n+8))
+		args = append(args,
+			device.UserID,
+			device.App,
+			device.Token,
+			device.Platform,
+			device.Locale,
+			device.Timezone,
+			metadataOrEmpty(device.Metadata),
+			tagsOrEmpty(device.Tags),
+		)
+	}
+
+	query := `
+		INSERT INTO devices (user_id, app, token, platform, locale, timezone, is_active, metadata, tags)
+		VALUES ` + strings.Join(values, ", ") + `
+		ON CONFLICT (app, token) DO UPDATE
+		SET user_id = EXCLUDED.user_id,
+			platform = EXCLUDED.platform,
+			locale = COALESCE(NULLIF(EXCLUDED.locale, ''), devices.locale),
+			timezone = COALESCE(NULLIF(EXCLUDED.timezone, ''), devices.timezone),
+			is_active = true,
+			metadata = devices.metadata || EXCLUDED.metadata,
+			tags = ARRAY(SELECT DISTINCT unnest(devices.tags || EXCLUDED.tags)),
+			last_seen_at = NOW(),
+			updated_at = NOW()
+		RETURNING ` + deviceColumns
+
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		device, err := scanDevice(rows)
+		if err != nil {
+			return err
+		}
+		if i, ok := index[device.App+"\x00"+device.Token]; ok {
+			devices[i] = *device
+		}
+	}
+
+	return rows.Err()
+}
+
+func (r *deviceRepo) GetByToken(ctx context.Context, app, token string) (*models.Device, error) {
+	query := `
+		SELECT ` + deviceColumns + `
+		FROM devices
+		WHERE app = $1 AND token = $2 AND is_active = true
+	`
+
+	device, err := scanDevice(r.db.QueryRow(ctx, query, appOrDefault(app), token))
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, nil
@@ -75,12 +279,12 @@ func (r *deviceRepo) GetByToken(ctx context.Context, token string) (*models.Devi
 		return nil, err
 	}
 
-	return &device, nil
+	return device, nil
 }
 
 func (r *deviceRepo) GetByUserID(ctx context.Context, userID string) ([]models.Device, error) {
 	query := `
-		SELECT id, user_id, token, platform, is_active, created_at, updated_at
+		SELECT ` + deviceColumns + `
 		FROM devices
 		WHERE user_id = $1 AND is_active = true
 		ORDER BY created_at DESC
@@ -95,33 +299,24 @@ func (r *deviceRepo) GetByUserID(ctx context.Context, userID string) ([]models.D
 
 	var devices []models.Device
 	for rows.Next() {
-		var device models.Device
-		err := rows.Scan(
-			&device.ID,
-			&device.UserID,
-			&device.Token,
-			&device.Platform,
-			&device.IsActive,
-			&device.CreatedAt,
-			&device.UpdatedAt,
-		)
+		device, err := scanDevice(rows)
 		if err != nil {
 			return nil, err
 		}
-		devices = append(devices, device)
+		devices = append(devices, *device)
 	}
 
-	return devices, nil
+	return devices, rows.Err()
 }
 
-func (r *deviceRepo) UpdateStatus(ctx context.Context, token string, isActive bool) error {
+func (r *deviceRepo) UpdateStatus(ctx context.Context, app, token string, isActive bool) error {
 	query := `
 		UPDATE devices 
 		SET is_active = $1, updated_at = NOW()
-		WHERE token = $2
+		WHERE app = $2 AND token = $3
 	`
 
-	result, err := r.db.Exec(ctx, query, isActive, token)
+	result, err := r.db.Exec(ctx, query, isActive, appOrDefault(app), token)
 	if err != nil {
 		zap.L().Error("Failed to update device status", zap.Error(err))
 		return err
@@ -134,10 +329,135 @@ func (r *deviceRepo) UpdateStatus(ctx context.Context, token string, isActive bo
 	return nil
 }
 
-func (r *deviceRepo) Delete(ctx context.Context, token string) error {
-	query := `DELETE FROM devices WHERE token = $1`
+// UpdateStatusForUser is UpdateStatus restricted to devices owned by userID
+func (r *deviceRepo) UpdateStatusForUser(ctx context.Context, userID, app, token string, isActive bool) error {
+	query := `
+		UPDATE devices
+		SET is_active = $1, updated_at = NOW()
+		WHERE app = $2 AND token = $3 AND user_id = $4
+	`
 
-	result, err := r.db.Exec(ctx, query, token)
+	result, err := r.db.Exec(ctx, query, isActive, appOrDefault(app), token, userID)
+	if err != nil {
+		zap.L().Error("Failed to update device status", zap.Error(err))
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// UpdateStatusBatch is UpdateStatus for many tokens in one statement, in
+// every app each token is registered in. It returns the tokens that matched at
+// least one device.
+func (r *deviceRepo) UpdateStatusBatch(ctx context.Context, tokens []string, isActive bool) ([]string, error) {
+	query := `
+		UPDATE devices
+		SET is_active = $1, updated_at = NOW()
+		WHERE token = ANY($2)
+		RETURNING token
+	`
+
+	rows, err := r.db.Query(ctx, query, isActive, tokens)
+	if err != nil {
+		zap.L().Error("Failed to update device status batch", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var matched []string
+	for rows.Next() {
+		var token string
+		if err := rows.Scan(&token); err != nil {
+			return nil, err
+		}
+		matched = append(matched, token)
+	}
+
+	return matched, rows.Err()
+}
+
+// DeactivateUserDevices soft deletes the user's active devices that match filter
+// and returns how many were deactivated
+func (r *deviceRepo) DeactivateUserDevices(ctx context.Context, userID string, filter models.UserDeviceFilter) (int64, error) {
+	query := `
+		UPDATE devices
+		SET is_active = false, updated_at = NOW()
+		WHERE user_id = $1 AND is_active = true
+			AND ($2 = '' OR token <> $2)
+			AND (cardinality($3::text[]) = 0 OR platform = ANY($3))
+	`
+
+	// A nil slice would be sent as NULL, which matches no platform
+	platforms := filter.Platforms
+	if platforms == nil {
+		platforms = []string{}
+	}
+
+	result, err := r.db.Exec(ctx, query, userID, filter.ExceptToken, platforms)
+	if err != nil {
+		zap.L().Error("Failed to deactivate user devices", zap.Error(err))
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// TokenRegistrations returns the registration of each of tokens that is known
+// to the service. Active is false for tokens that were unregistered or moved
+// to another user; tokens that were never registered are absent from the map.
+func (r *deviceRepo) TokenRegistrations(ctx context.Context, userID string, tokens []string) (map[string]models.TokenRegistration, error) {
+	query := `
+		SELECT DISTINCT ON (token) token, app, platform, locale, timezone, (is_active AND user_id = $1) AS active
+		FROM devices
+		WHERE token = ANY($2)
+		ORDER BY token, active DESC
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, tokens)
+	if err != nil {
+		zap.L().Error("Failed to get token registrations", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	registrations := make(map[string]models.TokenRegistration, len(tokens))
+	for rows.Next() {
+		var token string
+		var registration models.TokenRegistration
+		if err := rows.Scan(&token, &registration.App, &registration.Platform, &registration.Locale, &registration.Timezone, &registration.Active); err != nil {
+			return nil, err
+		}
+		registrations[token] = registration
+	}
+
+	return registrations, rows.Err()
+}
+
+// MarkSeen sets last_seen_at on userID's active devices with the given tokens,
+// so devices that are delivered to are not cleaned up as stale
+func (r *deviceRepo) MarkSeen(ctx context.Context, userID string, tokens []string) error {
+	query := `
+		UPDATE devices
+		SET last_seen_at = NOW()
+		WHERE user_id = $1 AND token = ANY($2) AND is_active = true
+	`
+
+	if _, err := r.db.Exec(ctx, query, userID, tokens); err != nil {
+		zap.L().Error("Failed to mark devices seen", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *deviceRepo) Delete(ctx context.Context, app, token string) error {
+	query := `DELETE FROM devices WHERE app = $1 AND token = $2`
+
+	result, err := r.db.Exec(ctx, query, appOrDefault(app), token)
 	if err != nil {
 		zap.L().Error("Failed to delete device", zap.Error(err))
 		return err
@@ -148,4 +468,98 @@ func (r *deviceRepo) Delete(ctx context.Context, token string) error {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// SetTags replaces the tags on the device registered with the token in app
+func (r *deviceRepo) SetTags(ctx context.Context, app, token string, tags []string) error {
+	query := `
+		UPDATE devices
+		SET tags = ARRAY(SELECT DISTINCT unnest($1::text[])), updated_at = NOW()
+		WHERE app = $2 AND token = $3
+	`
+
+	result, err := r.db.Exec(ctx, query, tagsOrEmpty(tags), appOrDefault(app), token)
+	if err != nil {
+		zap.L().Error("Failed to set device tags", zap.Error(err))
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// StreamSegment calls fn with successive pages of the active devices matching
+// the segment. Each page holds every matching device of up to pageSize users,
+// ordered by user, so a user's devices are never split across pages.
+func (r *deviceRepo) StreamSegment(ctx context.Context, seg *segment.Segment, pageSize int, fn func([]models.Device) error) error {
+	var lastUserID string
+
+	for {
+		b := &segment.Builder{}
+		condition := "devices.is_active = true AND " + seg.SQL(b)
+		userCondition := condition
+		if lastUserID != "" {
+			userCondition += " AND devices.user_id > " + b.Arg(lastUserID)
+		}
+
+		query := `
+			WITH page_users AS (
+				SELECT DISTINCT devices.user_id
+				FROM devices
+				WHERE ` + userCondition + `
+				ORDER BY devices.user_id
+				LIMIT ` + b.Arg(pageSize) + `
+			)
+			SELECT ` + deviceColumns + `
+			FROM devices
+			WHERE devices.user_id IN (SELECT user_id FROM page_users) AND ` + condition + `
+			ORDER BY devices.user_id, devices.id`
+
+		devices, err := r.queryDevices(ctx, query, b.Args...)
+		if err != nil {
+			zap.L().Error("Failed to stream segment devices", zap.String("segment", seg.String()), zap.Error(err))
+			return err
+		}
+		if len(devices) == 0 {
+			return nil
+		}
+
+		if err := fn(devices); err != nil {
+			return err
+		}
+
+		users := 1
+		for i := 1; i < len(devices); i++ {
+			if devices[i].UserID != devices[i-1].UserID {
+				users++
+			}
+		}
+		if users < pageSize {
+			return nil
+		}
+		lastUserID = devices[len(devices)-1].UserID
+	}
+}
+
+// queryDevices runs a query selecting deviceColumns and scans every row
+func (r *deviceRepo) queryDevices(ctx context.Context, query string, args ...any) ([]models.Device, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var devices []models.Device
+	for rows.Next() {
+		device, err := scanDevice(rows)
+		if err != nil {
+			return nil, err
+		}
+		devices = append(devices, *device)
+	}
+
+	return devices, rows.Err()
+}
@@ -0,0 +1,338 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"push-service/internal/models"
+	"push-service/internal/segment"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// newTestPool connects to TEST_DATABASE_URL, which must point at a migrated
+// database. Tests are skipped when it is not set.
+func newTestPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	pool, err := pgxpool.New(context.Background(), url)
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	return pool
+}
+
+func TestUpsertConcurrentRegistrationsLeaveOneRow(t *testing.T) {
+	pool := newTestPool(t)
+	repo := NewDeviceRepository(pool)
+	ctx := context.Background()
+
+	token := fmt.Sprintf("test-token-%d", time.Now().UnixNano())
+	t.Cleanup(func() {
+		pool.Exec(context.Background(), `DELETE FROM devices WHERE token = $1`, token)
+	})
+
+	const workers = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- repo.Upsert(ctx, &models.Device{
+				UserID:   fmt.Sprintf("user-%d", i),
+				Token:    token,
+				Platform: "android",
+			})
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("upsert failed: %v", err)
+		}
+	}
+
+	var count int
+	if err := pool.QueryRow(ctx, `SELECT COUNT(*) FROM devices WHERE token = $1`, token).Scan(&count); err != nil {
+		t.Fatalf("failed to count devices: %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("expected exactly 1 row for token, got %d", count)
+	}
+}
+
+func TestUpsertReactivatesAndReassignsDevice(t *testing.T) {
+	pool := newTestPool(t)
+	repo := NewDeviceRepository(pool)
+	ctx := context.Background()
+
+	token := fmt.Sprintf("test-token-%d", time.Now().UnixNano())
+	t.Cleanup(func() {
+		pool.Exec(context.Background(), `DELETE FROM devices WHERE token = $1`, token)
+	})
+
+	first := &models.Device{UserID: "user-a", Token: token, Platform: "ios"}
+	if err := repo.Upsert(ctx, first); err != nil {
+		t.Fatalf("initial upsert failed: %v", err)
+	}
+	if err := repo.UpdateStatus(ctx, "", token, false); err != nil {
+		t.Fatalf("failed to deactivate device: %v", err)
+	}
+
+	second := &models.Device{UserID: "user-b", Token: token, Platform: "ios"}
+	if err := repo.Upsert(ctx, second); err != nil {
+		t.Fatalf("re-registration upsert failed: %v", err)
+	}
+
+	if second.ID != first.ID {
+		t.Errorf("expected the existing row to be reused, got ids %s and %s", first.ID, second.ID)
+	}
+	if !second.IsActive {
+		t.Error("expected re-registered device to be active")
+	}
+
+	device, err := repo.GetByToken(ctx, "", token)
+	if err != nil {
+		t.Fatalf("failed to get device: %v", err)
+	}
+	if device == nil || device.UserID != "user-b" {
+		t.Fatalf("expected device to belong to user-b, got %+v", device)
+	}
+}
+
+func TestTokenLookupsStayWithinTheirApp(t *testing.T) {
+	pool := newTestPool(t)
+	repo := NewDeviceRepository(pool)
+	ctx := context.Background()
+
+	token := fmt.Sprintf("test-token-%d", time.Now().UnixNano())
+	t.Cleanup(func() {
+		pool.Exec(context.Background(), `DELETE FROM devices WHERE token = $1`, token)
+	})
+
+	for _, app := range []string{"", "other-app"} {
+		if err := repo.Upsert(ctx, &models.Device{UserID: "user-a", App: app, Token: token, Platform: "ios"}); err != nil {
+			t.Fatalf("upsert in app %q failed: %v", app, err)
+		}
+	}
+
+	if err := repo.SetTags(ctx, "other-app", token, []string{"beta"}); err != nil {
+		t.Fatalf("failed to set tags: %v", err)
+	}
+	if err := repo.UpdateStatus(ctx, "other-app", token, false); err != nil {
+		t.Fatalf("failed to deactivate device: %v", err)
+	}
+
+	device, err := repo.GetByToken(ctx, "", token)
+	if err != nil {
+		t.Fatalf("failed to get device: %v", err)
+	}
+	if device == nil || device.App != models.DefaultApp || len(device.Tags) != 0 {
+		t.Fatalf("expected the default app's device to be untouched, got %+v", device)
+	}
+	if device, err := repo.GetByToken(ctx, "other-app", token); err != nil || device != nil {
+		t.Fatalf("expected the other app's device to be inactive, got %+v, %v", device, err)
+	}
+
+	if err := repo.Delete(ctx, "other-app", token); err != nil {
+		t.Fatalf("failed to delete device: %v", err)
+	}
+	if device, err := repo.GetByToken(ctx, "", token); err != nil || device == nil {
+		t.Fatalf("expected the default app's device to survive the delete, got %+v, %v", device, err)
+	}
+}
+
+func TestUpsertKeepsLocaleWhenNotReported(t *testing.T) {
+	pool := newTestPool(t)
+	repo := NewDeviceRepository(pool)
+	ctx := context.Background()
+
+	token := fmt.Sprintf("test-token-%d", time.Now().UnixNano())
+	t.Cleanup(func() {
+		pool.Exec(context.Background(), `DELETE FROM devices WHERE token = $1`, token)
+	})
+
+	if err := repo.Upsert(ctx, &models.Device{UserID: "user-a", Token: token, Platform: "android", Locale: "pt-BR"}); err != nil {
+		t.Fatalf("initial upsert failed: %v", err)
+	}
+
+	device := &models.Device{UserID: "user-a", Token: token, Platform: "android"}
+	if err := repo.Upsert(ctx, device); err != nil {
+		t.Fatalf("re-registration upsert failed: %v", err)
+	}
+	if device.Locale != "pt-BR" {
+		t.Errorf("expected locale pt-BR to be kept, got %q", device.Locale)
+	}
+
+	registrations, err := repo.TokenRegistrations(ctx, "user-a", []string{token})
+	if err != nil {
+		t.Fatalf("failed to get token registrations: %v", err)
+	}
+	if registrations[token].Locale != "pt-BR" {
+		t.Errorf("expected registration locale pt-BR, got %q", registrations[token].Locale)
+	}
+}
+
+func TestUpsertBatchRegistersAndUpdatesInPlace(t *testing.T) {
+	pool := newTestPool(t)
+	repo := NewDeviceRepository(pool)
+	ctx := context.Background()
+
+	prefix := fmt.Sprintf("test-batch-%d", time.Now().UnixNano())
+	existing := prefix + "-existing"
+	fresh := prefix + "-fresh"
+	t.Cleanup(func() {
+		pool.Exec(context.Background(), `DELETE FROM devices WHERE token LIKE $1`, prefix+"%")
+	})
+
+	first := &models.Device{UserID: "user-a", Token: existing, Platform: "ios", Tags: []string{"beta"}}
+	if err := repo.Upsert(ctx, first); err != nil {
+		t.Fatalf("initial upsert failed: %v", err)
+	}
+	if err := repo.UpdateStatus(ctx, "", existing, false); err != nil {
+		t.Fatalf("failed to deactivate device: %v", err)
+	}
+
+	devices := []models.Device{
+		{UserID: "user-b", Token: existing, Platform: "ios", Tags: []string{"vip"}},
+		{UserID: "user-c", Token: fresh, Platform: "android"},
+	}
+	if err := repo.UpsertBatch(ctx, devices); err != nil {
+		t.Fatalf("batch upsert failed: %v", err)
+	}
+
+	if devices[0].ID != first.ID {
+		t.Errorf("expected the existing row to be reused, got ids %s and %s", first.ID, devices[0].ID)
+	}
+	if !devices[0].IsActive || devices[0].UserID != "user-b" {
+		t.Errorf("expected existing device to be reactivated for user-b, got %+v", devices[0])
+	}
+	if len(devices[0].Tags) != 2 {
+		t.Errorf("expected tags to be merged, got %v", devices[0].Tags)
+	}
+	if devices[1].ID == "" || devices[1].App != models.DefaultApp {
+		t.Errorf("expected new device to be stored with the default app, got %+v", devices[1])
+	}
+
+	matched, err := repo.UpdateStatusBatch(ctx, []string{existing, fresh, prefix + "-missing"}, false)
+	if err != nil {
+		t.Fatalf("batch status update failed: %v", err)
+	}
+	if len(matched) != 2 {
+		t.Fatalf("expected 2 matched tokens, got %v", matched)
+	}
+}
+
+func TestUpsertBatchSplitsBatchesOverTheParameterLimit(t *testing.T) {
+	pool := newTestPool(t)
+	repo := NewDeviceRepository(pool)
+	ctx := context.Background()
+
+	prefix := fmt.Sprintf("test-large-batch-%d", time.Now().UnixNano())
+	t.Cleanup(func() {
+		pool.Exec(context.Background(), `DELETE FROM devices WHERE token LIKE $1`, prefix+"%")
+	})
+
+	devices := make([]models.Device, maxUpsertBatchRows+1)
+	for i := range devices {
+		devices[i] = models.Device{UserID: "user-a", Token: fmt.Sprintf("%s-%d", prefix, i), Platform: "android"}
+	}
+	if err := repo.UpsertBatch(ctx, devices); err != nil {
+		t.Fatalf("batch upsert failed: %v", err)
+	}
+
+	last := devices[len(devices)-1]
+	if last.ID == "" {
+		t.Errorf("expected the device in the second statement to be stored, got %+v", last)
+	}
+}
+
+func TestStreamSegmentPagesByUser(t *testing.T) {
+	pool := newTestPool(t)
+	repo := NewDeviceRepository(pool)
+	ctx := context.Background()
+
+	prefix := fmt.Sprintf("test-segment-%d", time.Now().UnixNano())
+	t.Cleanup(func() {
+		pool.Exec(context.Background(), `DELETE FROM devices WHERE token LIKE $1`, prefix+"%")
+	})
+
+	// user-a's devices outnumber the page size
+	for i, userID := range []string{"user-a", "user-a", "user-a", "user-b"} {
+		device := &models.Device{UserID: prefix + "-" + userID, Token: fmt.Sprintf("%s-%d", prefix, i), Platform: "ios", Tags: []string{prefix}}
+		if err := repo.Upsert(ctx, device); err != nil {
+			t.Fatalf("upsert failed: %v", err)
+		}
+	}
+
+	seg, err := segment.Parse("tag:" + prefix)
+	if err != nil {
+		t.Fatalf("failed to parse segment: %v", err)
+	}
+
+	var pages [][]models.Device
+	err = repo.StreamSegment(ctx, seg, 1, func(devices []models.Device) error {
+		pages = append(pages, devices)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to stream segment: %v", err)
+	}
+
+	if len(pages) != 2 || len(pages[0]) != 3 || len(pages[1]) != 1 {
+		t.Fatalf("expected pages of 3 and 1 devices, got %v", pages)
+	}
+	if pages[1][0].UserID != prefix+"-user-b" {
+		t.Errorf("expected user-b on the second page, got %s", pages[1][0].UserID)
+	}
+}
+
+func TestDecodeDeviceCursorRejectsForeignCursors(t *testing.T) {
+	valid := deviceCursor{
+		SortBy:    models.DeviceSortCreatedAt,
+		SortValue: time.Now().UTC(),
+		ID:        "6f1c2b8e-4a4e-4c4f-9b1a-2d3e4f5a6b7c",
+	}
+	if _, err := decodeDeviceCursor(encodeDeviceCursor(valid), models.DeviceSortCreatedAt, false); err != nil {
+		t.Fatalf("expected a cursor to decode for its own sort, got %v", err)
+	}
+
+	notUUID := valid
+	notUUID.ID = "not-a-uuid"
+	otherColumn := valid
+	otherColumn.SortBy = models.DeviceSortLastSeenAt
+
+	tests := []struct {
+		name       string
+		cursor     string
+		descending bool
+	}{
+		{name: "not base64", cursor: "%%%"},
+		{name: "id is not a uuid", cursor: encodeDeviceCursor(notUUID)},
+		{name: "other sort column", cursor: encodeDeviceCursor(otherColumn)},
+		{name: "other direction", cursor: encodeDeviceCursor(valid), descending: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodeDeviceCursor(tt.cursor, models.DeviceSortCreatedAt, tt.descending); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("expected ErrInvalidCursor, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"push-service/internal/models"
+	"push-service/internal/segment"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ErrInvalidCursor is returned when a search cursor cannot be decoded or was
+// issued for a different sort order
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// deviceCursor is the keyset position of the last device on a page, and the
+// order it was taken in
+type deviceCursor struct {
+	SortBy     string    `json:"s"`
+	Descending bool      `json:"d,omitempty"`
+	SortValue  time.Time `json:"v"`
+	ID         string    `json:"id"`
+}
+
+func encodeDeviceCursor(cursor deviceCursor) string {
+	raw, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodeDeviceCursor decodes a cursor, which must have been issued for the
+// same sort column and direction
+func decodeDeviceCursor(encoded, sortBy string, descending bool) (*deviceCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cursor deviceCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if _, err := uuid.Parse(cursor.ID); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if cursor.SortBy != sortBy || cursor.Descending != descending {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+// deviceQueryBuilder accumulates WHERE conditions and their positional arguments
+type deviceQueryBuilder struct {
+	segment.Builder
+	conditions []string
+}
+
+func (b *deviceQueryBuilder) where(format string, values ...any) {
+	placeholders := make([]any, len(values))
+	for i, value := range values {
+		placeholders[i] = b.Arg(value)
+	}
+	b.conditions = append(b.conditions, fmt.Sprintf(format, placeholders...))
+}
+
+func (b *deviceQueryBuilder) whereMetadata(filter models.MetadataFilter) error {
+	condition, err := segment.Compare(&b.Builder, "metadata ->> "+b.Arg(filter.Key), filter.Op, filter.Value)
+	if err != nil {
+		return err
+	}
+	b.conditions = append(b.conditions, condition)
+	return nil
+}
+
+// Search returns one page of devices matching the query, ordered by the sort
+// column and id so that pages are stable while rows are inserted
+func (r *deviceRepo) Search(ctx context.Context, query models.DeviceQuery) (*models.DevicePage, error) {
+	sortColumn := models.DeviceSortCreatedAt
+	if query.SortBy == models.DeviceSortLastSeenAt {
+		sortColumn = models.DeviceSortLastSeenAt
+	}
+	direction, comparison := "ASC", ">"
+	if query.Descending {
+		direction, comparison = "DESC", "<"
+	}
+
+	b := &deviceQueryBuilder{}
+	if query.UserID != "" {
+		b.where("user_id = %s", query.UserID)
+	}
+	if query.Platform != "" {
+		b.where("platform = %s", query.Platform)
+	}
+	if query.App != "" {
+		b.where("app = %s", query.App)
+	}
+	if query.IsActive != nil {
+		b.where("is_active = %s", *query.IsActive)
+	}
+	if query.CreatedAfter != nil {
+		b.where("created_at >= %s", *query.CreatedAfter)
+	}
+	if query.CreatedBefore != nil {
+		b.where("created_at < %s", *query.CreatedBefore)
+	}
+	if query.LastSeenAfter != nil {
+		b.where("last_seen_at >= %s", *query.LastSeenAfter)
+	}
+	if query.LastSeenBefore != nil {
+		b.where("last_seen_at < %s", *query.LastSeenBefore)
+	}
+	for _, filter := range query.Metadata {
+		if err := b.whereMetadata(filter); err != nil {
+			return nil, err
+		}
+	}
+	if query.Cursor != "" {
+		cursor, err := decodeDeviceCursor(query.Cursor, sortColumn, query.Descending)
+		if err != nil {
+			return nil, err
+		}
+		b.where("("+sortColumn+", id) "+comparison+" (%s, %s)", cursor.SortValue, cursor.ID)
+	}
+
+	sql := `SELECT ` + deviceColumns + ` FROM devices`
+	if len(b.conditions) > 0 {
+		sql += ` WHERE ` + strings.Join(b.conditions, " AND ")
+	}
+	// Fetch one extra row to learn whether another page exists
+	sql += fmt.Sprintf(` ORDER BY %s %s, id %s LIMIT %s`, sortColumn, direction, direction, b.Arg(query.Limit+1))
+
+	rows, err := r.db.Query(ctx, sql, b.Args...)
+	if err != nil {
+		zap.L().Error("Failed to search devices", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	page := &models.DevicePage{Devices: []models.Device{}}
+	for rows.Next() {
+		device, err := scanDevice(rows)
+		if err != nil {
+			return nil, err
+		}
+		page.Devices = append(page.Devices, *device)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(page.Devices) > query.Limit {
+		page.Devices = page.Devices[:query.Limit]
+		last := page.Devices[len(page.Devices)-1]
+		sortValue := last.CreatedAt
+		if sortColumn == models.DeviceSortLastSeenAt {
+			sortValue = last.LastSeenAt
+		}
+		page.NextCursor = encodeDeviceCursor(deviceCursor{
+			SortBy:     sortColumn,
+			Descending: query.Descending,
+			SortValue:  sortValue,
+			ID:         last.ID,
+		})
+	}
+
+	return page, nil
+}
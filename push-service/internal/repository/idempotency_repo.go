@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"push-service/internal/models"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+type IdempotencyRepository interface {
+	Reserve(ctx context.Context, record *models.IdempotencyRecord, staleBefore time.Time) (*models.IdempotencyRecord, error)
+	Complete(ctx context.Context, reservation *models.IdempotencyRecord, statusCode int, response []byte) error
+	Release(ctx context.Context, reservation *models.IdempotencyRecord) error
+	MessageProcessed(ctx context.Context, notificationID string) (bool, error)
+	RecordMessage(ctx context.Context, notificationID string, ttl time.Duration) error
+}
+
+type idempotencyRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewIdempotencyRepository(db *pgxpool.Pool) IdempotencyRepository {
+	return &idempotencyRepo{db: db}
+}
+
+// Reserve claims record's key for a new request. It returns nil when the key
+// was free, expired or held by a request that started before staleBefore
+// without finishing, and is now reserved, otherwise the existing record. The
+// reservation time is stored in record.CreatedAt and identifies the
+// reservation to Complete and Release.
+func (r *idempotencyRepo) Reserve(ctx context.Context, record *models.IdempotencyRecord, staleBefore time.Time) (*models.IdempotencyRecord, error) {
+	query := `
+		INSERT INTO idempotency_keys (scope, idempotency_key, route, request_hash, expires_at, user_ids)
+		VALUES ($1, $2, $3, $4, $5, $7)
+		ON CONFLICT (scope, idempotency_key) DO UPDATE SET
+			route = EXCLUDED.route,
+			request_hash = EXCLUDED.request_hash,
+			user_ids = EXCLUDED.user_ids,
+			status_code = NULL,
+			response = NULL,
+			created_at = NOW(),
+			expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at < NOW()
+			OR (idempotency_keys.status_code IS NULL AND idempotency_keys.created_at < $6)
+		RETURNING created_at
+	`
+
+	err := r.db.QueryRow(
+		ctx,
+		query,
+		record.Scope,
+		record.Key,
+		record.Route,
+		record.RequestHash,
+		record.ExpiresAt,
+		staleBefore,
+		tagsOrEmpty(record.UserIDs),
+	).Scan(&record.CreatedAt)
+	if err == nil {
+		return nil, nil
+	}
+	if err != pgx.ErrNoRows {
+		zap.L().Error("Failed to reserve idempotency key", zap.Error(err))
+		return nil, err
+	}
+
+	// The key is held by an unexpired record or a request still running
+	existing := models.IdempotencyRecord{Scope: record.Scope, Key: record.Key}
+	var response []byte
+	err = r.db.QueryRow(ctx, `
+		SELECT route, request_hash, status_code, response, created_at, expires_at
+		FROM idempotency_keys
+		WHERE scope = $1 AND idempotency_key = $2
+	`, record.Scope, record.Key).Scan(
+		&existing.Route,
+		&existing.RequestHash,
+		&existing.StatusCode,
+		&response,
+		&existing.CreatedAt,
+		&existing.ExpiresAt,
+	)
+	if err != nil {
+		zap.L().Error("Failed to get idempotency key", zap.Error(err))
+		return nil, err
+	}
+	existing.Response = response
+
+	return &existing, nil
+}
+
+// Complete stores the response of a reserved request. It does nothing if the
+// reservation was taken over by another request.
+func (r *idempotencyRepo) Complete(ctx context.Context, reservation *models.IdempotencyRecord, statusCode int, response []byte) error {
+	query := `
+		UPDATE idempotency_keys
+		SET status_code = $4, response = $5
+		WHERE scope = $1 AND idempotency_key = $2 AND created_at = $3 AND status_code IS NULL
+	`
+
+	var payload any
+	if len(response) > 0 {
+		payload = json.RawMessage(response)
+	}
+
+	if _, err := r.db.Exec(ctx, query, reservation.Scope, reservation.Key, reservation.CreatedAt, statusCode, payload); err != nil {
+		zap.L().Error("Failed to store idempotent response", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// Release frees a reserved key so the request can be retried. It does nothing
+// if the reservation was taken over by another request.
+func (r *idempotencyRepo) Release(ctx context.Context, reservation *models.IdempotencyRecord) error {
+	query := `
+		DELETE FROM idempotency_keys
+		WHERE scope = $1 AND idempotency_key = $2 AND created_at = $3 AND status_code IS NULL
+	`
+
+	if _, err := r.db.Exec(ctx, query, reservation.Scope, reservation.Key, reservation.CreatedAt); err != nil {
+		zap.L().Error("Failed to release idempotency key", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// MessageProcessed reports whether a gateway notification ID was recorded as
+// processed and has not expired
+func (r *idempotencyRepo) MessageProcessed(ctx context.Context, notificationID string) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM processed_gateway_messages
+			WHERE notification_id = $1 AND expires_at >= NOW()
+		)
+	`
+
+	var processed bool
+	if err := r.db.QueryRow(ctx, query, notificationID).Scan(&processed); err != nil {
+		zap.L().Error("Failed to check gateway message", zap.Error(err))
+		return false, err
+	}
+
+	return processed, nil
+}
+
+// RecordMessage records a gateway notification ID as processed for ttl
+func (r *idempotencyRepo) RecordMessage(ctx context.Context, notificationID string, ttl time.Duration) error {
+	query := `
+		INSERT INTO processed_gateway_messages (notification_id, expires_at)
+		VALUES ($1, $2)
+		ON CONFLICT (notification_id) DO UPDATE SET
+			processed_at = NOW(),
+			expires_at = EXCLUDED.expires_at
+	`
+
+	if _, err := r.db.Exec(ctx, query, notificationID, time.Now().Add(ttl)); err != nil {
+		zap.L().Error("Failed to record gateway message", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"push-service/internal/models"
+)
+
+func TestReserveTakesOverStaleInProgressKey(t *testing.T) {
+	pool := newTestPool(t)
+	repo := NewIdempotencyRepository(pool)
+	ctx := context.Background()
+
+	key := fmt.Sprintf("test-key-%d", time.Now().UnixNano())
+	t.Cleanup(func() {
+		pool.Exec(context.Background(), `DELETE FROM idempotency_keys WHERE idempotency_key = $1`, key)
+	})
+
+	newRecord := func() *models.IdempotencyRecord {
+		return &models.IdempotencyRecord{
+			Scope:       "key:test",
+			Key:         key,
+			Route:       "POST /v1/push/send",
+			RequestHash: "hash",
+			ExpiresAt:   time.Now().Add(time.Hour),
+		}
+	}
+
+	// The first request does not finish within the lock timeout
+	first := newRecord()
+	existing, err := repo.Reserve(ctx, first, time.Now().Add(-time.Minute))
+	if err != nil || existing != nil {
+		t.Fatalf("expected the key to be reserved, got %+v, %v", existing, err)
+	}
+
+	existing, err = repo.Reserve(ctx, newRecord(), time.Now().Add(-time.Minute))
+	if err != nil {
+		t.Fatalf("failed to reserve key: %v", err)
+	}
+	if existing == nil || existing.StatusCode != nil {
+		t.Fatalf("expected a retry within the lock timeout to find the key in progress, got %+v", existing)
+	}
+
+	// Make sure the takeover gets a later reservation time
+	time.Sleep(time.Millisecond)
+	retry := newRecord()
+	existing, err = repo.Reserve(ctx, retry, time.Now().Add(time.Minute))
+	if err != nil || existing != nil {
+		t.Fatalf("expected a retry after the lock timeout to take the key over, got %+v, %v", existing, err)
+	}
+
+	// The first request finishing late must not touch the retry's reservation
+	if err := repo.Release(ctx, first); err != nil {
+		t.Fatalf("failed to release key: %v", err)
+	}
+	if err := repo.Complete(ctx, first, 500, []byte(`{}`)); err != nil {
+		t.Fatalf("failed to complete key: %v", err)
+	}
+	existing, err = repo.Reserve(ctx, newRecord(), time.Now().Add(-time.Minute))
+	if err != nil {
+		t.Fatalf("failed to reserve key: %v", err)
+	}
+	if existing == nil || existing.StatusCode != nil {
+		t.Fatalf("expected the retry to still hold the key, got %+v", existing)
+	}
+
+	if err := repo.Complete(ctx, retry, 202, []byte(`{}`)); err != nil {
+		t.Fatalf("failed to complete key: %v", err)
+	}
+	existing, err = repo.Reserve(ctx, newRecord(), time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("failed to reserve key: %v", err)
+	}
+	if existing == nil || existing.StatusCode == nil || *existing.StatusCode != 202 {
+		t.Fatalf("expected a completed key to be replayed rather than taken over, got %+v", existing)
+	}
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"push-service/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+type NotificationRepository interface {
+	Create(ctx context.Context, notification *models.PushNotification, deviceCount int) error
+	CreateForGateway(ctx context.Context, gatewayID string, notification *models.PushNotification, deviceCount int) error
+	UpdateStatus(ctx context.Context, id string, update models.NotificationStatusUpdate) error
+	GetStatus(ctx context.Context, id string) (*models.NotificationStatus, error)
+	GetStatusByGatewayID(ctx context.Context, gatewayID string) (*models.NotificationStatus, error)
+}
+
+type notificationRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewNotificationRepository(db *pgxpool.Pool) NotificationRepository {
+	return &notificationRepo{db: db}
+}
+
+// Create records a notification, setting its ID and creation time
+func (r *notificationRepo) Create(ctx context.Context, notification *models.PushNotification, deviceCount int) error {
+	query := `
+		INSERT INTO push_notifications (user_id, title, body, data, category, status, device_count)
+		VALUES ($1, $2, $3, $4, COALESCE(NULLIF($5, ''), 'transactional'), $6, $7)
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRow(
+		ctx,
+		query,
+		notification.UserID,
+		notification.Title,
+		notification.Body,
+		notification.Data,
+		notification.Category,
+		notification.Status,
+		deviceCount,
+	).Scan(&notification.ID, &notification.CreatedAt)
+	if err != nil {
+		zap.L().Error("Failed to create notification", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// CreateForGateway records a notification received from the API gateway under
+// the gateway's ID, setting its ID and creation time. A redelivered gateway
+// message reuses the notification recorded for it, which is queued again.
+func (r *notificationRepo) CreateForGateway(ctx context.Context, gatewayID string, notification *models.PushNotification, deviceCount int) error {
+	query := `
+		INSERT INTO push_notifications (user_id, title, body, data, category, status, device_count, gateway_notification_id)
+		VALUES ($1, $2, $3, $4, COALESCE(NULLIF($5, ''), 'transactional'), $6, $7, $8)
+		ON CONFLICT (gateway_notification_id) WHERE gateway_notification_id IS NOT NULL DO UPDATE
+		SET status = EXCLUDED.status,
+			device_count = EXCLUDED.device_count,
+			updated_at = NOW()
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRow(
+		ctx,
+		query,
+		notification.UserID,
+		notification.Title,
+		notification.Body,
+		notification.Data,
+		notification.Category,
+		notification.Status,
+		deviceCount,
+		gatewayID,
+	).Scan(&notification.ID, &notification.CreatedAt)
+	if err != nil {
+		zap.L().Error("Failed to create gateway notification", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// UpdateStatus moves a notification to a new state, stamping sent_at when it
+// is sent. SuppressedCount is added to the devices already suppressed.
+func (r *notificationRepo) UpdateStatus(ctx context.Context, id string, update models.NotificationStatusUpdate) error {
+	query := `
+		UPDATE push_notifications
+		SET status = $2,
+			success_count = $3,
+			failure_count = $4,
+			suppressed_count = suppressed_count + $5,
+			error_message = $6,
+			sent_at = CASE WHEN $2 = 'sent' THEN NOW() ELSE sent_at END,
+			updated_at = NOW()
+		WHERE id = $1
+	`
+
+	tag, err := r.db.Exec(ctx, query, id, update.Status, update.SuccessCount, update.FailureCount, update.SuppressedCount, update.ErrorMessage)
+	if err != nil {
+		zap.L().Error("Failed to update notification status", zap.Error(err))
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// GetStatus returns a notification's delivery progress, or nil if it does not exist
+func (r *notificationRepo) GetStatus(ctx context.Context, id string) (*models.NotificationStatus, error) {
+	return r.getStatus(ctx, "id = $1", id)
+}
+
+// GetStatusByGatewayID returns the delivery progress of the notification
+// received from the API gateway under gatewayID, or nil if there is none
+func (r *notificationRepo) GetStatusByGatewayID(ctx context.Context, gatewayID string) (*models.NotificationStatus, error) {
+	return r.getStatus(ctx, "gateway_notification_id = $1", gatewayID)
+}
+
+func (r *notificationRepo) getStatus(ctx context.Context, where string, arg string) (*models.NotificationStatus, error) {
+	query := `
+		SELECT id, user_id, category, status, device_count, success_count, failure_count,
+			suppressed_count, error_message, sent_at, created_at, COALESCE(updated_at, created_at)
+		FROM push_notifications
+		WHERE ` + where
+
+	var status models.NotificationStatus
+	err := r.db.QueryRow(ctx, query, arg).Scan(
+		&status.ID,
+		&status.UserID,
+		&status.Category,
+		&status.Status,
+		&status.DeviceCount,
+		&status.SuccessCount,
+		&status.FailureCount,
+		&status.SuppressedCount,
+		&status.ErrorMessage,
+		&status.SentAt,
+		&status.CreatedAt,
+		&status.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		zap.L().Error("Failed to get notification status", zap.Error(err))
+		return nil, err
+	}
+
+	return &status, nil
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"push-service/internal/models"
+)
+
+func TestCreateForGatewayReusesNotificationOnRedelivery(t *testing.T) {
+	pool := newTestPool(t)
+	repo := NewNotificationRepository(pool)
+	ctx := context.Background()
+
+	// Gateway IDs need not be UUIDs
+	gatewayID := fmt.Sprintf("gw-%d", time.Now().UnixNano())
+	t.Cleanup(func() {
+		pool.Exec(context.Background(), `DELETE FROM push_notifications WHERE gateway_notification_id = $1`, gatewayID)
+	})
+
+	first := &models.PushNotification{UserID: "user-a", Title: "Hi", Body: "Hello", Status: models.NotificationStatusQueued}
+	if err := repo.CreateForGateway(ctx, gatewayID, first, 2); err != nil {
+		t.Fatalf("failed to create gateway notification: %v", err)
+	}
+	if err := repo.UpdateStatus(ctx, first.ID, models.NotificationStatusUpdate{Status: models.NotificationStatusFailed}); err != nil {
+		t.Fatalf("failed to update status: %v", err)
+	}
+
+	second := &models.PushNotification{UserID: "user-a", Title: "Hi", Body: "Hello", Status: models.NotificationStatusQueued}
+	if err := repo.CreateForGateway(ctx, gatewayID, second, 3); err != nil {
+		t.Fatalf("failed to create redelivered gateway notification: %v", err)
+	}
+	if second.ID != first.ID {
+		t.Errorf("expected the redelivery to reuse notification %s, got %s", first.ID, second.ID)
+	}
+
+	status, err := repo.GetStatusByGatewayID(ctx, gatewayID)
+	if err != nil {
+		t.Fatalf("failed to get status: %v", err)
+	}
+	if status == nil || status.ID != first.ID || status.Status != models.NotificationStatusQueued || status.DeviceCount != 3 {
+		t.Errorf("expected the notification to be queued again for 3 devices, got %+v", status)
+	}
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"push-service/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+type PreferenceRepository interface {
+	List(ctx context.Context, userID string) ([]models.NotificationPreference, error)
+	Set(ctx context.Context, userID string, preferences []models.NotificationPreference) error
+	Delete(ctx context.Context, userID, category, deviceToken string) error
+	OptedOut(ctx context.Context, userID, category string, tokens []string) (map[string]bool, error)
+}
+
+type preferenceRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewPreferenceRepository(db *pgxpool.Pool) PreferenceRepository {
+	return &preferenceRepo{db: db}
+}
+
+// List returns the user's preferences, user-wide ones first within each category
+func (r *preferenceRepo) List(ctx context.Context, userID string) ([]models.NotificationPreference, error) {
+	query := `
+		SELECT category, device_token, enabled, updated_at
+		FROM notification_preferences
+		WHERE user_id = $1
+		ORDER BY category, device_token
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, userID)
+	if err != nil {
+		zap.L().Error("Failed to list notification preferences", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	preferences := []models.NotificationPreference{}
+	for rows.Next() {
+		var preference models.NotificationPreference
+		err := rows.Scan(&preference.Category, &preference.DeviceToken, &preference.Enabled, &preference.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		preferences = append(preferences, preference)
+	}
+
+	return preferences, rows.Err()
+}
+
+// Set stores each preference, replacing any for the same category and device,
+// and sets their UpdatedAt
+func (r *preferenceRepo) Set(ctx context.Context, userID string, preferences []models.NotificationPreference) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin preference transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO notification_preferences (user_id, category, device_token, enabled, updated_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (user_id, category, device_token) DO UPDATE
+		SET enabled = EXCLUDED.enabled,
+			updated_at = NOW()
+		RETURNING updated_at
+	`
+
+	for i := range preferences {
+		preference := &preferences[i]
+		err := tx.QueryRow(ctx, query, userID, preference.Category, preference.DeviceToken, preference.Enabled).
+			Scan(&preference.UpdatedAt)
+		if err != nil {
+			zap.L().Error("Failed to set notification preference", zap.Error(err))
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Delete removes a preference, so the category falls back to the user-wide
+// preference or the default. It returns pgx.ErrNoRows if none was set.
+func (r *preferenceRepo) Delete(ctx context.Context, userID, category, deviceToken string) error {
+	query := `
+		DELETE FROM notification_preferences
+		WHERE user_id = $1 AND category = $2 AND device_token = $3
+	`
+
+	result, err := r.db.Exec(ctx, query, userID, category, deviceToken)
+	if err != nil {
+		zap.L().Error("Failed to delete notification preference", zap.Error(err))
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// OptedOut returns the tokens on which the user has turned category off,
+// either for the device or user-wide without a device preference overriding it
+func (r *preferenceRepo) OptedOut(ctx context.Context, userID, category string, tokens []string) (map[string]bool, error) {
+	query := `
+		SELECT t.token
+		FROM unnest($3::text[]) AS t(token)
+		LEFT JOIN notification_preferences d
+			ON d.user_id = $1 AND d.category = $2 AND d.device_token = t.token
+		LEFT JOIN notification_preferences u
+			ON u.user_id = $1 AND u.category = $2 AND u.device_token = ''
+		WHERE NOT COALESCE(d.enabled, u.enabled, TRUE)
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, category, tokens)
+	if err != nil {
+		zap.L().Error("Failed to check notification preferences", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	optedOut := make(map[string]bool)
+	for rows.Next() {
+		var token string
+		if err := rows.Scan(&token); err != nil {
+			return nil, err
+		}
+		optedOut[token] = true
+	}
+
+	return optedOut, rows.Err()
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"push-service/internal/models"
+)
+
+func TestOptedOutPrefersDevicePreferenceOverUserWide(t *testing.T) {
+	pool := newTestPool(t)
+	repo := NewPreferenceRepository(pool)
+	ctx := context.Background()
+
+	userID := fmt.Sprintf("test-user-%d", time.Now().UnixNano())
+	t.Cleanup(func() {
+		pool.Exec(context.Background(), `DELETE FROM notification_preferences WHERE user_id = $1`, userID)
+	})
+
+	err := repo.Set(ctx, userID, []models.NotificationPreference{
+		{Category: models.CategoryPromotions, Enabled: false},
+		{Category: models.CategoryPromotions, DeviceToken: "token-opted-in", Enabled: true},
+		{Category: models.CategorySocial, DeviceToken: "token-muted", Enabled: false},
+	})
+	if err != nil {
+		t.Fatalf("failed to set preferences: %v", err)
+	}
+
+	tokens := []string{"token-opted-in", "token-muted", "token-default"}
+
+	promotions, err := repo.OptedOut(ctx, userID, models.CategoryPromotions, tokens)
+	if err != nil {
+		t.Fatalf("failed to check promotions: %v", err)
+	}
+	if len(promotions) != 2 || !promotions["token-muted"] || !promotions["token-default"] {
+		t.Errorf("expected every device but token-opted-in to be opted out of promotions, got %v", promotions)
+	}
+
+	social, err := repo.OptedOut(ctx, userID, models.CategorySocial, tokens)
+	if err != nil {
+		t.Fatalf("failed to check social: %v", err)
+	}
+	if len(social) != 1 || !social["token-muted"] {
+		t.Errorf("expected only token-muted to be opted out of social, got %v", social)
+	}
+
+	if err := repo.Delete(ctx, userID, models.CategoryPromotions, ""); err != nil {
+		t.Fatalf("failed to delete user-wide preference: %v", err)
+	}
+	promotions, err = repo.OptedOut(ctx, userID, models.CategoryPromotions, tokens)
+	if err != nil {
+		t.Fatalf("failed to check promotions: %v", err)
+	}
+	if len(promotions) != 0 {
+		t.Errorf("expected promotions to be enabled by default, got %v", promotions)
+	}
+}
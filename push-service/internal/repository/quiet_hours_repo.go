@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"push-service/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+type QuietHoursRepository interface {
+	Get(ctx context.Context, scope, scopeID string) (*models.QuietHours, error)
+	Set(ctx context.Context, quietHours *models.QuietHours) error
+	Delete(ctx context.Context, scope, scopeID string) error
+	ForUser(ctx context.Context, userID string, apps []string) (*models.QuietHours, map[string]models.QuietHours, error)
+}
+
+type quietHoursRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewQuietHoursRepository(db *pgxpool.Pool) QuietHoursRepository {
+	return &quietHoursRepo{db: db}
+}
+
+// Get returns the quiet hours set for a user or app, or nil if none are set
+func (r *quietHoursRepo) Get(ctx context.Context, scope, scopeID string) (*models.QuietHours, error) {
+	query := `
+		SELECT scope, scope_id, start_time, end_time, updated_at
+		FROM quiet_hours
+		WHERE scope = $1 AND scope_id = $2
+	`
+
+	var quietHours models.QuietHours
+	err := conn(ctx, r.db).QueryRow(ctx, query, scope, scopeID).Scan(
+		&quietHours.Scope,
+		&quietHours.ScopeID,
+		&quietHours.Start,
+		&quietHours.End,
+		&quietHours.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		zap.L().Error("Failed to get quiet hours", zap.Error(err))
+		return nil, err
+	}
+
+	return &quietHours, nil
+}
+
+// Set replaces the quiet hours of a user or app
+func (r *quietHoursRepo) Set(ctx context.Context, quietHours *models.QuietHours) error {
+	query := `
+		INSERT INTO quiet_hours (scope, scope_id, start_time, end_time, updated_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (scope, scope_id) DO UPDATE
+		SET start_time = EXCLUDED.start_time,
+			end_time = EXCLUDED.end_time,
+			updated_at = NOW()
+		RETURNING updated_at
+	`
+
+	err := r.db.QueryRow(ctx, query, quietHours.Scope, quietHours.ScopeID, quietHours.Start, quietHours.End).
+		Scan(&quietHours.UpdatedAt)
+	if err != nil {
+		zap.L().Error("Failed to set quiet hours", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// Delete removes the quiet hours of a user or app, or returns pgx.ErrNoRows
+func (r *quietHoursRepo) Delete(ctx context.Context, scope, scopeID string) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM quiet_hours WHERE scope = $1 AND scope_id = $2`, scope, scopeID)
+	if err != nil {
+		zap.L().Error("Failed to delete quiet hours", zap.Error(err))
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// ForUser returns the user's quiet hours, or nil, and those of each of apps
+// that has them
+func (r *quietHoursRepo) ForUser(ctx context.Context, userID string, apps []string) (*models.QuietHours, map[string]models.QuietHours, error) {
+	query := `
+		SELECT scope, scope_id, start_time, end_time, updated_at
+		FROM quiet_hours
+		WHERE (scope = 'user' AND scope_id = $1) OR (scope = 'app' AND scope_id = ANY($2))
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, apps)
+	if err != nil {
+		zap.L().Error("Failed to get quiet hours for user", zap.Error(err))
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var user *models.QuietHours
+	byApp := make(map[string]models.QuietHours)
+	for rows.Next() {
+		var quietHours models.QuietHours
+		err := rows.Scan(&quietHours.Scope, &quietHours.ScopeID, &quietHours.Start, &quietHours.End, &quietHours.UpdatedAt)
+		if err != nil {
+			return nil, nil, err
+		}
+		if quietHours.Scope == models.QuietHoursScopeUser {
+			user = &quietHours
+		} else {
+			byApp[quietHours.ScopeID] = quietHours
+		}
+	}
+
+	return user, byApp, rows.Err()
+}
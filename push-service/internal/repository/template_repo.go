@@ -0,0 +1,273 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"push-service/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// ErrTemplateExists is returned when creating a template whose key is taken
+var ErrTemplateExists = errors.New("template already exists")
+
+type TemplateRepository interface {
+	Create(ctx context.Context, template *models.PushTemplate) error
+	Get(ctx context.Context, key string, version int) (*models.PushTemplate, error)
+	List(ctx context.Context) ([]models.PushTemplate, error)
+	Versions(ctx context.Context, key string) ([]models.TemplateVersion, error)
+	AddVersion(ctx context.Context, template *models.PushTemplate) error
+	Delete(ctx context.Context, key string) error
+}
+
+// templateColumns is the column list scanned by scanTemplates, one row per locale
+const templateColumns = `
+	t.key, t.description, t.latest_version, t.created_at, t.updated_at,
+	v.version, v.default_locale,
+	c.locale, c.title, c.body, c.image, c.link, c.platform_overrides
+`
+
+// templateJoin joins templates to the contents of the version selected by the query
+const templateJoin = `
+	FROM push_templates t
+	JOIN push_template_versions v ON v.template_key = t.key
+	JOIN push_template_contents c ON c.template_key = v.template_key AND c.version = v.version
+`
+
+type templateRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewTemplateRepository(db *pgxpool.Pool) TemplateRepository {
+	return &templateRepo{db: db}
+}
+
+// scanTemplates groups locale rows ordered by key into templates
+func scanTemplates(rows pgx.Rows) ([]models.PushTemplate, error) {
+	templates := []models.PushTemplate{}
+	for rows.Next() {
+		var template models.PushTemplate
+		var locale string
+		var content models.LocalizedContent
+		err := rows.Scan(
+			&template.Key,
+			&template.Description,
+			&template.LatestVersion,
+			&template.CreatedAt,
+			&template.UpdatedAt,
+			&template.Version,
+			&template.DefaultLocale,
+			&locale,
+			&content.Title,
+			&content.Body,
+			&content.Image,
+			&content.Link,
+			&content.PlatformOverrides,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if len(content.PlatformOverrides) == 0 {
+			content.PlatformOverrides = nil
+		}
+
+		if n := len(templates); n == 0 || templates[n-1].Key != template.Key {
+			template.Locales = map[string]models.LocalizedContent{}
+			templates = append(templates, template)
+		}
+		templates[len(templates)-1].Locales[locale] = content
+	}
+	return templates, rows.Err()
+}
+
+// insertVersion stores a version of template and its content for each locale
+func insertVersion(ctx context.Context, tx pgx.Tx, template *models.PushTemplate) error {
+	_, err := tx.Exec(ctx, `
+		INSERT INTO push_template_versions (template_key, version, default_locale)
+		VALUES ($1, $2, $3)
+	`, template.Key, template.Version, template.DefaultLocale)
+	if err != nil {
+		return err
+	}
+
+	for locale, content := range template.Locales {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO push_template_contents (template_key, version, locale, title, body, image, link, platform_overrides)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		`,
+			template.Key,
+			template.Version,
+			locale,
+			content.Title,
+			content.Body,
+			content.Image,
+			content.Link,
+			overridesOrEmpty(content.PlatformOverrides),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func overridesOrEmpty(overrides map[string]models.ContentOverride) map[string]models.ContentOverride {
+	if overrides == nil {
+		return map[string]models.ContentOverride{}
+	}
+	return overrides
+}
+
+// Create stores template as version 1, or returns ErrTemplateExists
+func (r *templateRepo) Create(ctx context.Context, template *models.PushTemplate) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin template transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO push_templates (key, description, latest_version)
+		VALUES ($1, $2, 1)
+		ON CONFLICT (key) DO NOTHING
+		RETURNING created_at, updated_at
+	`, template.Key, template.Description).Scan(&template.CreatedAt, &template.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return ErrTemplateExists
+	}
+	if err != nil {
+		zap.L().Error("Failed to create push template", zap.Error(err))
+		return err
+	}
+
+	template.Version, template.LatestVersion = 1, 1
+	if err := insertVersion(ctx, tx, template); err != nil {
+		zap.L().Error("Failed to store push template content", zap.Error(err))
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Get returns the given version of the template, or the latest when version
+// is zero. It returns nil if the template or version does not exist.
+func (r *templateRepo) Get(ctx context.Context, key string, version int) (*models.PushTemplate, error) {
+	query := `SELECT ` + templateColumns + templateJoin + `
+		WHERE t.key = $1 AND v.version = COALESCE(NULLIF($2, 0), t.latest_version)
+	`
+
+	rows, err := r.db.Query(ctx, query, key, version)
+	if err != nil {
+		zap.L().Error("Failed to get push template", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	templates, err := scanTemplates(rows)
+	if err != nil {
+		zap.L().Error("Failed to get push template", zap.Error(err))
+		return nil, err
+	}
+	if len(templates) == 0 {
+		return nil, nil
+	}
+	return &templates[0], nil
+}
+
+// List returns the latest version of every template, ordered by key
+func (r *templateRepo) List(ctx context.Context) ([]models.PushTemplate, error) {
+	query := `SELECT ` + templateColumns + templateJoin + `
+		WHERE v.version = t.latest_version
+		ORDER BY t.key
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		zap.L().Error("Failed to list push templates", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanTemplates(rows)
+}
+
+// Versions lists the template's versions, newest first. It returns an empty
+// list if the template does not exist.
+func (r *templateRepo) Versions(ctx context.Context, key string) ([]models.TemplateVersion, error) {
+	query := `
+		SELECT v.version, v.default_locale, array_agg(c.locale ORDER BY c.locale), v.created_at
+		FROM push_template_versions v
+		JOIN push_template_contents c ON c.template_key = v.template_key AND c.version = v.version
+		WHERE v.template_key = $1
+		GROUP BY v.version, v.default_locale, v.created_at
+		ORDER BY v.version DESC
+	`
+
+	rows, err := r.db.Query(ctx, query, key)
+	if err != nil {
+		zap.L().Error("Failed to list push template versions", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := []models.TemplateVersion{}
+	for rows.Next() {
+		var version models.TemplateVersion
+		if err := rows.Scan(&version.Version, &version.DefaultLocale, &version.Locales, &version.CreatedAt); err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+
+	return versions, rows.Err()
+}
+
+// AddVersion stores template as the next version of its key and makes it the
+// latest, setting Version and LatestVersion. It returns pgx.ErrNoRows if the
+// template does not exist.
+func (r *templateRepo) AddVersion(ctx context.Context, template *models.PushTemplate) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin template transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, `
+		UPDATE push_templates
+		SET latest_version = latest_version + 1, description = $2, updated_at = NOW()
+		WHERE key = $1
+		RETURNING latest_version, created_at, updated_at
+	`, template.Key, template.Description).Scan(&template.LatestVersion, &template.CreatedAt, &template.UpdatedAt)
+	if err != nil {
+		if err != pgx.ErrNoRows {
+			zap.L().Error("Failed to update push template", zap.Error(err))
+		}
+		return err
+	}
+
+	template.Version = template.LatestVersion
+	if err := insertVersion(ctx, tx, template); err != nil {
+		zap.L().Error("Failed to store push template content", zap.Error(err))
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Delete removes the template and all its versions, or returns pgx.ErrNoRows
+func (r *templateRepo) Delete(ctx context.Context, key string) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM push_templates WHERE key = $1`, key)
+	if err != nil {
+		zap.L().Error("Failed to delete push template", zap.Error(err))
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"push-service/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+type UserAttributesRepository interface {
+	Get(ctx context.Context, userID string) (*models.UserAttributes, error)
+	Set(ctx context.Context, attributes *models.UserAttributes) error
+}
+
+type userAttributesRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewUserAttributesRepository(db *pgxpool.Pool) UserAttributesRepository {
+	return &userAttributesRepo{db: db}
+}
+
+// Get returns the user's attributes, or nil if none have been set
+func (r *userAttributesRepo) Get(ctx context.Context, userID string) (*models.UserAttributes, error) {
+	query := `
+		SELECT user_id, tags, attributes, updated_at
+		FROM user_attributes
+		WHERE user_id = $1
+	`
+
+	var attributes models.UserAttributes
+	err := conn(ctx, r.db).QueryRow(ctx, query, userID).Scan(
+		&attributes.UserID,
+		&attributes.Tags,
+		&attributes.Attributes,
+		&attributes.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		zap.L().Error("Failed to get user attributes", zap.Error(err))
+		return nil, err
+	}
+
+	return &attributes, nil
+}
+
+// Set replaces the user's tags and attributes
+func (r *userAttributesRepo) Set(ctx context.Context, attributes *models.UserAttributes) error {
+	query := `
+		INSERT INTO user_attributes (user_id, tags, attributes, updated_at)
+		VALUES ($1, ARRAY(SELECT DISTINCT unnest($2::text[])), $3, NOW())
+		ON CONFLICT (user_id) DO UPDATE
+		SET tags = EXCLUDED.tags,
+			attributes = EXCLUDED.attributes,
+			updated_at = NOW()
+		RETURNING tags, updated_at
+	`
+
+	err := r.db.QueryRow(
+		ctx,
+		query,
+		attributes.UserID,
+		tagsOrEmpty(attributes.Tags),
+		metadataOrEmpty(attributes.Attributes),
+	).Scan(&attributes.Tags, &attributes.UpdatedAt)
+
+	if err != nil {
+		zap.L().Error("Failed to set user attributes", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
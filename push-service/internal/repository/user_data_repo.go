@@ -0,0 +1,222 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"push-service/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// UserDataRepository reads and erases all data held about a single user
+type UserDataRepository interface {
+	Export(ctx context.Context, userID string) (*models.UserDataExport, error)
+	Erase(ctx context.Context, userID string) (*models.UserErasureResult, error)
+}
+
+type userDataRepo struct {
+	db                 *pgxpool.Pool
+	userAttributesRepo UserAttributesRepository
+	preferenceRepo     PreferenceRepository
+	quietHoursRepo     QuietHoursRepository
+	deferredPushRepo   DeferredPushRepository
+}
+
+func NewUserDataRepository(db *pgxpool.Pool, userAttributesRepo UserAttributesRepository, preferenceRepo PreferenceRepository, quietHoursRepo QuietHoursRepository, deferredPushRepo DeferredPushRepository) UserDataRepository {
+	return &userDataRepo{
+		db:                 db,
+		userAttributesRepo: userAttributesRepo,
+		preferenceRepo:     preferenceRepo,
+		quietHoursRepo:     quietHoursRepo,
+		deferredPushRepo:   deferredPushRepo,
+	}
+}
+
+// Export reads all of the user's data in one read-only transaction, so the
+// export is a consistent snapshot even while the user's data changes
+func (r *userDataRepo) Export(ctx context.Context, userID string) (*models.UserDataExport, error) {
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin export transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+	ctx = withTx(ctx, tx)
+
+	export := &models.UserDataExport{
+		UserID:        userID,
+		Devices:       []models.Device{},
+		Notifications: []models.PushNotification{},
+	}
+
+	// Include inactive devices, unlike DeviceRepository.GetByUserID
+	deviceRows, err := tx.Query(ctx, `
+		SELECT `+deviceColumns+`
+		FROM devices
+		WHERE user_id = $1
+		ORDER BY created_at
+	`, userID)
+	if err != nil {
+		zap.L().Error("Failed to export user devices", zap.Error(err))
+		return nil, err
+	}
+	defer deviceRows.Close()
+
+	for deviceRows.Next() {
+		device, err := scanDevice(deviceRows)
+		if err != nil {
+			return nil, err
+		}
+		export.Devices = append(export.Devices, *device)
+	}
+	if err := deviceRows.Err(); err != nil {
+		return nil, err
+	}
+
+	notificationRows, err := tx.Query(ctx, `
+		SELECT id, device_id, user_id, COALESCE(title, ''), COALESCE(body, ''), data,
+			category, COALESCE(status, ''), error_message, sent_at, created_at
+		FROM push_notifications
+		WHERE user_id = $1
+		ORDER BY created_at
+	`, userID)
+	if err != nil {
+		zap.L().Error("Failed to export user notifications", zap.Error(err))
+		return nil, err
+	}
+	defer notificationRows.Close()
+
+	for notificationRows.Next() {
+		var notification models.PushNotification
+		err := notificationRows.Scan(
+			&notification.ID,
+			&notification.DeviceID,
+			&notification.UserID,
+			&notification.Title,
+			&notification.Body,
+			&notification.Data,
+			&notification.Category,
+			&notification.Status,
+			&notification.ErrorMessage,
+			&notification.SentAt,
+			&notification.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		export.Notifications = append(export.Notifications, notification)
+	}
+
+	if err := notificationRows.Err(); err != nil {
+		return nil, err
+	}
+
+	export.Attributes, err = r.userAttributesRepo.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	export.Preferences, err = r.preferenceRepo.List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	export.QuietHours, err = r.quietHoursRepo.Get(ctx, models.QuietHoursScopeUser, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	export.DeferredPushes, err = r.deferredPushRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit export transaction: %w", err)
+	}
+
+	return export, nil
+}
+
+// Erase hard-deletes every row belonging to the user in a single transaction
+func (r *userDataRepo) Erase(ctx context.Context, userID string) (*models.UserErasureResult, error) {
+	result := &models.UserErasureResult{UserID: userID}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin erasure transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Gateway messages are found through the user's notifications, so they go first
+	tag, err := tx.Exec(ctx, `
+		DELETE FROM processed_gateway_messages
+		WHERE notification_id IN (
+			SELECT gateway_notification_id FROM push_notifications
+			WHERE user_id = $1 AND gateway_notification_id IS NOT NULL
+		)
+	`, userID)
+	if err != nil {
+		zap.L().Error("Failed to erase user gateway messages", zap.Error(err))
+		return nil, err
+	}
+	result.GatewayMessagesDeleted = tag.RowsAffected()
+
+	tag, err = tx.Exec(ctx, `DELETE FROM push_notifications WHERE user_id = $1`, userID)
+	if err != nil {
+		zap.L().Error("Failed to erase user notifications", zap.Error(err))
+		return nil, err
+	}
+	result.NotificationsDeleted = tag.RowsAffected()
+
+	tag, err = tx.Exec(ctx, `DELETE FROM devices WHERE user_id = $1`, userID)
+	if err != nil {
+		zap.L().Error("Failed to erase user devices", zap.Error(err))
+		return nil, err
+	}
+	result.DevicesDeleted = tag.RowsAffected()
+
+	tag, err = tx.Exec(ctx, `DELETE FROM user_attributes WHERE user_id = $1`, userID)
+	if err != nil {
+		zap.L().Error("Failed to erase user attributes", zap.Error(err))
+		return nil, err
+	}
+	result.AttributesDeleted = tag.RowsAffected() > 0
+
+	tag, err = tx.Exec(ctx, `DELETE FROM notification_preferences WHERE user_id = $1`, userID)
+	if err != nil {
+		zap.L().Error("Failed to erase user notification preferences", zap.Error(err))
+		return nil, err
+	}
+	result.PreferencesDeleted = tag.RowsAffected()
+
+	tag, err = tx.Exec(ctx, `DELETE FROM quiet_hours WHERE scope = 'user' AND scope_id = $1`, userID)
+	if err != nil {
+		zap.L().Error("Failed to erase user quiet hours", zap.Error(err))
+		return nil, err
+	}
+	result.QuietHoursDeleted = tag.RowsAffected() > 0
+
+	tag, err = tx.Exec(ctx, `DELETE FROM deferred_pushes WHERE user_id = $1`, userID)
+	if err != nil {
+		zap.L().Error("Failed to erase user deferred pushes", zap.Error(err))
+		return nil, err
+	}
+	result.DeferredPushesDeleted = tag.RowsAffected()
+
+	// Stored responses to sends addressed to the user hold their devices and
+	// results. Keys of bulk sends to other users too are removed as well.
+	tag, err = tx.Exec(ctx, `DELETE FROM idempotency_keys WHERE $1 = ANY(user_ids)`, userID)
+	if err != nil {
+		zap.L().Error("Failed to erase user idempotency keys", zap.Error(err))
+		return nil, err
+	}
+	result.IdempotencyKeysDeleted = tag.RowsAffected()
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit erasure: %w", err)
+	}
+
+	return result, nil
+}
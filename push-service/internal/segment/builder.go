@@ -0,0 +1,85 @@
+package segment
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// versionPattern matches dotted numeric versions such as "3" or "3.2.10"
+var versionPattern = regexp.MustCompile(`^\d+(\.\d+)*$`)
+
+// maxVersionDigits bounds each version component so it fits the int4 array
+// versions are compared as. Stored values with longer components are not
+// treated as versions.
+const maxVersionDigits = 9
+
+var sqlOperators = map[string]string{
+	"=":   "=",
+	"eq":  "=",
+	"!=":  "IS DISTINCT FROM",
+	"ne":  "IS DISTINCT FROM",
+	"<":   "<",
+	"lt":  "<",
+	"<=":  "<=",
+	"lte": "<=",
+	">":   ">",
+	"gt":  ">",
+	">=":  ">=",
+	"gte": ">=",
+}
+
+// Builder collects positional query arguments while SQL is generated
+type Builder struct {
+	Args []any
+}
+
+// Arg appends value to the arguments and returns its placeholder
+func (b *Builder) Arg(value any) string {
+	b.Args = append(b.Args, value)
+	return fmt.Sprintf("$%d", len(b.Args))
+}
+
+// Compare builds a condition comparing the text expression valueExpr with value.
+// op is a symbol (=, !=, <, <=, >, >=) or its name (eq, ne, lt, lte, gt, gte).
+// Ordering comparisons against a version-shaped value compare numerically and
+// never match text that is not itself a version.
+func Compare(b *Builder, valueExpr, op, value string) (string, error) {
+	operator, ok := sqlOperators[op]
+	if !ok {
+		return "", fmt.Errorf("unsupported operator: %s", op)
+	}
+
+	ordering := operator != "=" && operator != "IS DISTINCT FROM"
+	if !ordering || !versionPattern.MatchString(value) {
+		return fmt.Sprintf("%s %s %s", valueExpr, operator, b.Arg(value)), nil
+	}
+
+	version, err := parseVersion(value)
+	if err != nil {
+		return "", err
+	}
+
+	// CASE guarantees the int[] cast only runs on version-shaped values whose
+	// components fit an int
+	return fmt.Sprintf(`CASE WHEN (%[1]s) ~ '^\d{1,%[4]d}(\.\d{1,%[4]d})*$'
+		THEN string_to_array(%[1]s, '.')::int[] %[2]s %[3]s::int[]
+		ELSE false END`, valueExpr, operator, b.Arg(version), maxVersionDigits), nil
+}
+
+func parseVersion(value string) ([]int32, error) {
+	parts := strings.Split(value, ".")
+	version := make([]int32, len(parts))
+	for i, part := range parts {
+		if len(part) > maxVersionDigits {
+			return nil, fmt.Errorf("invalid version %q: components are limited to %d digits", value, maxVersionDigits)
+		}
+		n, err := strconv.ParseInt(part, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version %q: %w", value, err)
+		}
+		version[i] = int32(n)
+	}
+	return version, nil
+}
@@ -0,0 +1,89 @@
+package segment
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenOp
+	tokenColon
+	tokenLParen
+	tokenRParen
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+// isWordRune reports whether r can appear in a bare word. Words cover field
+// names (app_version, user.plan), bare values (ios, beta) and versions (3.2.1).
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.' || r == '-'
+}
+
+func tokenize(src string) ([]token, error) {
+	var tokens []token
+	runes := []rune(src)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, value: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, value: ")", pos: i})
+			i++
+		case r == ':':
+			tokens = append(tokens, token{kind: tokenColon, value: ":", pos: i})
+			i++
+		case r == '=' || r == '!' || r == '<' || r == '>':
+			op := string(r)
+			if i+1 < len(runes) && runes[i+1] == '=' {
+				op += "="
+			}
+			if op == "!" {
+				return nil, fmt.Errorf("unexpected '!' at position %d", i)
+			}
+			tokens = append(tokens, token{kind: tokenOp, value: op, pos: i})
+			i += len(op)
+		case r == '\'' || r == '"':
+			start := i
+			var sb strings.Builder
+			i++
+			for i < len(runes) && runes[i] != r {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				sb.WriteRune(runes[i])
+				i++
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("unterminated string at position %d", start)
+			}
+			i++ // closing quote
+			tokens = append(tokens, token{kind: tokenString, value: sb.String(), pos: start})
+		case isWordRune(r):
+			start := i
+			for i < len(runes) && isWordRune(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenWord, value: string(runes[start:i]), pos: start})
+		default:
+			return nil, fmt.Errorf("unexpected character %q at position %d", r, i)
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(runes)}), nil
+}
@@ -0,0 +1,230 @@
+// Package segment parses audience segment definitions and compiles them to
+// SQL conditions over the devices table.
+//
+// A segment combines conditions with AND, OR, NOT and parentheses:
+//
+//	platform = ios AND tag:beta AND app_version >= 3.0
+//
+// Fields resolve as follows:
+//   - platform, app and user_id compare device columns
+//   - tag:<name> matches a device tag or a tag on the device's user
+//   - user.<key> compares a user attribute
+//   - any other name compares a device metadata key
+//
+// Ordering operators compare dotted numeric values (3.10 > 3.9) as versions
+// and anything else as text.
+package segment
+
+import (
+	"fmt"
+	"strings"
+)
+
+// deviceColumns are the fields compared directly against devices columns
+var deviceColumns = map[string]bool{
+	"platform": true,
+	"app":      true,
+	"user_id":  true,
+}
+
+// Segment is a parsed segment definition
+type Segment struct {
+	source string
+	root   node
+}
+
+// String returns the source the segment was parsed from
+func (s *Segment) String() string {
+	return s.source
+}
+
+// SQL compiles the segment to a boolean condition over the devices table,
+// appending its arguments to b
+func (s *Segment) SQL(b *Builder) string {
+	return s.root.sql(b)
+}
+
+type node interface {
+	sql(b *Builder) string
+}
+
+type andNode struct{ left, right node }
+type orNode struct{ left, right node }
+type notNode struct{ inner node }
+type tagNode struct{ tag string }
+type compareNode struct {
+	field string
+	op    string
+	value string
+}
+
+func (n andNode) sql(b *Builder) string {
+	return "(" + n.left.sql(b) + " AND " + n.right.sql(b) + ")"
+}
+
+func (n orNode) sql(b *Builder) string {
+	return "(" + n.left.sql(b) + " OR " + n.right.sql(b) + ")"
+}
+
+func (n notNode) sql(b *Builder) string {
+	// COALESCE so NOT over a missing attribute matches rather than yielding NULL
+	return "NOT COALESCE(" + n.inner.sql(b) + ", false)"
+}
+
+func (n tagNode) sql(b *Builder) string {
+	tag := b.Arg(n.tag)
+	return fmt.Sprintf(`(%[1]s = ANY(devices.tags) OR EXISTS (
+		SELECT 1 FROM user_attributes ua WHERE ua.user_id = devices.user_id AND %[1]s = ANY(ua.tags)))`, tag)
+}
+
+func (n compareNode) sql(b *Builder) string {
+	var valueExpr string
+	switch {
+	case deviceColumns[n.field]:
+		valueExpr = "devices." + n.field
+	case strings.HasPrefix(n.field, "user."):
+		valueExpr = fmt.Sprintf(`(SELECT ua.attributes ->> %s FROM user_attributes ua WHERE ua.user_id = devices.user_id)`,
+			b.Arg(strings.TrimPrefix(n.field, "user.")))
+	default:
+		valueExpr = "devices.metadata ->> " + b.Arg(n.field)
+	}
+
+	// Operators and versions are validated by the parser
+	condition, _ := Compare(b, valueExpr, n.op, n.value)
+	return condition
+}
+
+// Parse parses a segment definition
+func Parse(src string) (*Segment, error) {
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.value, tok.pos)
+	}
+
+	return &Segment{source: src, root: root}, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// keyword reports whether the next token is the given case-insensitive keyword
+func (p *parser) keyword(word string) bool {
+	tok := p.peek()
+	return tok.kind == tokenWord && strings.EqualFold(tok.value, word)
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("OR") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("AND") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.keyword("NOT") {
+		p.next()
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{inner: inner}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	tok := p.next()
+
+	switch tok.kind {
+	case tokenLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, fmt.Errorf("expected ')' at position %d", closing.pos)
+		}
+		return inner, nil
+
+	case tokenWord:
+		if strings.EqualFold(tok.value, "tag") && p.peek().kind == tokenColon {
+			p.next()
+			tag := p.next()
+			if tag.kind != tokenWord && tag.kind != tokenString {
+				return nil, fmt.Errorf("expected tag name at position %d", tag.pos)
+			}
+			return tagNode{tag: tag.value}, nil
+		}
+
+		if tok.value == "user." {
+			return nil, fmt.Errorf("missing user attribute name at position %d", tok.pos)
+		}
+
+		op := p.next()
+		if op.kind != tokenOp {
+			return nil, fmt.Errorf("expected operator after %q at position %d", tok.value, op.pos)
+		}
+		value := p.next()
+		if value.kind != tokenWord && value.kind != tokenString {
+			return nil, fmt.Errorf("expected value after %q at position %d", op.value, value.pos)
+		}
+		if _, err := Compare(&Builder{}, "", op.value, value.value); err != nil {
+			return nil, err
+		}
+		return compareNode{field: tok.value, op: op.value, value: value.value}, nil
+
+	case tokenEOF:
+		return nil, fmt.Errorf("unexpected end of segment")
+
+	default:
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.value, tok.pos)
+	}
+}
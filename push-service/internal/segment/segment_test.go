@@ -0,0 +1,148 @@
+package segment
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseCompilesToSQL(t *testing.T) {
+	tests := []struct {
+		name     string
+		segment  string
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:     "device column",
+			segment:  "platform = ios",
+			wantSQL:  "devices.platform = $1",
+			wantArgs: []any{"ios"},
+		},
+		{
+			name:     "AND binds tighter than OR",
+			segment:  "platform = ios OR app = shop AND user_id = u1",
+			wantSQL:  "(devices.platform = $1 OR (devices.app = $2 AND devices.user_id = $3))",
+			wantArgs: []any{"ios", "shop", "u1"},
+		},
+		{
+			name:     "parentheses override precedence",
+			segment:  "(platform = ios OR app = shop) AND user_id = u1",
+			wantSQL:  "((devices.platform = $1 OR devices.app = $2) AND devices.user_id = $3)",
+			wantArgs: []any{"ios", "shop", "u1"},
+		},
+		{
+			name:     "NOT binds tighter than AND",
+			segment:  "NOT platform = ios AND app = shop",
+			wantSQL:  "(NOT COALESCE(devices.platform = $1, false) AND devices.app = $2)",
+			wantArgs: []any{"ios", "shop"},
+		},
+		{
+			name:     "operators are left-associative",
+			segment:  "platform = ios or platform = android or platform = web",
+			wantSQL:  "((devices.platform = $1 OR devices.platform = $2) OR devices.platform = $3)",
+			wantArgs: []any{"ios", "android", "web"},
+		},
+		{
+			name:     "metadata key",
+			segment:  "country != US",
+			wantSQL:  "devices.metadata ->> $1 IS DISTINCT FROM $2",
+			wantArgs: []any{"country", "US"},
+		},
+		{
+			name:     "user attribute",
+			segment:  `user.plan = "pro"`,
+			wantSQL:  "(SELECT ua.attributes ->> $1 FROM user_attributes ua WHERE ua.user_id = devices.user_id) = $2",
+			wantArgs: []any{"plan", "pro"},
+		},
+		{
+			name:     "text ordering",
+			segment:  "user.name < m",
+			wantSQL:  "(SELECT ua.attributes ->> $1 FROM user_attributes ua WHERE ua.user_id = devices.user_id) < $2",
+			wantArgs: []any{"name", "m"},
+		},
+		{
+			name:    "version ordering",
+			segment: "app_version >= 3.10",
+			wantSQL: `CASE WHEN (devices.metadata ->> $1) ~ '^\d{1,9}(\.\d{1,9})*$'
+		THEN string_to_array(devices.metadata ->> $1, '.')::int[] >= $2::int[]
+		ELSE false END`,
+			wantArgs: []any{"app_version", []int32{3, 10}},
+		},
+		{
+			name:     "quote in a value",
+			segment:  `platform = "ios' OR '1'='1"`,
+			wantSQL:  "devices.platform = $1",
+			wantArgs: []any{"ios' OR '1'='1"},
+		},
+		{
+			name:     "escaped quote in a value",
+			segment:  `platform = 'ios\' OR true --'`,
+			wantSQL:  "devices.platform = $1",
+			wantArgs: []any{"ios' OR true --"},
+		},
+		{
+			name:    "statement in a tag",
+			segment: `tag:"beta'); DROP TABLE devices; --"`,
+			wantSQL: `($1 = ANY(devices.tags) OR EXISTS (
+		SELECT 1 FROM user_attributes ua WHERE ua.user_id = devices.user_id AND $1 = ANY(ua.tags)))`,
+			wantArgs: []any{"beta'); DROP TABLE devices; --"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seg, err := Parse(tt.segment)
+			if err != nil {
+				t.Fatalf("failed to parse %q: %v", tt.segment, err)
+			}
+			if seg.String() != tt.segment {
+				t.Errorf("expected source %q, got %q", tt.segment, seg.String())
+			}
+
+			b := &Builder{}
+			if sql := seg.SQL(b); sql != tt.wantSQL {
+				t.Errorf("expected SQL\n%s\ngot\n%s", tt.wantSQL, sql)
+			}
+			if !reflect.DeepEqual(b.Args, tt.wantArgs) {
+				t.Errorf("expected args %#v, got %#v", tt.wantArgs, b.Args)
+			}
+		})
+	}
+}
+
+func TestParseRejectsInvalidSegments(t *testing.T) {
+	tests := []struct {
+		name    string
+		segment string
+		wantErr string
+	}{
+		{name: "empty", segment: "", wantErr: "unexpected end of segment"},
+		{name: "missing value", segment: "platform =", wantErr: "expected value"},
+		{name: "missing operator", segment: "platform ios", wantErr: "expected operator"},
+		{name: "unclosed parenthesis", segment: "(platform = ios", wantErr: "expected ')'"},
+		{name: "stray closing parenthesis", segment: "platform = ios)", wantErr: "unexpected \")\""},
+		{name: "dangling AND", segment: "platform = ios AND", wantErr: "unexpected end of segment"},
+		{name: "lone bang", segment: "platform ! ios", wantErr: "unexpected '!'"},
+		{name: "unterminated string", segment: "platform = 'ios", wantErr: "unterminated string"},
+		{name: "missing tag name", segment: "tag:", wantErr: "expected tag name"},
+		{name: "missing attribute name", segment: "user. = pro", wantErr: "missing user attribute name"},
+		{name: "quoted field name", segment: `"platform' OR 1=1 --" = ios`, wantErr: "unexpected"},
+		{name: "statement separator", segment: "platform = ios; DROP TABLE devices", wantErr: "unexpected character ';'"},
+		{name: "comment", segment: "platform = ios /* x */", wantErr: "unexpected character '/'"},
+		{name: "version component overflow", segment: "app_version >= 99999999999.1", wantErr: "invalid version"},
+		{name: "version component too long", segment: "app_version >= 1.0000000000", wantErr: "invalid version"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.segment)
+			if err == nil {
+				t.Fatalf("expected %q to be rejected", tt.segment)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %q", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"push-service/internal/config"
+	"push-service/internal/models"
+	"push-service/internal/repository"
+	"push-service/pkg/logger"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+const apiKeyPrefix = "psk_"
+
+// bootstrapKeyID identifies the configured bootstrap key, which is not stored
+const bootstrapKeyID = "bootstrap"
+
+type APIKeyService interface {
+	Authenticate(ctx context.Context, rawKey string) (*models.APIKey, error)
+	CreateKey(ctx context.Context, req models.CreateAPIKeyRequest) (*models.CreatedAPIKey, error)
+	ListKeys(ctx context.Context) ([]models.APIKey, error)
+	RotateKey(ctx context.Context, id string, req models.RotateAPIKeyRequest) (*models.CreatedAPIKey, error)
+	RevokeKey(ctx context.Context, id string) error
+}
+
+type apiKeyService struct {
+	apiKeyRepo repository.APIKeyRepository
+	cfg        *config.AuthConfig
+}
+
+func NewAPIKeyService(apiKeyRepo repository.APIKeyRepository, cfg *config.AuthConfig) APIKeyService {
+	return &apiKeyService{
+		apiKeyRepo: apiKeyRepo,
+		cfg:        cfg,
+	}
+}
+
+// hashAPIKey returns the hex SHA-256 of a key. Keys carry 256 bits of
+// entropy, so a fast unsalted hash is sufficient and allows indexed lookup.
+func hashAPIKey(rawKey string) string {
+	sum := sha256.Sum256([]byte(rawKey))
+	return hex.EncodeToString(sum[:])
+}
+
+// generateAPIKey returns a new plaintext key and its display prefix
+func generateAPIKey() (key string, prefix string, err error) {
+	prefixBytes := make([]byte, 4)
+	secretBytes := make([]byte, 32)
+	if _, err := rand.Read(prefixBytes); err != nil {
+		return "", "", err
+	}
+	if _, err := rand.Read(secretBytes); err != nil {
+		return "", "", err
+	}
+
+	prefix = apiKeyPrefix + hex.EncodeToString(prefixBytes)
+	return prefix + "_" + hex.EncodeToString(secretBytes), prefix, nil
+}
+
+// Authenticate resolves a plaintext key to a usable API key
+func (s *apiKeyService) Authenticate(ctx context.Context, rawKey string) (*models.APIKey, error) {
+	if rawKey == "" {
+		return nil, ErrInvalidAPIKey
+	}
+
+	if s.cfg.BootstrapKey != "" && subtle.ConstantTimeCompare([]byte(rawKey), []byte(s.cfg.BootstrapKey)) == 1 {
+		return &models.APIKey{
+			ID:     bootstrapKeyID,
+			Name:   bootstrapKeyID,
+			Prefix: bootstrapKeyID,
+			Scopes: []string{models.ScopeAdmin},
+		}, nil
+	}
+
+	key, err := s.apiKeyRepo.GetByHash(ctx, hashAPIKey(rawKey))
+	if err != nil {
+		return nil, err
+	}
+	if key == nil || key.RevokedAt != nil {
+		return nil, ErrInvalidAPIKey
+	}
+	if key.ExpiresAt != nil && time.Now().After(*key.ExpiresAt) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	// Last-used tracking is best effort and must not fail the request
+	s.apiKeyRepo.TouchLastUsed(ctx, key.ID)
+
+	return key, nil
+}
+
+func validateScopes(scopes []string) error {
+	for _, scope := range scopes {
+		if !models.IsValidScope(scope) {
+			return ErrInvalidScope.WithDetails(map[string]any{
+				"scope":        scope,
+				"valid_scopes": models.Scopes,
+			})
+		}
+	}
+	return nil
+}
+
+func (s *apiKeyService) CreateKey(ctx context.Context, req models.CreateAPIKeyRequest) (*models.CreatedAPIKey, error) {
+	if err := validateScopes(req.Scopes); err != nil {
+		return nil, err
+	}
+
+	rawKey, prefix, err := generateAPIKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate API key: %w", err)
+	}
+
+	key := models.APIKey{
+		Name:      req.Name,
+		Prefix:    prefix,
+		Scopes:    req.Scopes,
+		ExpiresAt: req.ExpiresAt,
+		RateLimit: req.RateLimit,
+	}
+	if err := s.apiKeyRepo.Create(ctx, &key, hashAPIKey(rawKey)); err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx).Info("API key created",
+		zap.String("key_id", key.ID),
+		zap.String("name", key.Name),
+		zap.Strings("scopes", key.Scopes),
+	)
+
+	return &models.CreatedAPIKey{APIKey: key, Key: rawKey}, nil
+}
+
+func (s *apiKeyService) ListKeys(ctx context.Context) ([]models.APIKey, error) {
+	return s.apiKeyRepo.List(ctx)
+}
+
+// RotateKey issues a replacement key with the same name, scopes and rate limit. The old
+// key stays valid for the requested grace period and expires afterwards.
+func (s *apiKeyService) RotateKey(ctx context.Context, id string, req models.RotateAPIKeyRequest) (*models.CreatedAPIKey, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, ErrAPIKeyNotFound
+	}
+
+	oldKey, err := s.apiKeyRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if oldKey == nil || oldKey.RevokedAt != nil {
+		return nil, ErrAPIKeyNotFound
+	}
+
+	rawKey, prefix, err := generateAPIKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate API key: %w", err)
+	}
+
+	newKey := models.APIKey{
+		Name:      oldKey.Name,
+		Prefix:    prefix,
+		Scopes:    oldKey.Scopes,
+		ExpiresAt: oldKey.ExpiresAt,
+		RateLimit: oldKey.RateLimit,
+	}
+	oldValidUntil := time.Now().Add(time.Duration(req.GracePeriodSeconds) * time.Second)
+
+	if err := s.apiKeyRepo.Rotate(ctx, id, oldValidUntil, &newKey, hashAPIKey(rawKey)); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAPIKeyNotFound
+		}
+		return nil, err
+	}
+
+	logger.FromContext(ctx).Info("API key rotated",
+		zap.String("old_key_id", id),
+		zap.String("key_id", newKey.ID),
+		zap.Time("old_key_valid_until", oldValidUntil),
+	)
+
+	return &models.CreatedAPIKey{APIKey: newKey, Key: rawKey}, nil
+}
+
+func (s *apiKeyService) RevokeKey(ctx context.Context, id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return ErrAPIKeyNotFound
+	}
+
+	if err := s.apiKeyRepo.Revoke(ctx, id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrAPIKeyNotFound
+		}
+		return err
+	}
+
+	logger.FromContext(ctx).Info("API key revoked", zap.String("key_id", id))
+	return nil
+}
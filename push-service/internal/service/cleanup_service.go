@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"push-service/internal/config"
+	"push-service/internal/models"
+	"push-service/internal/repository"
+	"push-service/pkg/logger"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type CleanupService interface {
+	Run(ctx context.Context, dryRun bool) (*models.CleanupResult, error)
+	Start(ctx context.Context)
+	Policy() models.CleanupPolicy
+	LastResult() *models.CleanupResult
+}
+
+type cleanupService struct {
+	cleanupRepo repository.CleanupRepository
+	cfg         *config.CleanupConfig
+
+	mu         sync.RWMutex
+	lastResult *models.CleanupResult
+}
+
+func NewCleanupService(cleanupRepo repository.CleanupRepository, cfg *config.CleanupConfig) CleanupService {
+	return &cleanupService{
+		cleanupRepo: cleanupRepo,
+		cfg:         cfg,
+	}
+}
+
+// Policy returns the configured cleanup policy
+func (s *cleanupService) Policy() models.CleanupPolicy {
+	return models.CleanupPolicy{
+		DeactivateAfterDays:     s.cfg.DeactivateAfterDays,
+		DeleteInactiveAfterDays: s.cfg.DeleteInactiveAfterDays,
+		HistoryRetentionDays:    s.cfg.HistoryRetentionDays,
+	}
+}
+
+// Run executes the cleanup policy once. Non dry-run results are kept and
+// returned by LastResult. ErrCleanupLocked yields a skipped result, not an error.
+func (s *cleanupService) Run(ctx context.Context, dryRun bool) (*models.CleanupResult, error) {
+	startedAt := time.Now().UTC()
+
+	result, err := s.cleanupRepo.Cleanup(ctx, s.Policy(), dryRun)
+	if errors.Is(err, repository.ErrCleanupLocked) {
+		result, err = &models.CleanupResult{DryRun: dryRun, Skipped: true}, nil
+	}
+	if err != nil {
+		result = &models.CleanupResult{DryRun: dryRun, Error: err.Error()}
+	}
+
+	result.StartedAt = startedAt
+	result.FinishedAt = time.Now().UTC()
+
+	if !dryRun {
+		s.mu.Lock()
+		s.lastResult = result
+		s.mu.Unlock()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx).Info("Device cleanup completed",
+		zap.Bool("dry_run", result.DryRun),
+		zap.Bool("skipped", result.Skipped),
+		zap.Int64("devices_deactivated", result.DevicesDeactivated),
+		zap.Int64("devices_deleted", result.DevicesDeleted),
+		zap.Int64("notifications_purged", result.NotificationsPurged),
+		zap.Int64("idempotency_keys_purged", result.IdempotencyKeysPurged),
+		zap.Int64("gateway_messages_purged", result.GatewayMessagesPurged),
+	)
+
+	return result, nil
+}
+
+// Start runs the cleanup on the configured interval until ctx is cancelled
+func (s *cleanupService) Start(ctx context.Context) {
+	if !s.cfg.Enabled {
+		logger.FromContext(ctx).Info("Device cleanup job disabled")
+		return
+	}
+
+	interval := s.cfg.Interval
+	if interval <= 0 {
+		interval = time.Hour // default
+	}
+
+	logger.FromContext(ctx).Info("Starting device cleanup job", zap.Duration("interval", interval))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.FromContext(ctx).Info("Device cleanup job shutting down...")
+			return
+		case <-ticker.C:
+			if _, err := s.Run(ctx, false); err != nil {
+				logger.FromContext(ctx).Error("Device cleanup failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+// LastResult returns the result of the most recent non dry-run cleanup
+func (s *cleanupService) LastResult() *models.CleanupResult {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.lastResult
+}
@@ -2,21 +2,36 @@ package service
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"push-service/internal/config"
+	"push-service/internal/metrics"
 	"push-service/internal/models"
 	"push-service/internal/platform/fcm"
 	"push-service/internal/repository"
+	"push-service/pkg/logger"
 
+	"github.com/jackc/pgx/v5"
 	"go.uber.org/zap"
 )
 
 type DeviceService interface {
 	RegisterDevice(ctx context.Context, req models.CreateDeviceRequest) (*models.DeviceResponse, error)
-	UnregisterDevice(ctx context.Context, token string) error
+	RegisterDevices(ctx context.Context, reqs []models.CreateDeviceRequest) (*models.BatchDeviceResponse, error)
+	UnregisterDevice(ctx context.Context, app, token string) error
+	UnregisterDevices(ctx context.Context, tokens []string) (*models.BatchDeviceResponse, error)
+	UnregisterUserDevice(ctx context.Context, userID, app, token string) error
+	UnregisterUserDevices(ctx context.Context, userID string, filter models.UserDeviceFilter) (*models.UnregisterUserDevicesResult, error)
 	GetUserDevices(ctx context.Context, userID string) ([]models.DeviceResponse, error)
+	SearchDevices(ctx context.Context, query models.DeviceQuery) (*models.DevicePage, error)
+	SetDeviceTags(ctx context.Context, app, token string, tags []string) error
 }
 
+const (
+	defaultDeviceSearchLimit = 50
+	maxDeviceSearchLimit     = 500
+	defaultBatchMaxSize      = 1000
+)
+
 type deviceService struct {
 	deviceRepo repository.DeviceRepository
 	fcmClient  fcm.FCMClient
@@ -34,90 +49,286 @@ func NewDeviceService(deviceRepo repository.DeviceRepository, fcmClient fcm.FCMC
 func (s *deviceService) RegisterDevice(ctx context.Context, req models.CreateDeviceRequest) (*models.DeviceResponse, error) {
 	// Validate token if validation is enabled
 	if s.cfg != nil && s.cfg.Queue.Validation.Enabled && s.fcmClient != nil {
-		if err := s.fcmClient.ValidateToken(ctx, req.Token); err != nil {
-			zap.L().Warn("Token validation failed during device registration",
+		err := s.fcmClient.ValidateToken(ctx, req.Token)
+		metrics.TokenValidation(metrics.StageRegistration, err)
+		if err != nil {
+			logger.FromContext(ctx).Warn("Token validation failed during device registration",
 				zap.String("user_id", req.UserID),
 				zap.String("platform", req.Platform),
-				zap.String("token", maskToken(req.Token)),
+				logger.Token("token", req.Token),
 				zap.Error(err),
 			)
-			return nil, fmt.Errorf("token validation failed: %w", err)
+			return nil, ErrInvalidDeviceToken.Wrap(err)
 		}
-		zap.L().Debug("Token validated successfully",
+		logger.FromContext(ctx).Debug("Token validated successfully",
 			zap.String("user_id", req.UserID),
 			zap.String("platform", req.Platform),
 		)
 	}
 
-	// Check if device already exists
-	existingDevice, err := s.deviceRepo.GetByToken(ctx, req.Token)
-	if err != nil {
-		return nil, err
-	}
-
-	if existingDevice != nil {
-		// Update existing device
-		if err := s.deviceRepo.UpdateStatus(ctx, req.Token, true); err != nil {
-			return nil, err
-		}
-		return &models.DeviceResponse{
-			ID:       existingDevice.ID,
-			UserID:   existingDevice.UserID,
-			Token:    existingDevice.Token,
-			Platform: existingDevice.Platform,
-			IsActive: true,
-		}, nil
-	}
-
-	// Create new device
+	// Upsert so re-registering a token reactivates it and moves it to the new user
 	device := &models.Device{
 		UserID:   req.UserID,
+		App:      req.App,
 		Token:    req.Token,
 		Platform: req.Platform,
-		IsActive: true,
+		Locale:   req.Locale,
+		Timezone: req.Timezone,
+		Metadata: req.Metadata,
+		Tags:     req.Tags,
 	}
 
-	if err := s.deviceRepo.Create(ctx, device); err != nil {
+	if err := s.deviceRepo.Upsert(ctx, device); err != nil {
 		return nil, err
 	}
+	metrics.DeviceRegistrations.WithLabelValues(device.Platform).Inc()
 
-	zap.L().Info("Device registered successfully",
+	logger.FromContext(ctx).Info("Device registered successfully",
 		zap.String("user_id", req.UserID),
+		zap.String("app", device.App),
 		zap.String("platform", req.Platform),
 	)
 
+	return toDeviceResponse(*device), nil
+}
+
+// batchMaxSize returns the configured limit on batch entries
+func (s *deviceService) batchMaxSize() int {
+	if s.cfg != nil && s.cfg.Devices.BatchMaxSize > 0 {
+		return s.cfg.Devices.BatchMaxSize
+	}
+	return defaultBatchMaxSize
+}
+
+// checkBatchSize rejects empty batches and batches over the configured limit
+func (s *deviceService) checkBatchSize(n int) error {
+	if n == 0 {
+		return ErrInvalidRequest.WithDetails("batch must contain at least one entry")
+	}
+	if limit := s.batchMaxSize(); n > limit {
+		return ErrBatchTooLarge.WithDetails(map[string]int{"max_batch_size": limit, "batch_size": n})
+	}
+	return nil
+}
+
+// validateBatchDevice checks the fields that binding enforces for single registrations
+func validateBatchDevice(req models.CreateDeviceRequest) error {
+	switch {
+	case req.UserID == "":
+		return errors.New("user_id is required")
+	case req.Token == "":
+		return errors.New("token is required")
+	}
+	switch req.Platform {
+	case "ios", "android", "web":
+		return nil
+	default:
+		return errors.New("platform must be one of ios, android, web")
+	}
+}
+
+// RegisterDevices registers a batch of devices with one upsert. Tokens are not
+// validated with FCM here; invalid tokens are caught before sending. Entries
+// that fail validation or repeat an earlier app and token are reported and
+// skipped, and the rest are still registered.
+func (s *deviceService) RegisterDevices(ctx context.Context, reqs []models.CreateDeviceRequest) (*models.BatchDeviceResponse, error) {
+	if err := s.checkBatchSize(len(reqs)); err != nil {
+		return nil, err
+	}
+
+	results := make([]models.BatchDeviceResult, len(reqs))
+	devices := make([]models.Device, 0, len(reqs))
+	positions := make([]int, 0, len(reqs))
+	seen := make(map[string]bool, len(reqs))
+
+	for i, req := range reqs {
+		results[i] = models.BatchDeviceResult{Index: i, Token: req.Token}
+
+		if err := validateBatchDevice(req); err != nil {
+			results[i].ErrorCode = ErrInvalidRequest.Code
+			results[i].Error = err.Error()
+			continue
+		}
+
+		app := req.App
+		if app == "" {
+			app = models.DefaultApp
+		}
+		key := app + "\x00" + req.Token
+		if seen[key] {
+			results[i].ErrorCode = ErrDuplicateDevice.Code
+			results[i].Error = ErrDuplicateDevice.Message
+			continue
+		}
+		seen[key] = true
+
+		devices = append(devices, models.Device{
+			UserID:   req.UserID,
+			App:      app,
+			Token:    req.Token,
+			Platform: req.Platform,
+			Locale:   req.Locale,
+			Timezone: req.Timezone,
+			Metadata: req.Metadata,
+			Tags:     req.Tags,
+		})
+		positions = append(positions, i)
+	}
+
+	if err := s.deviceRepo.UpsertBatch(ctx, devices); err != nil {
+		return nil, err
+	}
+
+	for j, device := range devices {
+		result := &results[positions[j]]
+		result.Success = true
+		result.Device = toDeviceResponse(device)
+		metrics.DeviceRegistrations.WithLabelValues(device.Platform).Inc()
+	}
+
+	resp := newBatchDeviceResponse(results)
+	logger.FromContext(ctx).Info("Device batch registered",
+		zap.Int("count", len(reqs)),
+		zap.Int("success_count", resp.SuccessCount),
+		zap.Int("failure_count", resp.FailureCount),
+	)
+
+	return resp, nil
+}
+
+// UnregisterDevices soft deletes a batch of device tokens in one statement.
+// Tokens with no registered device are reported as device_not_found.
+func (s *deviceService) UnregisterDevices(ctx context.Context, tokens []string) (*models.BatchDeviceResponse, error) {
+	if err := s.checkBatchSize(len(tokens)); err != nil {
+		return nil, err
+	}
+
+	lookup := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		if token != "" {
+			lookup = append(lookup, token)
+		}
+	}
+
+	matched, err := s.deviceRepo.UpdateStatusBatch(ctx, lookup, false)
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[string]bool, len(matched))
+	for _, token := range matched {
+		found[token] = true
+	}
+
+	results := make([]models.BatchDeviceResult, len(tokens))
+	for i, token := range tokens {
+		results[i] = models.BatchDeviceResult{Index: i, Token: token}
+		switch {
+		case token == "":
+			results[i].ErrorCode = ErrInvalidRequest.Code
+			results[i].Error = "token is required"
+		case found[token]:
+			results[i].Success = true
+		default:
+			results[i].ErrorCode = ErrDeviceNotFound.Code
+			results[i].Error = ErrDeviceNotFound.Message
+		}
+	}
+
+	resp := newBatchDeviceResponse(results)
+	logger.FromContext(ctx).Info("Device batch unregistered",
+		zap.Int("count", len(tokens)),
+		zap.Int("success_count", resp.SuccessCount),
+		zap.Int("failure_count", resp.FailureCount),
+	)
+
+	return resp, nil
+}
+
+func newBatchDeviceResponse(results []models.BatchDeviceResult) *models.BatchDeviceResponse {
+	resp := &models.BatchDeviceResponse{Results: results}
+	for _, result := range results {
+		if result.Success {
+			resp.SuccessCount++
+		} else {
+			resp.FailureCount++
+		}
+	}
+	return resp
+}
+
+// toDeviceResponse converts a device model to its API representation
+func toDeviceResponse(device models.Device) *models.DeviceResponse {
 	return &models.DeviceResponse{
 		ID:       device.ID,
 		UserID:   device.UserID,
+		App:      device.App,
 		Token:    device.Token,
 		Platform: device.Platform,
+		Locale:   device.Locale,
+		Timezone: device.Timezone,
 		IsActive: device.IsActive,
-	}, nil
-}
-
-// maskToken masks a token for logging
-func maskToken(token string) string {
-	if len(token) <= 20 {
-		return "***"
+		Metadata: device.Metadata,
+		Tags:     device.Tags,
 	}
-	return token[:10] + "..." + token[len(token)-10:]
 }
 
-func (s *deviceService) UnregisterDevice(ctx context.Context, token string) error {
+// UnregisterDevice soft deletes the device registered with token in app, or in
+// the default app if app is empty
+func (s *deviceService) UnregisterDevice(ctx context.Context, app, token string) error {
 	// Soft delete by setting is_active to false
-	err := s.deviceRepo.UpdateStatus(ctx, token, false)
+	err := s.deviceRepo.UpdateStatus(ctx, app, token, false)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrDeviceNotFound
+	}
 	if err != nil {
-		zap.L().Error("Failed to unregister device", 
-			zap.String("token", token), 
+		logger.FromContext(ctx).Error("Failed to unregister device",
+			logger.Token("token", token),
 			zap.Error(err),
 		)
 		return err
 	}
 
-	zap.L().Info("Device unregistered successfully", zap.String("token", token))
+	logger.FromContext(ctx).Info("Device unregistered successfully", logger.Token("token", token))
+	return nil
+}
+
+// UnregisterUserDevice soft deletes a device only if it belongs to userID. It
+// returns ErrDeviceNotFound for tokens registered to other users.
+func (s *deviceService) UnregisterUserDevice(ctx context.Context, userID, app, token string) error {
+	err := s.deviceRepo.UpdateStatusForUser(ctx, userID, app, token, false)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrDeviceNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx).Info("Device unregistered by user",
+		zap.String("user_id", userID),
+		logger.Token("token", token),
+	)
 	return nil
 }
 
+// UnregisterUserDevices soft deletes all of a user's devices, optionally keeping
+// one token and limiting the platforms. Notifications already queued for the
+// removed devices are dropped by the worker, which only sends to active devices.
+func (s *deviceService) UnregisterUserDevices(ctx context.Context, userID string, filter models.UserDeviceFilter) (*models.UnregisterUserDevicesResult, error) {
+	count, err := s.deviceRepo.DeactivateUserDevices(ctx, userID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx).Info("User devices unregistered",
+		zap.String("user_id", userID),
+		zap.Bool("kept_current", filter.ExceptToken != ""),
+		zap.Strings("platforms", filter.Platforms),
+		zap.Int64("count", count),
+	)
+
+	return &models.UnregisterUserDevicesResult{UserID: userID, UnregisteredCount: count}, nil
+}
+
 func (s *deviceService) GetUserDevices(ctx context.Context, userID string) ([]models.DeviceResponse, error) {
 	devices, err := s.deviceRepo.GetByUserID(ctx, userID)
 	if err != nil {
@@ -126,14 +337,38 @@ func (s *deviceService) GetUserDevices(ctx context.Context, userID string) ([]mo
 
 	responses := make([]models.DeviceResponse, len(devices))
 	for i, device := range devices {
-		responses[i] = models.DeviceResponse{
-			ID:       device.ID,
-			UserID:   device.UserID,
-			Token:    device.Token,
-			Platform: device.Platform,
-			IsActive: device.IsActive,
-		}
+		responses[i] = *toDeviceResponse(device)
 	}
 
 	return responses, nil
-}
\ No newline at end of file
+}
+
+// SearchDevices runs an admin device search, clamping the page size
+func (s *deviceService) SearchDevices(ctx context.Context, query models.DeviceQuery) (*models.DevicePage, error) {
+	if query.Limit <= 0 {
+		query.Limit = defaultDeviceSearchLimit
+	}
+	if query.Limit > maxDeviceSearchLimit {
+		query.Limit = maxDeviceSearchLimit
+	}
+
+	page, err := s.deviceRepo.Search(ctx, query)
+	if errors.Is(err, repository.ErrInvalidCursor) {
+		return nil, ErrInvalidCursor
+	}
+	return page, err
+}
+
+// SetDeviceTags replaces the tags on a device in app, or in the default app if
+// app is empty
+func (s *deviceService) SetDeviceTags(ctx context.Context, app, token string, tags []string) error {
+	if err := s.deviceRepo.SetTags(ctx, app, token, tags); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrDeviceNotFound
+		}
+		return err
+	}
+
+	logger.FromContext(ctx).Info("Device tags updated", zap.Int("tag_count", len(tags)))
+	return nil
+}
@@ -0,0 +1,101 @@
+package service
+
+import "fmt"
+
+// Kind classifies a domain error. Transports map kinds to their own status
+// codes, so services never deal in HTTP statuses.
+type Kind int
+
+const (
+	KindInternal Kind = iota
+	KindInvalid
+	KindUnauthorized
+	KindForbidden
+	KindNotFound
+	KindConflict
+	KindUnprocessable
+	KindRateLimited
+	KindUnavailable
+)
+
+// Error is a domain error with a stable, machine-readable code. Sentinels are
+// matched with errors.Is, which compares codes, so copies made by WithDetails
+// and Wrap still match the sentinel they came from.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	Details any
+	Err     error
+}
+
+// NewError creates a domain error
+func NewError(kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// WithDetails returns a copy of e carrying client-facing details
+func (e *Error) WithDetails(details any) *Error {
+	copied := *e
+	copied.Details = details
+	return &copied
+}
+
+// Wrap returns a copy of e caused by err. The cause is logged but not shown
+// to clients.
+func (e *Error) Wrap(err error) *Error {
+	copied := *e
+	copied.Err = err
+	return &copied
+}
+
+var (
+	// ErrInvalidRequest is returned for malformed request bodies and parameters
+	ErrInvalidRequest = NewError(KindInvalid, "invalid_request", "Invalid request")
+	// ErrInternal is reported for errors that are not domain errors
+	ErrInternal = NewError(KindInternal, "internal_error", "Internal server error")
+
+	ErrDeviceNotFound      = NewError(KindNotFound, "device_not_found", "Device not found")
+	ErrNoDevices           = NewError(KindNotFound, "no_devices", "No devices found for user")
+	ErrNoMatchingPlatforms = NewError(KindUnprocessable, "no_matching_platforms", "No devices match the requested platforms")
+	ErrInvalidDeviceToken  = NewError(KindUnprocessable, "invalid_device_token", "Device token failed validation")
+	ErrInvalidCursor       = NewError(KindInvalid, "invalid_cursor", "Invalid cursor")
+	ErrInvalidSegment      = NewError(KindInvalid, "invalid_segment", "Invalid segment")
+	ErrQueueUnavailable    = NewError(KindUnavailable, "queue_unavailable", "Notification queue unavailable")
+	ErrBatchTooLarge       = NewError(KindInvalid, "batch_too_large", "Too many entries in batch")
+	ErrDuplicateDevice     = NewError(KindInvalid, "duplicate_device", "Device appears more than once in batch")
+
+	ErrNotificationNotFound = NewError(KindNotFound, "notification_not_found", "Notification not found")
+	ErrPreferenceNotFound   = NewError(KindNotFound, "preference_not_found", "Notification preference not found")
+	ErrQuietHoursNotFound   = NewError(KindNotFound, "quiet_hours_not_found", "Quiet hours not set")
+
+	ErrInvalidAPIKey  = NewError(KindUnauthorized, "invalid_api_key", "Invalid API key")
+	ErrInvalidScope   = NewError(KindInvalid, "invalid_scope", "Invalid scope")
+	ErrAPIKeyNotFound = NewError(KindNotFound, "api_key_not_found", "API key not found")
+
+	ErrTemplateNotFound = NewError(KindNotFound, "template_not_found", "Template not found")
+	ErrTemplateExists   = NewError(KindConflict, "template_exists", "A template with this key already exists")
+	ErrInvalidTemplate  = NewError(KindInvalid, "invalid_template", "Invalid template")
+	ErrTemplateRender   = NewError(KindUnprocessable, "template_render_failed", "Template could not be rendered")
+
+	ErrCredentialReloadFailed = NewError(KindUnavailable, "credential_reload_failed", "One or more credentials failed to reload")
+
+	ErrIdempotencyKeyMismatch   = NewError(KindUnprocessable, "idempotency_key_mismatch", "Idempotency-Key was already used for a different request")
+	ErrIdempotencyKeyInProgress = NewError(KindConflict, "idempotency_key_in_progress", "A request with this Idempotency-Key is in progress")
+)
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"push-service/internal/config"
+	"push-service/internal/models"
+	"push-service/internal/repository"
+	"time"
+)
+
+const (
+	defaultIdempotencyTTL         = 24 * time.Hour
+	defaultIdempotencyLockTimeout = 2 * time.Minute
+)
+
+type IdempotencyService interface {
+	Begin(ctx context.Context, scope, key, route, requestHash string, userIDs []string) (*models.IdempotencyRecord, error)
+	Complete(ctx context.Context, reservation *models.IdempotencyRecord, statusCode int, response []byte) error
+	Release(ctx context.Context, reservation *models.IdempotencyRecord) error
+}
+
+type idempotencyService struct {
+	idempotencyRepo repository.IdempotencyRepository
+	cfg             *config.IdempotencyConfig
+}
+
+func NewIdempotencyService(idempotencyRepo repository.IdempotencyRepository, cfg *config.IdempotencyConfig) IdempotencyService {
+	return &idempotencyService{
+		idempotencyRepo: idempotencyRepo,
+		cfg:             cfg,
+	}
+}
+
+// idempotencyTTL returns how long keys are remembered
+func idempotencyTTL(cfg *config.IdempotencyConfig) time.Duration {
+	if cfg != nil && cfg.TTL > 0 {
+		return cfg.TTL
+	}
+	return defaultIdempotencyTTL
+}
+
+// idempotencyLockTimeout returns how long an unfinished request holds its key
+func idempotencyLockTimeout(cfg *config.IdempotencyConfig) time.Duration {
+	if cfg != nil && cfg.LockTimeout > 0 {
+		return cfg.LockTimeout
+	}
+	return defaultIdempotencyLockTimeout
+}
+
+// Begin reserves key for a new request. It returns the reservation, without a
+// status code, if the caller should run the request, or the completed record
+// whose response should be replayed. A key held by a request that never
+// finished is taken over once its lock times out; the reservation is passed to
+// Complete or Release so a request that was taken over cannot change its key.
+// userIDs are the users the request is addressed to, whose erasure removes the
+// key.
+func (s *idempotencyService) Begin(ctx context.Context, scope, key, route, requestHash string, userIDs []string) (*models.IdempotencyRecord, error) {
+	record := &models.IdempotencyRecord{
+		Scope:       scope,
+		Key:         key,
+		Route:       route,
+		RequestHash: requestHash,
+		UserIDs:     userIDs,
+		ExpiresAt:   time.Now().Add(idempotencyTTL(s.cfg)),
+	}
+
+	existing, err := s.idempotencyRepo.Reserve(ctx, record, time.Now().Add(-idempotencyLockTimeout(s.cfg)))
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return record, nil
+	}
+
+	if existing.Route != route || existing.RequestHash != requestHash {
+		return nil, ErrIdempotencyKeyMismatch
+	}
+	if existing.StatusCode == nil {
+		return nil, ErrIdempotencyKeyInProgress
+	}
+
+	return existing, nil
+}
+
+func (s *idempotencyService) Complete(ctx context.Context, reservation *models.IdempotencyRecord, statusCode int, response []byte) error {
+	return s.idempotencyRepo.Complete(ctx, reservation, statusCode, response)
+}
+
+func (s *idempotencyService) Release(ctx context.Context, reservation *models.IdempotencyRecord) error {
+	return s.idempotencyRepo.Release(ctx, reservation)
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"push-service/internal/models"
+	"push-service/internal/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// notificationPollInterval is how often WatchStatus re-reads a notification.
+// Workers may run in another process, so changes are picked up by polling.
+const notificationPollInterval = time.Second
+
+type NotificationService interface {
+	GetStatus(ctx context.Context, id string) (*models.NotificationStatus, error)
+	WatchStatus(ctx context.Context, id string, send func(*models.NotificationStatus) error) error
+}
+
+type notificationService struct {
+	notificationRepo repository.NotificationRepository
+}
+
+func NewNotificationService(notificationRepo repository.NotificationRepository) NotificationService {
+	return &notificationService{notificationRepo: notificationRepo}
+}
+
+// GetStatus looks a notification up by its ID, or else by the ID the API
+// gateway gave it
+func (s *notificationService) GetStatus(ctx context.Context, id string) (*models.NotificationStatus, error) {
+	var status *models.NotificationStatus
+	var err error
+	if _, parseErr := uuid.Parse(id); parseErr == nil {
+		status, err = s.notificationRepo.GetStatus(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if status == nil {
+		status, err = s.notificationRepo.GetStatusByGatewayID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if status == nil {
+		return nil, ErrNotificationNotFound
+	}
+
+	return status, nil
+}
+
+// WatchStatus calls send with the current status of a notification and again
+// each time it changes. It returns once the status is final, send fails or ctx
+// is done.
+func (s *notificationService) WatchStatus(ctx context.Context, id string, send func(*models.NotificationStatus) error) error {
+	current, err := s.GetStatus(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := send(current); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(notificationPollInterval)
+	defer ticker.Stop()
+
+	for !models.IsFinalNotificationStatus(current.Status) {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		next, err := s.GetStatus(ctx, id)
+		if err != nil {
+			return err
+		}
+		if next.Status == current.Status && next.UpdatedAt.Equal(current.UpdatedAt) {
+			continue
+		}
+		if err := send(next); err != nil {
+			return err
+		}
+		current = next
+	}
+
+	return nil
+}
@@ -3,76 +3,392 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"push-service/internal/config"
+	"push-service/internal/metrics"
 	"push-service/internal/models"
 	"push-service/internal/platform/fcm"
 	"push-service/internal/queue"
 	"push-service/internal/repository"
+	"push-service/internal/segment"
+	"push-service/pkg/logger"
+	"push-service/pkg/rabbitmq"
+	"push-service/pkg/tracing"
+	"sync"
 	"time"
 
 	amqp "github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 type PushService interface {
-	SendPush(ctx context.Context, req models.SendPushRequest) error
+	SendPush(ctx context.Context, req models.SendPushRequest) (string, error)
+	SendPushSync(ctx context.Context, req models.SendPushRequest) (*models.SyncPushResult, error)
 	SendBulkPush(ctx context.Context, req models.BulkPushRequest) error
+	SendSegmentPush(ctx context.Context, req models.SendSegmentPushRequest) (*models.SegmentPushResult, error)
 	ProcessPushFromQueue(ctx context.Context, delivery amqp.Delivery) error
 	ProcessGatewayMessage(ctx context.Context, delivery amqp.Delivery) error
 	GetQueueStats(ctx context.Context) (map[string]int64, error)
 }
 
+const (
+	defaultSegmentPageSize    = 500
+	defaultSyncTimeout        = 10 * time.Second
+	defaultSyncMaxConcurrency = 20
+)
+
+// errDevicesUnregistered is recorded when every device a queued push targeted
+// was unregistered before it could be sent
+var errDevicesUnregistered = errors.New("all target devices were unregistered")
+
+// messageQueue is the part of the push queue used by the push service,
+// implemented by *queue.PushQueue
+type messageQueue interface {
+	EnqueuePush(ctx context.Context, notification models.PushNotification, deviceTokens []string) error
+	EnqueueRetry(ctx context.Context, message queue.PushMessage) error
+	RetriesExhausted(message queue.PushMessage) bool
+	DeadLetter(ctx context.Context, body []byte) error
+	Ack(delivery amqp.Delivery) error
+	Nack(delivery amqp.Delivery, requeue bool) error
+	GetQueueStats(ctx context.Context) (map[string]int64, error)
+}
+
 type pushService struct {
-	deviceRepo repository.DeviceRepository
-	fcmClient  fcm.FCMClient
-	pushQueue  *queue.PushQueue
-	cfg        *config.Config
+	deviceRepo       repository.DeviceRepository
+	idempotencyRepo  repository.IdempotencyRepository
+	notificationRepo repository.NotificationRepository
+	preferenceRepo   repository.PreferenceRepository
+	templateService  TemplateService
+	quietHours       QuietHoursService
+	fcmClient        fcm.FCMClient
+	pushQueue        messageQueue
+	cfg              *config.Config
+	syncSlots        chan struct{} // bounds in-flight FCM calls from synchronous sends
 }
 
-func NewPushService(deviceRepo repository.DeviceRepository, fcmClient fcm.FCMClient, pushQueue *queue.PushQueue, cfg *config.Config) PushService {
+func NewPushService(deviceRepo repository.DeviceRepository, idempotencyRepo repository.IdempotencyRepository, notificationRepo repository.NotificationRepository, preferenceRepo repository.PreferenceRepository, templateService TemplateService, quietHours QuietHoursService, fcmClient fcm.FCMClient, pushQueue *queue.PushQueue, cfg *config.Config) PushService {
 	return &pushService{
-		deviceRepo: deviceRepo,
-		fcmClient:  fcmClient,
-		pushQueue:  pushQueue,
-		cfg:        cfg,
+		deviceRepo:       deviceRepo,
+		idempotencyRepo:  idempotencyRepo,
+		notificationRepo: notificationRepo,
+		preferenceRepo:   preferenceRepo,
+		templateService:  templateService,
+		quietHours:       quietHours,
+		fcmClient:        fcmClient,
+		pushQueue:        pushQueue,
+		cfg:              cfg,
+		syncSlots:        make(chan struct{}, syncMaxConcurrency(cfg)),
+	}
+}
+
+func syncMaxConcurrency(cfg *config.Config) int {
+	if cfg != nil && cfg.Queue.Sync.MaxConcurrency > 0 {
+		return cfg.Queue.Sync.MaxConcurrency
+	}
+	return defaultSyncMaxConcurrency
+}
+
+func (s *pushService) syncTimeout() time.Duration {
+	if s.cfg != nil && s.cfg.Queue.Sync.Timeout > 0 {
+		return s.cfg.Queue.Sync.Timeout
 	}
+	return defaultSyncTimeout
 }
 
-func (s *pushService) SendPush(ctx context.Context, req models.SendPushRequest) error {
-	zap.L().Debug("=== SEND PUSH START ===",
+// SendPush records a notification for the user's devices and enqueues it,
+// returning the notification ID
+func (s *pushService) SendPush(ctx context.Context, req models.SendPushRequest) (string, error) {
+	logger.FromContext(ctx).Debug("=== SEND PUSH START ===",
 		zap.String("user_id", req.UserID),
-		zap.String("title", req.Title),
-		zap.String("body", req.Body),
-		zap.Any("data", req.Data),
+		logger.Content("title", req.Title),
+		logger.Content("body", req.Body),
+		logger.Data("data", req.Data),
 		zap.Strings("platforms", req.Platforms),
+		zap.String("template_id", req.TemplateID),
+	)
+
+	content, err := s.renderContent(ctx, req)
+	if err != nil {
+		return "", err
+	}
+
+	targetDevices, err := s.targetDevices(ctx, req)
+	if err != nil {
+		return "", err
+	}
+
+	// Extract device tokens
+	deviceTokens := make([]string, len(targetDevices))
+	for i, device := range targetDevices {
+		deviceTokens[i] = device.Token
+		logger.FromContext(ctx).Debug("📲 Device token",
+			zap.String("platform", device.Platform),
+			logger.Token("token", device.Token),
+		)
+	}
+
+	// Create notification
+	notification := models.PushNotification{
+		UserID:            req.UserID,
+		Title:             content.Title,
+		Body:              content.Body,
+		Image:             content.Image,
+		Link:              content.Link,
+		Data:              req.Data,
+		Category:          req.Category,
+		PlatformOverrides: content.PlatformOverrides,
+		Locales:           content.Locales,
+		Localization:      req.Localization,
+		Status:            models.NotificationStatusQueued,
+	}
+
+	if err := s.notificationRepo.Create(ctx, &notification, len(deviceTokens)); err != nil {
+		return "", err
+	}
+
+	logger.FromContext(ctx).Info("🚀 Enqueuing push notification to RabbitMQ",
+		zap.String("user_id", req.UserID),
+		zap.Int("device_count", len(deviceTokens)),
+		logger.Content("title", notification.Title),
 	)
 
+	// Enqueue to RabbitMQ instead of sending directly
+	if err := s.pushQueue.EnqueuePush(ctx, notification, deviceTokens); err != nil {
+		logger.FromContext(ctx).Error("💥 Failed to enqueue push notification",
+			zap.String("user_id", req.UserID),
+			zap.Int("device_count", len(deviceTokens)),
+			zap.Error(err),
+		)
+		s.recordStatus(ctx, notification.ID, models.NotificationStatusUpdate{
+			Status:       models.NotificationStatusFailed,
+			ErrorMessage: errorMessage(err),
+		})
+		return "", ErrQueueUnavailable.Wrap(err)
+	}
+
+	logger.FromContext(ctx).Info("✅ Push notification enqueued successfully",
+		zap.String("user_id", req.UserID),
+		zap.String("notification_id", notification.ID),
+		zap.Int("device_count", len(deviceTokens)),
+	)
+
+	return notification.ID, nil
+}
+
+// recordStatus updates a tracked notification. Status tracking is best effort
+// and never fails a send; messages without an ID are not tracked.
+func (s *pushService) recordStatus(ctx context.Context, id string, update models.NotificationStatusUpdate) {
+	if id == "" || s.notificationRepo == nil {
+		return
+	}
+	if err := s.notificationRepo.UpdateStatus(ctx, id, update); err != nil {
+		logger.FromContext(ctx).Warn("Failed to record notification status",
+			zap.String("notification_id", id),
+			zap.String("status", update.Status),
+			zap.Error(err),
+		)
+	}
+}
+
+func errorMessage(err error) *string {
+	message := err.Error()
+	return &message
+}
+
+// SendPushSync sends to the user's devices immediately, bypassing the queue,
+// and reports the outcome for each device. Sends share a concurrency limit with
+// all other synchronous requests and stop at the configured timeout; devices not
+// reached in time are reported with the timeout error code.
+func (s *pushService) SendPushSync(ctx context.Context, req models.SendPushRequest) (*models.SyncPushResult, error) {
+	content, err := s.renderContent(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	targetDevices, err := s.targetDevices(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.syncTimeout())
+	defer cancel()
+
+	notification := models.PushNotification{
+		UserID:            req.UserID,
+		Title:             content.Title,
+		Body:              content.Body,
+		Image:             content.Image,
+		Link:              content.Link,
+		Data:              req.Data,
+		Category:          req.Category,
+		PlatformOverrides: content.PlatformOverrides,
+		Locales:           content.Locales,
+		Localization:      req.Localization,
+		Status:            models.NotificationStatusSending,
+	}
+
+	if err := s.notificationRepo.Create(ctx, &notification, len(targetDevices)); err != nil {
+		return nil, err
+	}
+
+	tokens := make([]string, len(targetDevices))
+	for i, device := range targetDevices {
+		tokens[i] = device.Token
+	}
+	optedOut := s.optedOut(ctx, notification, tokens)
+
+	results := make([]models.DeviceSendResult, len(targetDevices))
+	var wg sync.WaitGroup
+	for i, device := range targetDevices {
+		results[i] = models.DeviceSendResult{
+			DeviceID: device.ID,
+			Platform: device.Platform,
+			Token:    device.Token,
+		}
+		if optedOut[device.Token] {
+			results[i].Suppressed = true
+			continue
+		}
+
+		wg.Add(1)
+		go func(result *models.DeviceSendResult, locale string) {
+			defer wg.Done()
+
+			select {
+			case s.syncSlots <- struct{}{}:
+				defer func() { <-s.syncSlots }()
+			case <-ctx.Done():
+				result.ErrorCode = fcm.ErrorCode(ctx.Err())
+				result.Error = ctx.Err().Error()
+				return
+			}
+
+			messageID, err := s.sendToDevice(ctx, result.Token, result.Platform, locale, notification)
+			if err != nil {
+				result.ErrorCode = fcm.ErrorCode(err)
+				result.Error = err.Error()
+				return
+			}
+			result.Success = true
+			result.MessageID = messageID
+		}(&results[i], device.Locale)
+	}
+	wg.Wait()
+
+	syncResult := &models.SyncPushResult{
+		NotificationID: notification.ID,
+		UserID:         req.UserID,
+		Results:        results,
+	}
+	for _, result := range results {
+		switch {
+		case result.Suppressed:
+			syncResult.SuppressedCount++
+		case result.Success:
+			syncResult.SuccessCount++
+		default:
+			syncResult.FailureCount++
+		}
+	}
+
+	final := models.NotificationStatusUpdate{
+		Status:          models.NotificationStatusSent,
+		SuccessCount:    syncResult.SuccessCount,
+		FailureCount:    syncResult.FailureCount,
+		SuppressedCount: syncResult.SuppressedCount,
+	}
+	if syncResult.SuppressedCount == len(results) {
+		final.Status = models.NotificationStatusSuppressed
+	} else if syncResult.SuccessCount == 0 {
+		final.Status = models.NotificationStatusFailed
+	}
+	// The client may be gone once sends time out, but the outcome is still recorded
+	s.recordStatus(context.WithoutCancel(ctx), notification.ID, final)
+
+	delivered := make([]string, 0, syncResult.SuccessCount)
+	for _, result := range results {
+		if result.Success {
+			delivered = append(delivered, result.Token)
+		}
+	}
+	s.markSeen(context.WithoutCancel(ctx), req.UserID, delivered)
+
+	logger.FromContext(ctx).Info("Synchronous push completed",
+		zap.String("user_id", req.UserID),
+		zap.Int("success_count", syncResult.SuccessCount),
+		zap.Int("failure_count", syncResult.FailureCount),
+	)
+
+	return syncResult, nil
+}
+
+// renderContent returns the content to send: req's rendered template when it
+// names a template or has variables, otherwise its title, body and locale
+// variants as given. An image or link in req takes precedence over the
+// template's.
+func (s *pushService) renderContent(ctx context.Context, req models.SendPushRequest) (*models.RenderedContent, error) {
+	content := &models.RenderedContent{
+		LocalizedContent: models.LocalizedContent{Title: req.Title, Body: req.Body},
+		Locales:          req.Locales,
+	}
+	if req.TemplateID != "" || len(req.Variables) > 0 {
+		rendered, err := s.templateService.Render(ctx, models.RenderRequest{
+			UserID:     req.UserID,
+			TemplateID: req.TemplateID,
+			Version:    req.TemplateVersion,
+			Title:      req.Title,
+			Body:       req.Body,
+			Locales:    req.Locales,
+			Data:       req.Data,
+			Variables:  req.Variables,
+		})
+		if err != nil {
+			return nil, err
+		}
+		content = rendered
+	}
+
+	if req.Image != nil {
+		content.Image = req.Image
+	}
+	if req.Link != nil {
+		content.Link = req.Link
+	}
+	return content, nil
+}
+
+// targetDevices returns the user's devices, filtered to the requested platforms
+func (s *pushService) targetDevices(ctx context.Context, req models.SendPushRequest) ([]models.Device, error) {
 	// Get user's devices
 	devices, err := s.deviceRepo.GetByUserID(ctx, req.UserID)
 	if err != nil {
-		zap.L().Error("❌ FAILED to get user devices from database",
+		logger.FromContext(ctx).Error("❌ FAILED to get user devices from database",
 			zap.String("user_id", req.UserID),
 			zap.Error(err),
 		)
-		return fmt.Errorf("database error: %w", err)
+		return nil, fmt.Errorf("database error: %w", err)
 	}
 
-	zap.L().Debug("📱 Database query result",
+	logger.FromContext(ctx).Debug("📱 Database query result",
 		zap.String("user_id", req.UserID),
 		zap.Int("device_count", len(devices)),
-		zap.Any("devices", devices), // Log all devices found
+		zap.Array("devices", loggedDevices(devices)),
 	)
 
 	if len(devices) == 0 {
-		zap.L().Warn("⚠️ No devices found for user", zap.String("user_id", req.UserID))
-		return fmt.Errorf("no devices found for user: %s", req.UserID)
+		logger.FromContext(ctx).Warn("⚠️ No devices found for user", zap.String("user_id", req.UserID))
+		return nil, ErrNoDevices.WithDetails(map[string]string{"user_id": req.UserID})
 	}
 
 	// Filter by platform if specified
 	var targetDevices []models.Device
 	if len(req.Platforms) > 0 {
-		zap.L().Debug("🔍 Filtering devices by platforms", zap.Strings("platforms", req.Platforms))
+		logger.FromContext(ctx).Debug("🔍 Filtering devices by platforms", zap.Strings("platforms", req.Platforms))
 		for _, device := range devices {
 			for _, platform := range req.Platforms {
 				if device.Platform == platform {
@@ -85,62 +401,43 @@ func (s *pushService) SendPush(ctx context.Context, req models.SendPushRequest)
 		targetDevices = devices
 	}
 
-	zap.L().Debug("🎯 Devices after filtering",
+	logger.FromContext(ctx).Debug("🎯 Devices after filtering",
 		zap.Int("original_count", len(devices)),
 		zap.Int("filtered_count", len(targetDevices)),
 	)
 
 	if len(targetDevices) == 0 {
-		zap.L().Error("❌ No devices match the specified platforms",
+		logger.FromContext(ctx).Error("❌ No devices match the specified platforms",
 			zap.String("user_id", req.UserID),
 			zap.Strings("requested_platforms", req.Platforms),
 			zap.Any("available_platforms", getPlatforms(devices)),
 		)
-		return fmt.Errorf("no devices match platforms: %v", req.Platforms)
+		return nil, ErrNoMatchingPlatforms.WithDetails(map[string][]string{
+			"requested_platforms": req.Platforms,
+			"available_platforms": getPlatforms(devices),
+		})
 	}
 
-	// Extract device tokens
-	deviceTokens := make([]string, len(targetDevices))
-	for i, device := range targetDevices {
-		deviceTokens[i] = device.Token
-		zap.L().Debug("📲 Device token",
-			zap.String("platform", device.Platform),
-			zap.String("token", device.Token), // Log full token for debugging
-		)
-	}
-
-	// Create notification
-	notification := models.PushNotification{
-		UserID: req.UserID,
-		Title:  req.Title,
-		Body:   req.Body,
-		Image:  req.Image,
-		Link:   req.Link,
-		Data:   req.Data,
-		Status: "queued",
-	}
+	return targetDevices, nil
+}
 
-	zap.L().Info("🚀 Enqueuing push notification to RabbitMQ",
-		zap.String("user_id", req.UserID),
-		zap.Int("device_count", len(deviceTokens)),
-		zap.String("title", req.Title),
-	)
+// loggedDevices logs devices with their tokens redacted
+type loggedDevices []models.Device
 
-	// Enqueue to RabbitMQ instead of sending directly
-	if err := s.pushQueue.EnqueuePush(ctx, notification, deviceTokens); err != nil {
-		zap.L().Error("💥 Failed to enqueue push notification",
-			zap.String("user_id", req.UserID),
-			zap.Int("device_count", len(deviceTokens)),
-			zap.Error(err),
-		)
-		return fmt.Errorf("failed to enqueue push notification: %w", err)
+func (d loggedDevices) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, device := range d {
+		err := enc.AppendObject(zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+			enc.AddString("id", device.ID)
+			enc.AddString("platform", device.Platform)
+			enc.AddString("app", device.App)
+			enc.AddString("token", logger.RedactToken(device.Token))
+			enc.AddBool("is_active", device.IsActive)
+			return nil
+		}))
+		if err != nil {
+			return err
+		}
 	}
-
-	zap.L().Info("✅ Push notification enqueued successfully",
-		zap.String("user_id", req.UserID),
-		zap.Int("device_count", len(deviceTokens)),
-	)
-
 	return nil
 }
 
@@ -161,17 +458,18 @@ func getPlatforms(devices []models.Device) []string {
 func (s *pushService) SendBulkPush(ctx context.Context, req models.BulkPushRequest) error {
 	// For bulk pushes, use the queue for better scalability
 	baseNotification := models.PushNotification{
-		Title:  req.Title,
-		Body:   req.Body,
-		Data:   req.Data,
-		Status: "queued",
+		Title:    req.Title,
+		Body:     req.Body,
+		Data:     req.Data,
+		Category: req.Category,
+		Status:   "queued",
 	}
 
 	enqueuedCount := 0
 	for _, userID := range req.UserIDs {
 		devices, err := s.deviceRepo.GetByUserID(ctx, userID)
 		if err != nil {
-			zap.L().Error("Failed to get devices for user",
+			logger.FromContext(ctx).Error("Failed to get devices for user",
 				zap.String("user_id", userID),
 				zap.Error(err),
 			)
@@ -179,7 +477,7 @@ func (s *pushService) SendBulkPush(ctx context.Context, req models.BulkPushReque
 		}
 
 		if len(devices) == 0 {
-			zap.L().Debug("No devices found for user", zap.String("user_id", userID))
+			logger.FromContext(ctx).Debug("No devices found for user", zap.String("user_id", userID))
 			continue
 		}
 
@@ -191,23 +489,37 @@ func (s *pushService) SendBulkPush(ctx context.Context, req models.BulkPushReque
 		userNotification := baseNotification
 		userNotification.UserID = userID
 
+		// Record each user's notification so its status is tracked
+		if err := s.notificationRepo.Create(ctx, &userNotification, len(deviceTokens)); err != nil {
+			logger.FromContext(ctx).Error("Failed to create notification for user",
+				zap.String("user_id", userID),
+				zap.Error(err),
+			)
+			continue
+		}
+
 		// Enqueue to RabbitMQ
 		if err := s.pushQueue.EnqueuePush(ctx, userNotification, deviceTokens); err != nil {
-			zap.L().Error("Failed to enqueue push for user",
+			logger.FromContext(ctx).Error("Failed to enqueue push for user",
 				zap.String("user_id", userID),
 				zap.Error(err),
 			)
+			s.recordStatus(ctx, userNotification.ID, models.NotificationStatusUpdate{
+				Status:       models.NotificationStatusFailed,
+				ErrorMessage: errorMessage(err),
+			})
 			continue
 		}
 
 		enqueuedCount++
-		zap.L().Info("Bulk push enqueued for user",
+		logger.FromContext(ctx).Info("Bulk push enqueued for user",
+			zap.String("notification_id", userNotification.ID),
 			zap.String("user_id", userID),
 			zap.Int("device_count", len(deviceTokens)),
 		)
 	}
 
-	zap.L().Info("Bulk push enqueuing completed",
+	logger.FromContext(ctx).Info("Bulk push enqueuing completed",
 		zap.Int("enqueued_users", enqueuedCount),
 		zap.Int("total_users", len(req.UserIDs)),
 	)
@@ -215,46 +527,228 @@ func (s *pushService) SendBulkPush(ctx context.Context, req models.BulkPushReque
 	return nil
 }
 
+// SendSegmentPush streams the active devices matching a segment from the
+// database page by page and records and enqueues one notification per user
+func (s *pushService) SendSegmentPush(ctx context.Context, req models.SendSegmentPushRequest) (*models.SegmentPushResult, error) {
+	seg, err := segment.Parse(req.Segment)
+	if err != nil {
+		return nil, ErrInvalidSegment.WithDetails(err.Error())
+	}
+
+	pageSize := defaultSegmentPageSize
+	if s.cfg != nil && s.cfg.Queue.Segment.PageSize > 0 {
+		pageSize = s.cfg.Queue.Segment.PageSize
+	}
+
+	baseNotification := models.PushNotification{
+		Title:    req.Title,
+		Body:     req.Body,
+		Image:    req.Image,
+		Link:     req.Link,
+		Data:     req.Data,
+		Category: req.Category,
+		Status:   "queued",
+	}
+
+	result := &models.SegmentPushResult{Segment: seg.String()}
+	err = s.deviceRepo.StreamSegment(ctx, seg, pageSize, func(devices []models.Device) error {
+		// Devices arrive ordered by user, so each run of equal user_ids is one
+		// notification
+		for start := 0; start < len(devices); {
+			end := start
+			for end < len(devices) && devices[end].UserID == devices[start].UserID {
+				end++
+			}
+
+			deviceTokens := make([]string, 0, end-start)
+			for _, device := range devices[start:end] {
+				deviceTokens = append(deviceTokens, device.Token)
+			}
+
+			userNotification := baseNotification
+			userNotification.UserID = devices[start].UserID
+			if err := s.notificationRepo.Create(ctx, &userNotification, len(deviceTokens)); err != nil {
+				return err
+			}
+			if err := s.pushQueue.EnqueuePush(ctx, userNotification, deviceTokens); err != nil {
+				s.recordStatus(ctx, userNotification.ID, models.NotificationStatusUpdate{
+					Status:       models.NotificationStatusFailed,
+					ErrorMessage: errorMessage(err),
+				})
+				return ErrQueueUnavailable.Wrap(err)
+			}
+
+			result.DeviceCount += len(deviceTokens)
+			result.EnqueuedCount++
+			start = end
+		}
+		return nil
+	})
+	if err != nil {
+		logger.FromContext(ctx).Error("Segment push failed",
+			zap.String("segment", seg.String()),
+			zap.Int("device_count", result.DeviceCount),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	logger.FromContext(ctx).Info("Segment push enqueued",
+		zap.String("segment", seg.String()),
+		zap.Int("device_count", result.DeviceCount),
+		zap.Int("enqueued_count", result.EnqueuedCount),
+	)
+
+	return result, nil
+}
+
 // ProcessPushFromQueue processes a single message from the queue
 // This is called by the worker for each message consumed from RabbitMQ
 func (s *pushService) ProcessPushFromQueue(ctx context.Context, delivery amqp.Delivery) error {
+	ctx, span := startConsumerSpan(ctx, "ProcessPushFromQueue", queue.PushQueueName, delivery)
+	err := s.processPushFromQueue(ctx, delivery)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *pushService) processPushFromQueue(ctx context.Context, delivery amqp.Delivery) error {
+	metrics.QueueMessage(queue.PushQueueName, metrics.QueueConsumed)
+
+	// Correlate worker logs with the request that queued the message
+	ctx = logger.WithRequestID(ctx, queue.DeliveryRequestID(delivery))
+
 	var pushMessage queue.PushMessage
 	if err := json.Unmarshal(delivery.Body, &pushMessage); err != nil {
-		zap.L().Error("Failed to unmarshal push message",
+		logger.FromContext(ctx).Error("Failed to unmarshal push message",
 			zap.Error(err),
 		)
 		// Nack and don't requeue - message is malformed
-		if err := s.pushQueue.GetRabbitMQClient().Nack(delivery.DeliveryTag, false, false); err != nil {
-			zap.L().Error("Failed to nack malformed message", zap.Error(err))
+		if err := s.nack(queue.PushQueueName, delivery, false); err != nil {
+			logger.FromContext(ctx).Error("Failed to nack malformed message", zap.Error(err))
 		}
 		return fmt.Errorf("failed to unmarshal message: %w", err)
 	}
+	if logger.RequestIDFromContext(ctx) == "" {
+		ctx = logger.WithRequestID(ctx, pushMessage.RequestID)
+	}
 
 	notification := pushMessage.Notification
 	deviceTokens := pushMessage.DeviceTokens
 
-	zap.L().Info("Processing push message from queue",
+	logger.FromContext(ctx).Info("Processing push message from queue",
 		zap.String("user_id", notification.UserID),
 		zap.Int("device_count", len(deviceTokens)),
-		zap.String("title", notification.Title),
+		logger.Content("title", notification.Title),
 		zap.Int("retry_count", pushMessage.RetryCount),
 	)
 
+	// Drop devices unregistered since the message was queued. The lookup also
+	// provides each device's platform for metrics.
+	lookupCtx, lookupSpan := startDBSpan(ctx, "DeviceRepository.TokenRegistrations")
+	registrations, err := s.deviceRepo.TokenRegistrations(lookupCtx, notification.UserID, deviceTokens)
+	tracing.End(lookupSpan, err)
+	if err != nil {
+		logger.FromContext(ctx).Error("Failed to check unregistered devices, sending to all queued tokens",
+			zap.String("user_id", notification.UserID),
+			zap.Error(err),
+		)
+	} else if activeTokens := deliverableTokens(deviceTokens, registrations); len(activeTokens) < len(deviceTokens) {
+		logger.FromContext(ctx).Info("Dropping unregistered devices from queued push",
+			zap.String("user_id", notification.UserID),
+			zap.Int("queued_count", len(deviceTokens)),
+			zap.Int("active_count", len(activeTokens)),
+		)
+		if len(activeTokens) == 0 {
+			s.recordStatus(ctx, notification.ID, models.NotificationStatusUpdate{
+				Status:       models.NotificationStatusFailed,
+				ErrorMessage: errorMessage(errDevicesUnregistered),
+			})
+			if err := s.ack(queue.PushQueueName, delivery); err != nil {
+				logger.FromContext(ctx).Error("Failed to ack message", zap.Error(err))
+				return err
+			}
+			return nil
+		}
+		deviceTokens = activeTokens
+		pushMessage.DeviceTokens = activeTokens
+	}
+
+	// Suppress the notification on devices that opted out of its category.
+	// They are dropped from the message, so retries do not count them again.
+	suppressedCount := 0
+	if optedOut := s.optedOut(ctx, notification, deviceTokens); len(optedOut) > 0 {
+		allowedTokens := make([]string, 0, len(deviceTokens))
+		for _, token := range deviceTokens {
+			if !optedOut[token] {
+				allowedTokens = append(allowedTokens, token)
+			}
+		}
+		suppressedCount = len(deviceTokens) - len(allowedTokens)
+		logger.FromContext(ctx).Info("Suppressed push on devices that opted out of its category",
+			zap.String("user_id", notification.UserID),
+			zap.String("category", notification.Category),
+			zap.Int("suppressed_count", suppressedCount),
+			zap.Int("remaining_count", len(allowedTokens)),
+		)
+		if len(allowedTokens) == 0 {
+			s.recordStatus(ctx, notification.ID, models.NotificationStatusUpdate{
+				Status:          models.NotificationStatusSuppressed,
+				SuppressedCount: suppressedCount,
+			})
+			if err := s.ack(queue.PushQueueName, delivery); err != nil {
+				logger.FromContext(ctx).Error("Failed to ack message", zap.Error(err))
+				return err
+			}
+			return nil
+		}
+		deviceTokens = allowedTokens
+		pushMessage.DeviceTokens = allowedTokens
+	}
+
+	// Hold the notification for devices in quiet hours until they end. If it
+	// cannot be held, it is sent now rather than risk losing it.
+	if s.quietHours != nil {
+		sendNow, err := s.quietHours.Hold(ctx, pushMessage, registrations)
+		if err != nil {
+			logger.FromContext(ctx).Warn("Failed to hold push for quiet hours, sending now",
+				zap.String("user_id", notification.UserID),
+				zap.Error(err),
+			)
+		} else if len(sendNow) == 0 {
+			s.recordStatus(ctx, notification.ID, models.NotificationStatusUpdate{
+				Status:          models.NotificationStatusDeferred,
+				SuppressedCount: suppressedCount,
+			})
+			if err := s.ack(queue.PushQueueName, delivery); err != nil {
+				logger.FromContext(ctx).Error("Failed to ack message", zap.Error(err))
+				return err
+			}
+			return nil
+		} else {
+			deviceTokens = sendNow
+			pushMessage.DeviceTokens = sendNow
+		}
+	}
+
+	s.recordStatus(ctx, notification.ID, models.NotificationStatusUpdate{
+		Status:          models.NotificationStatusSending,
+		SuppressedCount: suppressedCount,
+	})
+
 	// Validate tokens if validation is enabled
 	validTokens := make([]string, 0, len(deviceTokens))
 	if s.cfg != nil && s.cfg.Queue.Validation.Enabled {
 		for _, token := range deviceTokens {
 			validationCtx, cancel := context.WithTimeout(ctx, s.cfg.Queue.Validation.Timeout)
+			validationCtx, span := tracing.Tracer().Start(validationCtx, "fcm.ValidateToken", trace.WithSpanKind(trace.SpanKindClient))
 			err := s.fcmClient.ValidateToken(validationCtx, token)
+			tracing.End(span, err)
 			cancel()
+			metrics.TokenValidation(metrics.StageSend, err)
 
 			if err != nil {
-				maskedToken := "***"
-				if len(token) > 20 {
-					maskedToken = token[:10] + "..." + token[len(token)-10:]
-				}
-				zap.L().Warn("Token validation failed, skipping",
-					zap.String("token", maskedToken),
+				logger.FromContext(ctx).Warn("Token validation failed, skipping",
+					logger.Token("token", token),
 					zap.Error(err),
 				)
 				continue
@@ -263,23 +757,28 @@ func (s *pushService) ProcessPushFromQueue(ctx context.Context, delivery amqp.De
 		}
 
 		if len(validTokens) == 0 {
-			zap.L().Warn("No valid tokens found, moving to dead letter queue",
+			logger.FromContext(ctx).Warn("No valid tokens found, moving to dead letter queue",
 				zap.String("user_id", notification.UserID),
 				zap.Int("original_count", len(deviceTokens)),
 			)
 			// All tokens invalid - move to dead letter queue
+			s.recordRetry(ctx, pushMessage, fmt.Errorf("no valid device tokens"))
 			if err := s.pushQueue.EnqueueRetry(ctx, pushMessage); err != nil {
-				zap.L().Error("Failed to enqueue to retry/dead letter", zap.Error(err))
+				logger.FromContext(ctx).Error("Failed to enqueue to retry/dead letter, requeuing", zap.Error(err))
+				if err := s.nack(queue.PushQueueName, delivery, true); err != nil {
+					logger.FromContext(ctx).Error("Failed to nack message", zap.Error(err))
+				}
+				return fmt.Errorf("failed to enqueue retry: %w", err)
 			}
 			// Ack the message since we've handled it
-			if err := s.pushQueue.GetRabbitMQClient().Ack(delivery.DeliveryTag, false); err != nil {
-				zap.L().Error("Failed to ack message", zap.Error(err))
+			if err := s.ack(queue.PushQueueName, delivery); err != nil {
+				logger.FromContext(ctx).Error("Failed to ack message", zap.Error(err))
 			}
 			return fmt.Errorf("no valid tokens")
 		}
 
 		deviceTokens = validTokens
-		zap.L().Debug("Token validation completed",
+		logger.FromContext(ctx).Debug("Token validation completed",
 			zap.Int("original_count", len(pushMessage.DeviceTokens)),
 			zap.Int("valid_count", len(validTokens)),
 		)
@@ -288,75 +787,226 @@ func (s *pushService) ProcessPushFromQueue(ctx context.Context, delivery amqp.De
 	// Update notification status
 	notification.Status = "sending"
 
-	// Send notifications via FCM
-	successCount, failureCount, err := s.fcmClient.SendMultiple(ctx, deviceTokens, notification)
-	if err != nil {
-		zap.L().Error("Failed to send push notifications",
-			zap.String("user_id", notification.UserID),
-			zap.Int("device_count", len(deviceTokens)),
-			zap.Error(err),
-		)
-		// Enqueue for retry
-		if err := s.pushQueue.EnqueueRetry(ctx, pushMessage); err != nil {
-			zap.L().Error("Failed to enqueue retry", zap.Error(err))
-		}
-		// Nack and requeue via retry queue
-		if err := s.pushQueue.GetRabbitMQClient().Nack(delivery.DeliveryTag, false, false); err != nil {
-			zap.L().Error("Failed to nack message", zap.Error(err))
+	// Send notifications via FCM, one device at a time for per-device error tracking
+	successCount, failureCount := 0, 0
+	delivered := make([]string, 0, len(deviceTokens))
+	for _, token := range deviceTokens {
+		if _, err := s.sendToDevice(ctx, token, platformOf(registrations, token), registrations[token].Locale, notification); err != nil {
+			failureCount++
+			continue
 		}
-		return fmt.Errorf("fcm send failed: %w", err)
+		successCount++
+		delivered = append(delivered, token)
 	}
+	s.markSeen(ctx, notification.UserID, delivered)
 
 	// Check if all sends failed
 	if failureCount == len(deviceTokens) {
-		zap.L().Warn("All push notifications failed, enqueuing for retry",
+		logger.FromContext(ctx).Warn("All push notifications failed, enqueuing for retry",
 			zap.String("user_id", notification.UserID),
 			zap.Int("device_count", len(deviceTokens)),
 		)
 		// Enqueue for retry
+		s.recordRetry(ctx, pushMessage, fmt.Errorf("all %d sends failed", failureCount))
 		if err := s.pushQueue.EnqueueRetry(ctx, pushMessage); err != nil {
-			zap.L().Error("Failed to enqueue retry", zap.Error(err))
+			// Requeue rather than lose the notification
+			logger.FromContext(ctx).Error("Failed to enqueue retry, requeuing", zap.Error(err))
+			if err := s.nack(queue.PushQueueName, delivery, true); err != nil {
+				logger.FromContext(ctx).Error("Failed to nack message", zap.Error(err))
+			}
+			return fmt.Errorf("failed to enqueue retry: %w", err)
 		}
 		// Nack - message will go to retry queue
-		if err := s.pushQueue.GetRabbitMQClient().Nack(delivery.DeliveryTag, false, false); err != nil {
-			zap.L().Error("Failed to nack message", zap.Error(err))
+		if err := s.nack(queue.PushQueueName, delivery, false); err != nil {
+			logger.FromContext(ctx).Error("Failed to nack message", zap.Error(err))
 		}
 		return fmt.Errorf("all notifications failed")
 	}
 
 	// Success - ack the message
-	zap.L().Info("Push notifications sent successfully",
+	logger.FromContext(ctx).Info("Push notifications sent successfully",
 		zap.String("user_id", notification.UserID),
 		zap.Int("device_count", len(deviceTokens)),
 		zap.Int("success_count", successCount),
 		zap.Int("failure_count", failureCount),
 	)
 
-	if err := s.pushQueue.GetRabbitMQClient().Ack(delivery.DeliveryTag, false); err != nil {
-		zap.L().Error("Failed to ack message", zap.Error(err))
+	s.recordStatus(ctx, notification.ID, models.NotificationStatusUpdate{
+		Status:       models.NotificationStatusSent,
+		SuccessCount: successCount,
+		FailureCount: failureCount,
+	})
+
+	if err := s.ack(queue.PushQueueName, delivery); err != nil {
+		logger.FromContext(ctx).Error("Failed to ack message", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// optedOut returns the tokens of devices that opted out of notification's
+// category. Mandatory categories are not checked. If the check fails, every
+// device is sent to, as a duplicate is preferred over a lost notification.
+func (s *pushService) optedOut(ctx context.Context, notification models.PushNotification, tokens []string) map[string]bool {
+	if models.IsMandatoryCategory(notification.Category) || s.preferenceRepo == nil {
+		return nil
+	}
+
+	lookupCtx, lookupSpan := startDBSpan(ctx, "PreferenceRepository.OptedOut")
+	optedOut, err := s.preferenceRepo.OptedOut(lookupCtx, notification.UserID, notification.Category, tokens)
+	tracing.End(lookupSpan, err)
+	if err != nil {
+		logger.FromContext(ctx).Warn("Failed to check notification preferences, sending to all devices",
+			zap.String("user_id", notification.UserID),
+			zap.String("category", notification.Category),
+			zap.Error(err),
+		)
+		return nil
+	}
+
+	metrics.PreferenceSuppressions.WithLabelValues(notification.Category).Add(float64(len(optedOut)))
+	return optedOut
+}
+
+// sendToDevice sends a notification to one device, in the variant for its
+// locale and with the overrides for its platform, and records the FCM latency
+// and outcome
+func (s *pushService) sendToDevice(ctx context.Context, token, platform, locale string, notification models.PushNotification) (string, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "fcm.Send",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("push.platform", platform)),
+	)
+	start := time.Now()
+	messageID, err := s.fcmClient.Send(ctx, token, notification.ForDevice(platform, locale))
+	metrics.FCMSendDuration.WithLabelValues(platform).Observe(time.Since(start).Seconds())
+	metrics.FCMSends.WithLabelValues(platform, fcm.ErrorCode(err)).Inc()
+	if err != nil {
+		span.SetAttributes(attribute.String("fcm.error_code", fcm.ErrorCode(err)))
+	} else {
+		span.SetAttributes(attribute.String("fcm.message_id", messageID))
+	}
+	tracing.End(span, err)
+	return messageID, err
+}
+
+// startConsumerSpan starts the span for processing a delivery from queueName,
+// continuing the trace of the request that published it
+func startConsumerSpan(ctx context.Context, name, queueName string, delivery amqp.Delivery) (context.Context, trace.Span) {
+	ctx = rabbitmq.ExtractTraceContext(ctx, delivery.Headers)
+	return tracing.Tracer().Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingSystemRabbitMQ,
+			semconv.MessagingOperationTypeProcess,
+			semconv.MessagingDestinationName(queueName),
+			attribute.Bool("messaging.rabbitmq.redelivered", delivery.Redelivered),
+		),
+	)
+}
+
+// startDBSpan starts the span for a database call made while processing a delivery
+func startDBSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	return tracing.Tracer().Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemNamePostgreSQL),
+	)
+}
+
+// markSeen records that userID's devices with tokens were delivered to.
+// Failing to record it only affects stale device cleanup, so it is logged.
+func (s *pushService) markSeen(ctx context.Context, userID string, tokens []string) {
+	if len(tokens) == 0 {
+		return
+	}
+	if err := s.deviceRepo.MarkSeen(ctx, userID, tokens); err != nil {
+		logger.FromContext(ctx).Warn("Failed to mark delivered devices seen",
+			zap.String("user_id", userID),
+			zap.Error(err),
+		)
+	}
+}
+
+// ack acknowledges a delivery from queueName and counts it
+func (s *pushService) ack(queueName string, delivery amqp.Delivery) error {
+	if err := s.pushQueue.Ack(delivery); err != nil {
 		return err
 	}
+	metrics.QueueMessage(queueName, metrics.QueueAcked)
+	return nil
+}
 
+// nack rejects a delivery from queueName and counts it
+func (s *pushService) nack(queueName string, delivery amqp.Delivery, requeue bool) error {
+	if err := s.pushQueue.Nack(delivery, requeue); err != nil {
+		return err
+	}
+	metrics.QueueMessage(queueName, metrics.QueueNacked)
 	return nil
 }
 
+// deliverableTokens drops tokens that were unregistered or moved to another
+// user. Tokens that were never registered are kept.
+func deliverableTokens(tokens []string, registrations map[string]models.TokenRegistration) []string {
+	deliverable := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		if registration, ok := registrations[token]; !ok || registration.Active {
+			deliverable = append(deliverable, token)
+		}
+	}
+	return deliverable
+}
+
+// platformOf returns the platform of token, or "unknown" when it is not registered
+func platformOf(registrations map[string]models.TokenRegistration, token string) string {
+	if registration, ok := registrations[token]; ok {
+		return registration.Platform
+	}
+	return "unknown"
+}
+
+// recordRetry marks a tracked notification as retrying, or failed if the
+// message is about to be dead-lettered
+func (s *pushService) recordRetry(ctx context.Context, message queue.PushMessage, cause error) {
+	status := models.NotificationStatusRetrying
+	if s.pushQueue.RetriesExhausted(message) {
+		status = models.NotificationStatusFailed
+	}
+	s.recordStatus(ctx, message.Notification.ID, models.NotificationStatusUpdate{
+		Status:       status,
+		FailureCount: len(message.DeviceTokens),
+		ErrorMessage: errorMessage(cause),
+	})
+}
+
 // GetQueueStats returns statistics about the push queues
 func (s *pushService) GetQueueStats(ctx context.Context) (map[string]int64, error) {
 	return s.pushQueue.GetQueueStats(ctx)
 }
 
 // ProcessGatewayMessage processes messages from the API Gateway's push.queue
-// API Gateway sends: {notification_id, user_id, push_token, name, template: {subject, body}, ...}
+// API Gateway sends: {notification_id, user_id, push_token, name, category, template: {subject, body}, ...}
+// The template may also carry locales: {"pt-BR": {subject, body}, ...} and
+// title_loc_key, title_loc_args, body_loc_key and body_loc_args.
 func (s *pushService) ProcessGatewayMessage(ctx context.Context, delivery amqp.Delivery) error {
+	ctx, span := startConsumerSpan(ctx, "ProcessGatewayMessage", queue.GatewayPushQueueName, delivery)
+	err := s.processGatewayMessage(ctx, delivery)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *pushService) processGatewayMessage(ctx context.Context, delivery amqp.Delivery) error {
+	metrics.QueueMessage(queue.GatewayPushQueueName, metrics.QueueConsumed)
+
 	// Parse API Gateway message format
 	var gatewayMessage map[string]interface{}
 	if err := json.Unmarshal(delivery.Body, &gatewayMessage); err != nil {
-		zap.L().Error("Failed to unmarshal gateway message",
+		logger.FromContext(ctx).Error("Failed to unmarshal gateway message",
 			zap.Error(err),
 		)
 		// Nack and don't requeue - message is malformed
-		if err := s.pushQueue.GetRabbitMQClient().Nack(delivery.DeliveryTag, false, false); err != nil {
-			zap.L().Error("Failed to nack malformed gateway message", zap.Error(err))
+		if err := s.nack(queue.GatewayPushQueueName, delivery, false); err != nil {
+			logger.FromContext(ctx).Error("Failed to nack malformed gateway message", zap.Error(err))
 		}
 		return fmt.Errorf("failed to unmarshal gateway message: %w", err)
 	}
@@ -364,22 +1014,46 @@ func (s *pushService) ProcessGatewayMessage(ctx context.Context, delivery amqp.D
 	// Extract data from gateway message
 	notificationID, ok := gatewayMessage["notification_id"].(string)
 	if !ok {
-		zap.L().Error("Missing or invalid notification_id in gateway message")
-		if err := s.pushQueue.GetRabbitMQClient().Nack(delivery.DeliveryTag, false, false); err != nil {
-			zap.L().Error("Failed to nack gateway message", zap.Error(err))
+		logger.FromContext(ctx).Error("Missing or invalid notification_id in gateway message")
+		if err := s.nack(queue.GatewayPushQueueName, delivery, false); err != nil {
+			logger.FromContext(ctx).Error("Failed to nack gateway message", zap.Error(err))
 		}
 		return fmt.Errorf("missing notification_id")
 	}
+	// The gateway's notification ID is the correlation ID for everything below
+	ctx = logger.WithRequestID(ctx, notificationID)
 
 	userID, ok := gatewayMessage["user_id"].(string)
 	if !ok {
-		zap.L().Error("Missing or invalid user_id in gateway message")
-		if err := s.pushQueue.GetRabbitMQClient().Nack(delivery.DeliveryTag, false, false); err != nil {
-			zap.L().Error("Failed to nack gateway message", zap.Error(err))
+		logger.FromContext(ctx).Error("Missing or invalid user_id in gateway message")
+		if err := s.nack(queue.GatewayPushQueueName, delivery, false); err != nil {
+			logger.FromContext(ctx).Error("Failed to nack gateway message", zap.Error(err))
 		}
 		return fmt.Errorf("missing user_id")
 	}
 
+	// Drop redeliveries of a notification that was already enqueued. The
+	// notification is recorded only once it is enqueued, so a worker dying
+	// mid-way lets the redelivery through. If the check itself fails, sending
+	// a duplicate is preferred over dropping it.
+	processed, err := s.idempotencyRepo.MessageProcessed(ctx, notificationID)
+	if err != nil {
+		logger.FromContext(ctx).Warn("Failed to check gateway message for duplicates, processing anyway",
+			zap.String("notification_id", notificationID),
+			zap.Error(err),
+		)
+	} else if processed {
+		logger.FromContext(ctx).Info("Skipping duplicate gateway message",
+			zap.String("notification_id", notificationID),
+			zap.String("user_id", userID),
+		)
+		if err := s.ack(queue.GatewayPushQueueName, delivery); err != nil {
+			logger.FromContext(ctx).Error("Failed to ack duplicate gateway message", zap.Error(err))
+			return err
+		}
+		return nil
+	}
+
 	// Get template (may be nil)
 	var template map[string]interface{}
 	if templateVal, ok := gatewayMessage["template"]; ok {
@@ -400,10 +1074,60 @@ func (s *pushService) ProcessGatewayMessage(ctx context.Context, delivery amqp.D
 		}
 	}
 
+	// Extract data if present
+	var data map[string]interface{}
+	if dataVal, ok := gatewayMessage["data"]; ok {
+		if dataMap, ok := dataVal.(map[string]interface{}); ok {
+			data = dataMap
+		}
+	}
+
+	// Fill placeholders such as {{name}} from the message and user attributes
+	name, _ := gatewayMessage["name"].(string)
+	content, err := s.templateService.Render(ctx, models.RenderRequest{
+		UserID:  userID,
+		Title:   title,
+		Body:    body,
+		Locales: gatewayLocales(template),
+		Name:    name,
+		Data:    data,
+	})
+	if errors.Is(err, ErrTemplateRender) {
+		// Rendering fails the same way on every attempt
+		logger.FromContext(ctx).Error("Failed to render gateway message, moving to dead letter queue",
+			zap.String("notification_id", notificationID),
+			zap.Error(err),
+		)
+		if err := s.pushQueue.DeadLetter(ctx, delivery.Body); err != nil {
+			logger.FromContext(ctx).Error("Failed to dead-letter gateway message", zap.Error(err))
+			if err := s.nack(queue.GatewayPushQueueName, delivery, true); err != nil {
+				logger.FromContext(ctx).Error("Failed to nack gateway message", zap.Error(err))
+			}
+			return fmt.Errorf("failed to dead-letter gateway message: %w", err)
+		}
+		if err := s.ack(queue.GatewayPushQueueName, delivery); err != nil {
+			logger.FromContext(ctx).Error("Failed to ack gateway message", zap.Error(err))
+		}
+		return err
+	}
+	if err != nil {
+		logger.FromContext(ctx).Error("Failed to load template variables for gateway message",
+			zap.String("notification_id", notificationID),
+			zap.Error(err),
+		)
+		if err := s.nack(queue.GatewayPushQueueName, delivery, true); err != nil {
+			logger.FromContext(ctx).Error("Failed to nack gateway message", zap.Error(err))
+		}
+		return fmt.Errorf("failed to render gateway message: %w", err)
+	}
+	title, body = content.Title, content.Body
+
 	// Get device tokens from database
-	devices, err := s.deviceRepo.GetByUserID(ctx, userID)
+	lookupCtx, lookupSpan := startDBSpan(ctx, "DeviceRepository.GetByUserID")
+	devices, err := s.deviceRepo.GetByUserID(lookupCtx, userID)
+	tracing.End(lookupSpan, err)
 	if err != nil {
-		zap.L().Warn("Failed to get devices from database, using push_token fallback",
+		logger.FromContext(ctx).Warn("Failed to get devices from database, using push_token fallback",
 			zap.String("user_id", userID),
 			zap.Error(err),
 		)
@@ -416,7 +1140,7 @@ func (s *pushService) ProcessGatewayMessage(ctx context.Context, delivery amqp.D
 		for i, device := range devices {
 			deviceTokens[i] = device.Token
 		}
-		zap.L().Info("Using device tokens from database",
+		logger.FromContext(ctx).Info("Using device tokens from database",
 			zap.String("user_id", userID),
 			zap.Int("device_count", len(deviceTokens)),
 		)
@@ -424,69 +1148,90 @@ func (s *pushService) ProcessGatewayMessage(ctx context.Context, delivery amqp.D
 		// Fallback to push_token from gateway message
 		if pushToken, ok := gatewayMessage["push_token"].(string); ok && pushToken != "" {
 			deviceTokens = []string{pushToken}
-			zap.L().Info("Using push_token from gateway message",
+			logger.FromContext(ctx).Info("Using push_token from gateway message",
 				zap.String("user_id", userID),
 			)
 		} else {
-			zap.L().Warn("No devices found and no push_token provided",
+			logger.FromContext(ctx).Warn("No devices found and no push_token provided",
 				zap.String("user_id", userID),
 				zap.String("notification_id", notificationID),
 			)
 			// Ack the message since we can't process it
-			if err := s.pushQueue.GetRabbitMQClient().Ack(delivery.DeliveryTag, false); err != nil {
-				zap.L().Error("Failed to ack gateway message", zap.Error(err))
+			if err := s.ack(queue.GatewayPushQueueName, delivery); err != nil {
+				logger.FromContext(ctx).Error("Failed to ack gateway message", zap.Error(err))
 			}
 			return fmt.Errorf("no device tokens available for user: %s", userID)
 		}
 	}
 
-	// Extract data if present
-	var data map[string]interface{}
-	if dataVal, ok := gatewayMessage["data"]; ok {
-		if dataMap, ok := dataVal.(map[string]interface{}); ok {
-			data = dataMap
-		}
-	}
-
 	// Create notification
+	category, _ := gatewayMessage["category"].(string)
 	notification := models.PushNotification{
-		ID:        notificationID,
-		UserID:    userID,
-		Title:     title,
-		Body:      body,
-		Data:      data,
-		Status:    "queued",
-		CreatedAt: time.Now(),
+		UserID:       userID,
+		Title:        title,
+		Body:         body,
+		Data:         data,
+		Category:     category,
+		Status:       models.NotificationStatusQueued,
+		CreatedAt:    time.Now(),
+		Locales:      content.Locales,
+		Localization: gatewayLocalization(template),
+	}
+
+	// Track the notification under the gateway's ID. Tracking is best effort,
+	// so a notification that cannot be recorded is still sent, untracked.
+	if s.notificationRepo != nil {
+		createCtx, createSpan := startDBSpan(ctx, "NotificationRepository.CreateForGateway")
+		err := s.notificationRepo.CreateForGateway(createCtx, notificationID, &notification, len(deviceTokens))
+		tracing.End(createSpan, err)
+		if err != nil {
+			logger.FromContext(ctx).Warn("Failed to record gateway notification, sending untracked",
+				zap.String("notification_id", notificationID),
+				zap.Error(err),
+			)
+			notification.ID = ""
+		}
 	}
 
-	zap.L().Info("Processing gateway push message",
+	logger.FromContext(ctx).Info("Processing gateway push message",
 		zap.String("notification_id", notificationID),
 		zap.String("user_id", userID),
 		zap.Int("device_count", len(deviceTokens)),
-		zap.String("title", title),
+		logger.Content("title", title),
 	)
 
 	// Enqueue to internal push queue for processing
 	if err := s.pushQueue.EnqueuePush(ctx, notification, deviceTokens); err != nil {
-		zap.L().Error("Failed to enqueue push from gateway",
+		logger.FromContext(ctx).Error("Failed to enqueue push from gateway",
 			zap.String("notification_id", notificationID),
 			zap.String("user_id", userID),
 			zap.Error(err),
 		)
 		// Nack and requeue
-		if err := s.pushQueue.GetRabbitMQClient().Nack(delivery.DeliveryTag, false, true); err != nil {
-			zap.L().Error("Failed to nack gateway message", zap.Error(err))
+		if err := s.nack(queue.GatewayPushQueueName, delivery, true); err != nil {
+			logger.FromContext(ctx).Error("Failed to nack gateway message", zap.Error(err))
 		}
 		return fmt.Errorf("failed to enqueue push: %w", err)
 	}
 
+	ttl := defaultIdempotencyTTL
+	if s.cfg != nil {
+		ttl = idempotencyTTL(&s.cfg.Idempotency)
+	}
+	if err := s.idempotencyRepo.RecordMessage(ctx, notificationID, ttl); err != nil {
+		logger.FromContext(ctx).Warn("Failed to record gateway message, a redelivery will be sent again",
+			zap.String("notification_id", notificationID),
+			zap.Error(err),
+		)
+	}
+
 	// Ack the gateway message
-	if err := s.pushQueue.GetRabbitMQClient().Ack(delivery.DeliveryTag, false); err != nil {
-		zap.L().Error("Failed to ack gateway message", zap.Error(err))
+	if err := s.ack(queue.GatewayPushQueueName, delivery); err != nil {
+		logger.FromContext(ctx).Error("Failed to ack gateway message", zap.Error(err))
 		return err
 	}
 
-	zap.L().Info("Gateway push message enqueued successfully",
+	logger.FromContext(ctx).Info("Gateway push message enqueued successfully",
 		zap.String("notification_id", notificationID),
 		zap.String("user_id", userID),
 	)
@@ -494,23 +1239,62 @@ func (s *pushService) ProcessGatewayMessage(ctx context.Context, delivery amqp.D
 	return nil
 }
 
-func (s *pushService) SendDirect(ctx context.Context, token string, notification models.PushNotification) error {
-	zap.L().Debug("🔧 Sending direct FCM message",
-		zap.String("token", token),
-		zap.String("title", notification.Title),
-		zap.String("body", notification.Body),
-	)
+// gatewayLocales returns the subject and body variants in a gateway template's
+// locales, skipping any without both
+func gatewayLocales(template map[string]interface{}) map[string]models.LocalizedContent {
+	localesMap, ok := template["locales"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
 
-	err := s.fcmClient.Send(ctx, token, notification)
-	if err != nil {
-		zap.L().Error("💥 FCM direct send failed",
-			zap.String("token", token),
-			zap.String("error_type", fmt.Sprintf("%T", err)),
-			zap.Error(err),
-		)
-		return err
+	locales := make(map[string]models.LocalizedContent, len(localesMap))
+	for locale, value := range localesMap {
+		variant, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		subject, _ := variant["subject"].(string)
+		body, _ := variant["body"].(string)
+		if subject == "" || body == "" {
+			continue
+		}
+		locales[locale] = models.LocalizedContent{Title: subject, Body: body}
+	}
+	if len(locales) == 0 {
+		return nil
 	}
+	return locales
+}
 
-	zap.L().Info("✅ FCM direct send successful")
-	return nil
+// gatewayLocalization returns the localization keys in a gateway template, or
+// nil if it has none
+func gatewayLocalization(template map[string]interface{}) *models.LocalizationKeys {
+	keys := models.LocalizationKeys{
+		TitleLocKey:  stringValue(template["title_loc_key"]),
+		TitleLocArgs: stringValues(template["title_loc_args"]),
+		BodyLocKey:   stringValue(template["body_loc_key"]),
+		BodyLocArgs:  stringValues(template["body_loc_args"]),
+	}
+	if keys.TitleLocKey == "" && keys.BodyLocKey == "" {
+		return nil
+	}
+	return &keys
+}
+
+func stringValue(value interface{}) string {
+	s, _ := value.(string)
+	return s
+}
+
+// stringValues returns the strings in a JSON array, formatting other values
+func stringValues(value interface{}) []string {
+	values, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+	result := make([]string, len(values))
+	for i, v := range values {
+		result[i] = fmt.Sprint(v)
+	}
+	return result
 }
@@ -0,0 +1,369 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"push-service/internal/models"
+	"push-service/internal/platform/fcm"
+	"push-service/internal/queue"
+	"push-service/internal/repository"
+	"push-service/internal/segment"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// The fakes below embed the interfaces they stand in for, so calling a method
+// a test does not expect panics
+
+type fakeDeviceRepo struct {
+	repository.DeviceRepository
+	devices []models.Device
+	seen    []string
+}
+
+func (r *fakeDeviceRepo) GetByUserID(ctx context.Context, userID string) ([]models.Device, error) {
+	var devices []models.Device
+	for _, device := range r.devices {
+		if device.UserID == userID {
+			devices = append(devices, device)
+		}
+	}
+	return devices, nil
+}
+
+// StreamSegment returns every device, one user per page
+func (r *fakeDeviceRepo) StreamSegment(ctx context.Context, seg *segment.Segment, pageSize int, fn func([]models.Device) error) error {
+	for start := 0; start < len(r.devices); {
+		end := start
+		for end < len(r.devices) && r.devices[end].UserID == r.devices[start].UserID {
+			end++
+		}
+		if err := fn(r.devices[start:end]); err != nil {
+			return err
+		}
+		start = end
+	}
+	return nil
+}
+
+func (r *fakeDeviceRepo) TokenRegistrations(ctx context.Context, userID string, tokens []string) (map[string]models.TokenRegistration, error) {
+	registrations := make(map[string]models.TokenRegistration, len(r.devices))
+	for _, device := range r.devices {
+		registrations[device.Token] = models.TokenRegistration{App: device.App, Active: true}
+	}
+	return registrations, nil
+}
+
+func (r *fakeDeviceRepo) MarkSeen(ctx context.Context, userID string, tokens []string) error {
+	r.seen = append(r.seen, tokens...)
+	return nil
+}
+
+type fakeIdempotencyRepo struct {
+	repository.IdempotencyRepository
+}
+
+func (r *fakeIdempotencyRepo) MessageProcessed(ctx context.Context, notificationID string) (bool, error) {
+	return false, nil
+}
+
+func (r *fakeIdempotencyRepo) RecordMessage(ctx context.Context, notificationID string, ttl time.Duration) error {
+	return nil
+}
+
+// fakeNotificationRepo tracks notifications and the updates to their status
+type fakeNotificationRepo struct {
+	repository.NotificationRepository
+	created    []models.PushNotification
+	gatewayIDs map[string]string
+	statuses   map[string]models.NotificationStatusUpdate
+}
+
+func newFakeNotificationRepo() *fakeNotificationRepo {
+	return &fakeNotificationRepo{
+		gatewayIDs: make(map[string]string),
+		statuses:   make(map[string]models.NotificationStatusUpdate),
+	}
+}
+
+func (r *fakeNotificationRepo) Create(ctx context.Context, notification *models.PushNotification, deviceCount int) error {
+	notification.ID = fmt.Sprintf("00000000-0000-0000-0000-%012d", len(r.created)+1)
+	r.created = append(r.created, *notification)
+	r.statuses[notification.ID] = models.NotificationStatusUpdate{Status: notification.Status}
+	return nil
+}
+
+func (r *fakeNotificationRepo) CreateForGateway(ctx context.Context, gatewayID string, notification *models.PushNotification, deviceCount int) error {
+	notification.ID = "00000000-0000-0000-0000-000000000001"
+	r.gatewayIDs[gatewayID] = notification.ID
+	r.statuses[notification.ID] = models.NotificationStatusUpdate{Status: notification.Status}
+	return nil
+}
+
+func (r *fakeNotificationRepo) UpdateStatus(ctx context.Context, id string, update models.NotificationStatusUpdate) error {
+	r.statuses[id] = update
+	return nil
+}
+
+type fakePreferenceRepo struct {
+	repository.PreferenceRepository
+	optedOut map[string]bool
+}
+
+func (r *fakePreferenceRepo) OptedOut(ctx context.Context, userID, category string, tokens []string) (map[string]bool, error) {
+	return r.optedOut, nil
+}
+
+type fakeTemplateService struct {
+	TemplateService
+}
+
+func (s *fakeTemplateService) Render(ctx context.Context, req models.RenderRequest) (*models.RenderedContent, error) {
+	return &models.RenderedContent{
+		LocalizedContent: models.LocalizedContent{Title: req.Title, Body: req.Body},
+		Locales:          req.Locales,
+	}, nil
+}
+
+// fakeQueue keeps published messages instead of publishing them
+type fakeQueue struct {
+	messageQueue
+	pushes   []queue.PushMessage
+	acked    int
+	requeued int
+	retryErr error
+}
+
+func (q *fakeQueue) EnqueuePush(ctx context.Context, notification models.PushNotification, deviceTokens []string) error {
+	q.pushes = append(q.pushes, queue.PushMessage{Notification: notification, DeviceTokens: deviceTokens})
+	return nil
+}
+
+func (q *fakeQueue) EnqueueRetry(ctx context.Context, message queue.PushMessage) error {
+	return q.retryErr
+}
+
+func (q *fakeQueue) RetriesExhausted(message queue.PushMessage) bool {
+	return false
+}
+
+func (q *fakeQueue) Ack(delivery amqp.Delivery) error {
+	q.acked++
+	return nil
+}
+
+func (q *fakeQueue) Nack(delivery amqp.Delivery, requeue bool) error {
+	if requeue {
+		q.requeued++
+	}
+	return nil
+}
+
+// fakeFCMClient fails sends to the tokens in failing
+type fakeFCMClient struct {
+	fcm.FCMClient
+	failing map[string]bool
+}
+
+func (c *fakeFCMClient) Send(ctx context.Context, deviceToken string, notification models.PushNotification) (string, error) {
+	if c.failing[deviceToken] {
+		return "", errors.New("fcm unavailable")
+	}
+	return "message-" + deviceToken, nil
+}
+
+// processGatewayPush runs a gateway message through the gateway consumer and
+// the message it publishes through the push worker
+func processGatewayPush(t *testing.T, s *pushService, pushQueue *fakeQueue, gatewayMessage map[string]interface{}) {
+	t.Helper()
+	ctx := context.Background()
+
+	body, err := json.Marshal(gatewayMessage)
+	if err != nil {
+		t.Fatalf("failed to marshal gateway message: %v", err)
+	}
+	if err := s.processGatewayMessage(ctx, amqp.Delivery{Body: body}); err != nil {
+		t.Fatalf("failed to process gateway message: %v", err)
+	}
+	if len(pushQueue.pushes) != 1 {
+		t.Fatalf("expected 1 queued push, got %d", len(pushQueue.pushes))
+	}
+
+	body, err = json.Marshal(pushQueue.pushes[0])
+	if err != nil {
+		t.Fatalf("failed to marshal push message: %v", err)
+	}
+	if err := s.processPushFromQueue(ctx, amqp.Delivery{Body: body}); err != nil {
+		t.Fatalf("failed to process push message: %v", err)
+	}
+}
+
+func TestGatewayPushSuppressedOnOptedOutDevicesIsTracked(t *testing.T) {
+	devices := []models.Device{
+		{UserID: "user-a", Token: "token-1", App: models.DefaultApp},
+		{UserID: "user-a", Token: "token-2", App: models.DefaultApp},
+	}
+	notificationRepo := newFakeNotificationRepo()
+	pushQueue := &fakeQueue{}
+	s := &pushService{
+		deviceRepo:       &fakeDeviceRepo{devices: devices},
+		idempotencyRepo:  &fakeIdempotencyRepo{},
+		notificationRepo: notificationRepo,
+		preferenceRepo:   &fakePreferenceRepo{optedOut: map[string]bool{"token-1": true, "token-2": true}},
+		templateService:  &fakeTemplateService{},
+		pushQueue:        pushQueue,
+	}
+
+	processGatewayPush(t, s, pushQueue, map[string]interface{}{
+		"notification_id": "gw-1",
+		"user_id":         "user-a",
+		"category":        models.CategoryMarketing,
+		"template":        map[string]interface{}{"subject": "Sale", "body": "Everything must go"},
+	})
+
+	id, ok := notificationRepo.gatewayIDs["gw-1"]
+	if !ok {
+		t.Fatal("expected the gateway notification to be recorded")
+	}
+	status := notificationRepo.statuses[id]
+	if status.Status != models.NotificationStatusSuppressed || status.SuppressedCount != 2 {
+		t.Errorf("expected status suppressed on 2 devices, got %+v", status)
+	}
+	if pushQueue.acked != 2 {
+		t.Errorf("expected both deliveries to be acked, got %d acks", pushQueue.acked)
+	}
+}
+
+// checkNotificationPerUser checks that one notification was recorded and
+// queued under its ID for each of userIDs
+func checkNotificationPerUser(t *testing.T, notificationRepo *fakeNotificationRepo, pushQueue *fakeQueue, userIDs ...string) {
+	t.Helper()
+
+	if len(notificationRepo.created) != len(userIDs) {
+		t.Fatalf("expected %d notifications, got %d", len(userIDs), len(notificationRepo.created))
+	}
+	if len(pushQueue.pushes) != len(userIDs) {
+		t.Fatalf("expected %d queued pushes, got %d", len(userIDs), len(pushQueue.pushes))
+	}
+	for i, userID := range userIDs {
+		created, queued := notificationRepo.created[i], pushQueue.pushes[i].Notification
+		if created.UserID != userID || queued.UserID != userID {
+			t.Errorf("expected notification %d for %s, got %s queued for %s", i, userID, created.UserID, queued.UserID)
+		}
+		if queued.ID == "" || queued.ID != created.ID {
+			t.Errorf("expected the queued push to carry notification ID %q, got %q", created.ID, queued.ID)
+		}
+	}
+}
+
+func TestBulkPushRecordsNotificationPerUser(t *testing.T) {
+	notificationRepo := newFakeNotificationRepo()
+	pushQueue := &fakeQueue{}
+	s := &pushService{
+		deviceRepo: &fakeDeviceRepo{devices: []models.Device{
+			{UserID: "user-a", Token: "token-1"},
+			{UserID: "user-a", Token: "token-2"},
+			{UserID: "user-b", Token: "token-3"},
+		}},
+		notificationRepo: notificationRepo,
+		pushQueue:        pushQueue,
+	}
+
+	err := s.SendBulkPush(context.Background(), models.BulkPushRequest{
+		UserIDs: []string{"user-a", "user-b", "user-without-devices"},
+		Title:   "Hello",
+		Body:    "Hi",
+	})
+	if err != nil {
+		t.Fatalf("bulk push failed: %v", err)
+	}
+
+	checkNotificationPerUser(t, notificationRepo, pushQueue, "user-a", "user-b")
+}
+
+func TestSegmentPushRecordsNotificationPerUser(t *testing.T) {
+	notificationRepo := newFakeNotificationRepo()
+	pushQueue := &fakeQueue{}
+	s := &pushService{
+		deviceRepo: &fakeDeviceRepo{devices: []models.Device{
+			{UserID: "user-a", Token: "token-1"},
+			{UserID: "user-a", Token: "token-2"},
+			{UserID: "user-b", Token: "token-3"},
+		}},
+		notificationRepo: notificationRepo,
+		pushQueue:        pushQueue,
+	}
+
+	result, err := s.SendSegmentPush(context.Background(), models.SendSegmentPushRequest{
+		Segment: "platform = ios",
+		Title:   "Hello",
+		Body:    "Hi",
+	})
+	if err != nil {
+		t.Fatalf("segment push failed: %v", err)
+	}
+	if result.DeviceCount != 3 || result.EnqueuedCount != 2 {
+		t.Errorf("expected 3 devices in 2 messages, got %+v", result)
+	}
+
+	checkNotificationPerUser(t, notificationRepo, pushQueue, "user-a", "user-b")
+}
+
+func TestFailedSendIsRequeuedWhenRetryCannotBeScheduled(t *testing.T) {
+	pushQueue := &fakeQueue{retryErr: errors.New("channel closed")}
+	s := &pushService{
+		deviceRepo:     &fakeDeviceRepo{devices: []models.Device{{UserID: "user-a", Token: "token-1"}}},
+		preferenceRepo: &fakePreferenceRepo{},
+		fcmClient:      &fakeFCMClient{failing: map[string]bool{"token-1": true}},
+		pushQueue:      pushQueue,
+	}
+
+	body, err := json.Marshal(queue.PushMessage{
+		Notification: models.PushNotification{UserID: "user-a", Title: "Hello", Body: "Hi"},
+		DeviceTokens: []string{"token-1"},
+	})
+	if err != nil {
+		t.Fatalf("failed to marshal push message: %v", err)
+	}
+	if err := s.processPushFromQueue(context.Background(), amqp.Delivery{Body: body}); err == nil {
+		t.Fatal("expected the failed retry to be reported")
+	}
+
+	if pushQueue.requeued != 1 || pushQueue.acked != 0 {
+		t.Errorf("expected the message to be requeued, got %d requeued and %d acked", pushQueue.requeued, pushQueue.acked)
+	}
+}
+
+func TestDeliveredDevicesAreMarkedSeen(t *testing.T) {
+	deviceRepo := &fakeDeviceRepo{devices: []models.Device{
+		{UserID: "user-a", Token: "token-1"},
+		{UserID: "user-a", Token: "token-2"},
+	}}
+	pushQueue := &fakeQueue{}
+	s := &pushService{
+		deviceRepo:     deviceRepo,
+		preferenceRepo: &fakePreferenceRepo{},
+		fcmClient:      &fakeFCMClient{failing: map[string]bool{"token-2": true}},
+		pushQueue:      pushQueue,
+	}
+
+	body, err := json.Marshal(queue.PushMessage{
+		Notification: models.PushNotification{UserID: "user-a", Title: "Hello", Body: "Hi"},
+		DeviceTokens: []string{"token-1", "token-2"},
+	})
+	if err != nil {
+		t.Fatalf("failed to marshal push message: %v", err)
+	}
+	if err := s.processPushFromQueue(context.Background(), amqp.Delivery{Body: body}); err != nil {
+		t.Fatalf("failed to process push message: %v", err)
+	}
+
+	if len(deviceRepo.seen) != 1 || deviceRepo.seen[0] != "token-1" {
+		t.Errorf("expected only the delivered device to be marked seen, got %v", deviceRepo.seen)
+	}
+}
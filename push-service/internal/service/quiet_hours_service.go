@@ -0,0 +1,286 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"push-service/internal/config"
+	"push-service/internal/metrics"
+	"push-service/internal/models"
+	"push-service/internal/queue"
+	"push-service/internal/repository"
+	"push-service/pkg/logger"
+	"push-service/pkg/tracing"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// QuietHoursService manages the quiet hours of users and apps, holds queued
+// notifications for devices in quiet hours and publishes them when they end
+type QuietHoursService interface {
+	Get(ctx context.Context, scope, scopeID string) (*models.QuietHours, error)
+	Set(ctx context.Context, scope, scopeID string, req models.SetQuietHoursRequest) (*models.QuietHours, error)
+	Delete(ctx context.Context, scope, scopeID string) error
+	Hold(ctx context.Context, message queue.PushMessage, registrations map[string]models.TokenRegistration) ([]string, error)
+	ReleaseDue(ctx context.Context) (int, error)
+	Start(ctx context.Context)
+}
+
+const (
+	defaultQuietHoursPollInterval = 30 * time.Second
+	defaultQuietHoursBatchSize    = 100
+)
+
+type quietHoursService struct {
+	quietHoursRepo   repository.QuietHoursRepository
+	deferredPushRepo repository.DeferredPushRepository
+	pushQueue        *queue.PushQueue
+	cfg              *config.QuietHoursConfig
+	locations        sync.Map // time zone name -> *time.Location
+}
+
+func NewQuietHoursService(quietHoursRepo repository.QuietHoursRepository, deferredPushRepo repository.DeferredPushRepository, pushQueue *queue.PushQueue, cfg *config.QuietHoursConfig) QuietHoursService {
+	return &quietHoursService{
+		quietHoursRepo:   quietHoursRepo,
+		deferredPushRepo: deferredPushRepo,
+		pushQueue:        pushQueue,
+		cfg:              cfg,
+	}
+}
+
+func (s *quietHoursService) Get(ctx context.Context, scope, scopeID string) (*models.QuietHours, error) {
+	quietHours, err := s.quietHoursRepo.Get(ctx, scope, scopeID)
+	if err != nil {
+		return nil, err
+	}
+	if quietHours == nil {
+		return nil, ErrQuietHoursNotFound.WithDetails(map[string]string{scope: scopeID})
+	}
+	return quietHours, nil
+}
+
+// Set replaces the quiet hours of a user or app
+func (s *quietHoursService) Set(ctx context.Context, scope, scopeID string, req models.SetQuietHoursRequest) (*models.QuietHours, error) {
+	// Times were validated as 15:04, which also accepts 7:00; store them as 07:00
+	start, _ := time.Parse("15:04", req.Start)
+	end, _ := time.Parse("15:04", req.End)
+	quietHours := &models.QuietHours{
+		Scope:   scope,
+		ScopeID: scopeID,
+		Start:   start.Format("15:04"),
+		End:     end.Format("15:04"),
+	}
+	if err := s.quietHoursRepo.Set(ctx, quietHours); err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx).Info("Quiet hours updated",
+		zap.String("scope", scope),
+		zap.String("scope_id", scopeID),
+		zap.String("start", quietHours.Start),
+		zap.String("end", quietHours.End),
+	)
+
+	return quietHours, nil
+}
+
+// Delete removes quiet hours. Notifications already held are still delivered
+// when the quiet hours they were held for end.
+func (s *quietHoursService) Delete(ctx context.Context, scope, scopeID string) error {
+	err := s.quietHoursRepo.Delete(ctx, scope, scopeID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrQuietHoursNotFound.WithDetails(map[string]string{scope: scopeID})
+	}
+	return err
+}
+
+// Hold stores a copy of message for the devices currently in quiet hours, to
+// be published when they end, and returns the tokens to send to now. A
+// device's quiet hours are its user's, or else its app's, in the device's
+// time zone. Notifications in bypass categories and messages already held
+// once are not held. On error, every token is returned.
+func (s *quietHoursService) Hold(ctx context.Context, message queue.PushMessage, registrations map[string]models.TokenRegistration) ([]string, error) {
+	notification := message.Notification
+	if !s.cfg.Enabled || message.Deferred || s.bypasses(notification.Category) {
+		return message.DeviceTokens, nil
+	}
+
+	var apps []string
+	for _, token := range message.DeviceTokens {
+		if app := registrations[token].App; app != "" && !slices.Contains(apps, app) {
+			apps = append(apps, app)
+		}
+	}
+
+	lookupCtx, lookupSpan := startDBSpan(ctx, "QuietHoursRepository.ForUser")
+	userQuietHours, appQuietHours, err := s.quietHoursRepo.ForUser(lookupCtx, notification.UserID, apps)
+	tracing.End(lookupSpan, err)
+	if err != nil {
+		return message.DeviceTokens, err
+	}
+	if userQuietHours == nil && len(appQuietHours) == 0 {
+		return message.DeviceTokens, nil
+	}
+
+	now := time.Now()
+	sendNow := make([]string, 0, len(message.DeviceTokens))
+	held := make(map[time.Time][]string)
+	for _, token := range message.DeviceTokens {
+		registration := registrations[token]
+		quietHours := userQuietHours
+		if quietHours == nil {
+			if appHours, ok := appQuietHours[registration.App]; ok {
+				quietHours = &appHours
+			}
+		}
+		if quietHours == nil {
+			sendNow = append(sendNow, token)
+			continue
+		}
+
+		until, quiet := quietHours.Until(now.In(s.location(registration.Timezone)))
+		if !quiet {
+			sendNow = append(sendNow, token)
+			continue
+		}
+		deliverAt := until.UTC()
+		held[deliverAt] = append(held[deliverAt], token)
+	}
+	if len(held) == 0 {
+		return sendNow, nil
+	}
+
+	// Devices whose quiet hours end at the same instant share a message
+	pushes := make([]models.DeferredPush, 0, len(held))
+	for deliverAt, tokens := range held {
+		deferred := message
+		deferred.DeviceTokens = tokens
+		deferred.Deferred = true
+		body, err := json.Marshal(deferred)
+		if err != nil {
+			return message.DeviceTokens, err
+		}
+		pushes = append(pushes, models.DeferredPush{
+			UserID:    notification.UserID,
+			Message:   body,
+			DeliverAt: deliverAt,
+		})
+	}
+
+	storeCtx, storeSpan := startDBSpan(ctx, "DeferredPushRepository.Create")
+	err = s.deferredPushRepo.Create(storeCtx, pushes)
+	tracing.End(storeSpan, err)
+	if err != nil {
+		return message.DeviceTokens, err
+	}
+
+	heldCount := len(message.DeviceTokens) - len(sendNow)
+	metrics.QuietHoursDeferrals.WithLabelValues(notification.Category).Add(float64(heldCount))
+	logger.FromContext(ctx).Info("Held push for devices in quiet hours",
+		zap.String("user_id", notification.UserID),
+		zap.String("category", notification.Category),
+		zap.Int("held_count", heldCount),
+		zap.Int("remaining_count", len(sendNow)),
+	)
+
+	return sendNow, nil
+}
+
+// bypasses reports whether notifications in category are sent during quiet
+// hours. Notifications without a category are transactional.
+func (s *quietHoursService) bypasses(category string) bool {
+	if category == "" {
+		category = models.CategoryTransactional
+	}
+	return slices.Contains(s.cfg.BypassCategories, category)
+}
+
+// location returns the time zone named by a device, or the default time zone
+// if the device has none or it is unknown
+func (s *quietHoursService) location(name string) *time.Location {
+	if name == "" {
+		name = s.cfg.DefaultTimezone
+	}
+	if cached, ok := s.locations.Load(name); ok {
+		return cached.(*time.Location)
+	}
+
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		if name == s.cfg.DefaultTimezone {
+			return time.UTC
+		}
+		return s.location("")
+	}
+	s.locations.Store(name, location)
+	return location
+}
+
+// ReleaseDue publishes one batch of held notifications whose quiet hours have
+// ended to the push queue, returning how many were published
+func (s *quietHoursService) ReleaseDue(ctx context.Context) (int, error) {
+	batchSize := s.cfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultQuietHoursBatchSize
+	}
+
+	released, err := s.deferredPushRepo.ReleaseDue(ctx, batchSize, func(push models.DeferredPush) error {
+		return s.pushQueue.EnqueueDeferred(ctx, push.Message)
+	})
+	metrics.QuietHoursReleased.Add(float64(released))
+	return released, err
+}
+
+// Start publishes due notifications on the configured interval until ctx is
+// cancelled. Full batches are followed immediately by the next one.
+func (s *quietHoursService) Start(ctx context.Context) {
+	if !s.cfg.Enabled {
+		logger.FromContext(ctx).Info("Quiet hours scheduler disabled")
+		return
+	}
+
+	interval := s.cfg.PollInterval
+	if interval <= 0 {
+		interval = defaultQuietHoursPollInterval
+	}
+
+	logger.FromContext(ctx).Info("Starting quiet hours scheduler", zap.Duration("interval", interval))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.FromContext(ctx).Info("Quiet hours scheduler shutting down...")
+			return
+		case <-ticker.C:
+			s.releaseAllDue(ctx)
+		}
+	}
+}
+
+func (s *quietHoursService) releaseAllDue(ctx context.Context) {
+	batchSize := s.cfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultQuietHoursBatchSize
+	}
+
+	for ctx.Err() == nil {
+		released, err := s.ReleaseDue(ctx)
+		if err != nil {
+			logger.FromContext(ctx).Error("Failed to release held notifications", zap.Error(err))
+			return
+		}
+		if released > 0 {
+			logger.FromContext(ctx).Info("Released held notifications", zap.Int("count", released))
+		}
+		if released < batchSize {
+			return
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"push-service/internal/config"
+	"push-service/internal/models"
+	"push-service/internal/repository"
+)
+
+type fakeQuietHoursRepo struct {
+	repository.QuietHoursRepository
+	user *models.QuietHours
+}
+
+func (r *fakeQuietHoursRepo) ForUser(ctx context.Context, userID string, apps []string) (*models.QuietHours, map[string]models.QuietHours, error) {
+	return r.user, nil, nil
+}
+
+type fakeDeferredPushRepo struct {
+	repository.DeferredPushRepository
+	pushes []models.DeferredPush
+}
+
+func (r *fakeDeferredPushRepo) Create(ctx context.Context, pushes []models.DeferredPush) error {
+	r.pushes = append(r.pushes, pushes...)
+	return nil
+}
+
+func TestQuietHoursLocation(t *testing.T) {
+	tests := []struct {
+		name            string
+		defaultTimezone string
+		timezone        string
+		want            string
+	}{
+		{name: "device time zone", defaultTimezone: "Europe/Berlin", timezone: "Asia/Tokyo", want: "Asia/Tokyo"},
+		{name: "no device time zone", defaultTimezone: "Europe/Berlin", want: "Europe/Berlin"},
+		{name: "invalid device time zone", defaultTimezone: "Europe/Berlin", timezone: "Mars/Olympus_Mons", want: "Europe/Berlin"},
+		{name: "invalid default time zone", defaultTimezone: "Mars/Olympus_Mons", want: "UTC"},
+		{name: "both invalid", defaultTimezone: "Mars/Olympus_Mons", timezone: "Venus/Maxwell", want: "UTC"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &quietHoursService{cfg: &config.QuietHoursConfig{DefaultTimezone: tt.defaultTimezone}}
+			if got := s.location(tt.timezone).String(); got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGatewayPushHeldForQuietHoursIsTracked(t *testing.T) {
+	// Quiet hours around now, so the push is held whatever the time of day
+	now := time.Now().UTC()
+	deferredPushRepo := &fakeDeferredPushRepo{}
+	quietHours := &quietHoursService{
+		quietHoursRepo: &fakeQuietHoursRepo{user: &models.QuietHours{
+			Scope:   models.QuietHoursScopeUser,
+			ScopeID: "user-a",
+			Start:   now.Add(-time.Hour).Format("15:04"),
+			End:     now.Add(time.Hour).Format("15:04"),
+		}},
+		deferredPushRepo: deferredPushRepo,
+		cfg:              &config.QuietHoursConfig{Enabled: true, DefaultTimezone: "UTC"},
+	}
+
+	devices := []models.Device{{UserID: "user-a", Token: "token-1", App: models.DefaultApp}}
+	notificationRepo := newFakeNotificationRepo()
+	pushQueue := &fakeQueue{}
+	s := &pushService{
+		deviceRepo:       &fakeDeviceRepo{devices: devices},
+		idempotencyRepo:  &fakeIdempotencyRepo{},
+		notificationRepo: notificationRepo,
+		templateService:  &fakeTemplateService{},
+		quietHours:       quietHours,
+		pushQueue:        pushQueue,
+	}
+
+	processGatewayPush(t, s, pushQueue, map[string]interface{}{
+		"notification_id": "gw-2",
+		"user_id":         "user-a",
+		"category":        models.CategoryMarketing,
+		"template":        map[string]interface{}{"subject": "Sale", "body": "Everything must go"},
+	})
+
+	id, ok := notificationRepo.gatewayIDs["gw-2"]
+	if !ok {
+		t.Fatal("expected the gateway notification to be recorded")
+	}
+	if status := notificationRepo.statuses[id]; status.Status != models.NotificationStatusDeferred {
+		t.Errorf("expected status deferred, got %+v", status)
+	}
+	if len(deferredPushRepo.pushes) != 1 {
+		t.Fatalf("expected 1 held push, got %d", len(deferredPushRepo.pushes))
+	}
+	if deliverAt := deferredPushRepo.pushes[0].DeliverAt; !deliverAt.After(now) {
+		t.Errorf("expected the push to be held until after %s, got %s", now, deliverAt)
+	}
+}
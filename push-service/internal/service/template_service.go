@@ -0,0 +1,466 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"push-service/internal/config"
+	"push-service/internal/models"
+	"push-service/internal/platform/fcm"
+	"push-service/internal/repository"
+	"push-service/internal/templating"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// previewToken stands in for the device token in previewed FCM messages
+const previewToken = "DEVICE_TOKEN"
+
+// previewPlatforms are the platforms a preview shows an FCM message for
+var previewPlatforms = []string{"android", "ios", "web"}
+
+// TemplateService manages stored push templates and renders push content for
+// a user from a stored template or inline text
+type TemplateService interface {
+	Render(ctx context.Context, req models.RenderRequest) (*models.RenderedContent, error)
+	CreateTemplate(ctx context.Context, req models.CreateTemplateRequest) (*models.PushTemplate, error)
+	GetTemplate(ctx context.Context, key string, version int) (*models.PushTemplate, error)
+	ListTemplates(ctx context.Context) ([]models.PushTemplate, error)
+	ListVersions(ctx context.Context, key string) ([]models.TemplateVersion, error)
+	UpdateTemplate(ctx context.Context, key string, req models.UpdateTemplateRequest) (*models.PushTemplate, error)
+	DeleteTemplate(ctx context.Context, key string) error
+	PreviewTemplate(ctx context.Context, key string, req models.PreviewTemplateRequest) (*models.TemplatePreview, error)
+}
+
+type templateService struct {
+	templateRepo       repository.TemplateRepository
+	userAttributesRepo repository.UserAttributesRepository
+	options            templating.Options
+	cache              *templateCache
+}
+
+func NewTemplateService(templateRepo repository.TemplateRepository, userAttributesRepo repository.UserAttributesRepository, cfg *config.TemplatesConfig) TemplateService {
+	return &templateService{
+		templateRepo:       templateRepo,
+		userAttributesRepo: userAttributesRepo,
+		options: templating.Options{
+			Missing: cfg.MissingVariables,
+			Escape:  cfg.Escape,
+		},
+		cache: newTemplateCache(cfg.CacheTTL),
+	}
+}
+
+// Render fills the placeholders in the default content and each locale
+// variant. User attributes are only loaded when there is something to render.
+func (s *templateService) Render(ctx context.Context, req models.RenderRequest) (*models.RenderedContent, error) {
+	content, locales := models.LocalizedContent{Title: req.Title, Body: req.Body}, req.Locales
+	if req.TemplateID != "" {
+		template, err := s.sendTemplate(ctx, req.TemplateID, req.Version)
+		if err != nil {
+			return nil, err
+		}
+		if template == nil {
+			return nil, ErrTemplateNotFound.WithDetails(map[string]string{"template_id": req.TemplateID})
+		}
+		content = template.Locales[template.DefaultLocale]
+		locales = make(map[string]models.LocalizedContent, len(template.Locales)-1)
+		for locale, variant := range template.Locales {
+			if locale != template.DefaultLocale {
+				locales[locale] = variant
+			}
+		}
+	}
+
+	var vars templating.Variables
+	needsVariables := hasPlaceholders(content)
+	for _, variant := range locales {
+		needsVariables = needsVariables || hasPlaceholders(variant)
+	}
+	if needsVariables {
+		var err error
+		if vars, err = s.variables(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+
+	rendered := &models.RenderedContent{}
+	var err error
+	if rendered.LocalizedContent, err = s.renderContent(content, vars, ""); err != nil {
+		return nil, err
+	}
+	if len(locales) > 0 {
+		rendered.Locales = make(map[string]models.LocalizedContent, len(locales))
+		for locale, variant := range locales {
+			if rendered.Locales[locale], err = s.renderContent(variant, vars, "locales."+locale+"."); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return rendered, nil
+}
+
+// sendTemplate returns a template for sending. The latest version is served
+// from the cache when possible; pinned versions never change, but are rare
+// enough to read from the database.
+func (s *templateService) sendTemplate(ctx context.Context, key string, version int) (*models.PushTemplate, error) {
+	if version != 0 {
+		return s.templateRepo.Get(ctx, key, version)
+	}
+	if template, ok := s.cache.get(key); ok {
+		return template, nil
+	}
+
+	template, err := s.templateRepo.Get(ctx, key, 0)
+	if err != nil {
+		return nil, err
+	}
+	if template != nil {
+		s.cache.put(key, template)
+	}
+	return template, nil
+}
+
+// renderContent fills the placeholders in every field of content. Errors name
+// the field with prefix before it.
+func (s *templateService) renderContent(content models.LocalizedContent, vars templating.Variables, prefix string) (models.LocalizedContent, error) {
+	if !hasPlaceholders(content) {
+		return content, nil
+	}
+
+	r := &renderer{vars: vars, options: s.options}
+	rendered := models.LocalizedContent{
+		Title: r.text(prefix+"title", content.Title),
+		Body:  r.text(prefix+"body", content.Body),
+		Image: r.optional(prefix+"image", content.Image),
+		Link:  r.optional(prefix+"link", content.Link),
+	}
+	if len(content.PlatformOverrides) > 0 {
+		rendered.PlatformOverrides = make(map[string]models.ContentOverride, len(content.PlatformOverrides))
+		for platform, override := range content.PlatformOverrides {
+			field := prefix + "platform_overrides." + platform + "."
+			rendered.PlatformOverrides[platform] = models.ContentOverride{
+				Title: r.optional(field+"title", override.Title),
+				Body:  r.optional(field+"body", override.Body),
+				Image: r.optional(field+"image", override.Image),
+				Link:  r.optional(field+"link", override.Link),
+			}
+		}
+	}
+	if r.err != nil {
+		return models.LocalizedContent{}, r.err
+	}
+
+	return rendered, nil
+}
+
+// variables collects the values placeholders may use. Each source overrides
+// the ones before it for unqualified names: user attributes, then data, then
+// the recipient name, then explicit variables. Attributes and data are also
+// available as user.<name> and data.<name>.
+func (s *templateService) variables(ctx context.Context, req models.RenderRequest) (templating.Variables, error) {
+	vars := templating.Variables{}
+
+	if req.UserID != "" {
+		attributes, err := s.userAttributesRepo.Get(ctx, req.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if attributes != nil {
+			for name, value := range attributes.Attributes {
+				vars.Set(name, value)
+				vars.Set("user."+name, value)
+			}
+		}
+	}
+
+	vars.SetAll("", req.Data)
+	vars.SetAll("data", req.Data)
+	if req.Name != "" {
+		vars.Set("name", req.Name)
+	}
+	vars.SetAll("", req.Variables)
+
+	return vars, nil
+}
+
+// renderer renders the fields of one piece of content, keeping the first error
+type renderer struct {
+	vars    templating.Variables
+	options templating.Options
+	err     error
+}
+
+func (r *renderer) text(field, text string) string {
+	if r.err != nil {
+		return ""
+	}
+	rendered, err := templating.Render(text, r.vars, r.options)
+	if err != nil {
+		r.err = renderError(field, err)
+	}
+	return rendered
+}
+
+func (r *renderer) optional(field string, text *string) *string {
+	if text == nil {
+		return nil
+	}
+	rendered := r.text(field, *text)
+	return &rendered
+}
+
+func renderError(field string, err error) error {
+	return ErrTemplateRender.WithDetails(map[string]string{
+		"field": field,
+		"error": err.Error(),
+	}).Wrap(err)
+}
+
+// hasPlaceholders reports whether any field of content has something to render
+func hasPlaceholders(content models.LocalizedContent) bool {
+	for _, text := range contentFields(content) {
+		if templating.HasPlaceholders(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// contentFields returns each text field set in content by name, including
+// platform overrides
+func contentFields(content models.LocalizedContent) map[string]string {
+	fields := map[string]string{"title": content.Title, "body": content.Body}
+	if content.Image != nil {
+		fields["image"] = *content.Image
+	}
+	if content.Link != nil {
+		fields["link"] = *content.Link
+	}
+	for platform, override := range content.PlatformOverrides {
+		prefix := "platform_overrides." + platform + "."
+		for name, text := range map[string]*string{"title": override.Title, "body": override.Body, "image": override.Image, "link": override.Link} {
+			if text != nil {
+				fields[prefix+name] = *text
+			}
+		}
+	}
+	return fields
+}
+
+// validateTemplate checks that the default locale has content and that every
+// placeholder is well formed
+func validateTemplate(req models.UpdateTemplateRequest) error {
+	if _, ok := req.Locales[req.DefaultLocale]; !ok {
+		return ErrInvalidTemplate.WithDetails(map[string]string{
+			"field": "default_locale",
+			"error": "no content for the default locale " + req.DefaultLocale,
+		})
+	}
+	for locale, content := range req.Locales {
+		for field, text := range contentFields(content) {
+			if err := templating.Validate(text); err != nil {
+				return ErrInvalidTemplate.WithDetails(map[string]string{
+					"field": "locales." + locale + "." + field,
+					"error": err.Error(),
+				})
+			}
+		}
+	}
+	return nil
+}
+
+func templateNotFound(key string, version int) error {
+	details := map[string]any{"key": key}
+	if version != 0 {
+		details["version"] = version
+	}
+	return ErrTemplateNotFound.WithDetails(details)
+}
+
+// CreateTemplate stores a new template as version 1
+func (s *templateService) CreateTemplate(ctx context.Context, req models.CreateTemplateRequest) (*models.PushTemplate, error) {
+	if err := validateTemplate(req.UpdateTemplateRequest); err != nil {
+		return nil, err
+	}
+
+	template := &models.PushTemplate{
+		Key:           req.Key,
+		Description:   req.Description,
+		DefaultLocale: req.DefaultLocale,
+		Locales:       req.Locales,
+	}
+	if err := s.templateRepo.Create(ctx, template); err != nil {
+		if errors.Is(err, repository.ErrTemplateExists) {
+			return nil, ErrTemplateExists.WithDetails(map[string]string{"key": req.Key})
+		}
+		return nil, err
+	}
+
+	return template, nil
+}
+
+// GetTemplate returns a version of the template, or the latest when version is zero
+func (s *templateService) GetTemplate(ctx context.Context, key string, version int) (*models.PushTemplate, error) {
+	template, err := s.templateRepo.Get(ctx, key, version)
+	if err != nil {
+		return nil, err
+	}
+	if template == nil {
+		return nil, templateNotFound(key, version)
+	}
+	return template, nil
+}
+
+// ListTemplates returns the latest version of every template
+func (s *templateService) ListTemplates(ctx context.Context) ([]models.PushTemplate, error) {
+	return s.templateRepo.List(ctx)
+}
+
+// ListVersions lists the template's versions, newest first
+func (s *templateService) ListVersions(ctx context.Context, key string) ([]models.TemplateVersion, error) {
+	versions, err := s.templateRepo.Versions(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, templateNotFound(key, 0)
+	}
+	return versions, nil
+}
+
+// UpdateTemplate replaces the template's content with a new version
+func (s *templateService) UpdateTemplate(ctx context.Context, key string, req models.UpdateTemplateRequest) (*models.PushTemplate, error) {
+	if err := validateTemplate(req); err != nil {
+		return nil, err
+	}
+
+	template := &models.PushTemplate{
+		Key:           key,
+		Description:   req.Description,
+		DefaultLocale: req.DefaultLocale,
+		Locales:       req.Locales,
+	}
+	if err := s.templateRepo.AddVersion(ctx, template); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, templateNotFound(key, 0)
+		}
+		return nil, err
+	}
+	s.cache.remove(key)
+
+	return template, nil
+}
+
+// DeleteTemplate removes the template and all its versions
+func (s *templateService) DeleteTemplate(ctx context.Context, key string) error {
+	if err := s.templateRepo.Delete(ctx, key); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return templateNotFound(key, 0)
+		}
+		return err
+	}
+	s.cache.remove(key)
+	return nil
+}
+
+// PreviewTemplate renders the template with the request's variables and
+// builds the FCM message each platform would be sent
+func (s *templateService) PreviewTemplate(ctx context.Context, key string, req models.PreviewTemplateRequest) (*models.TemplatePreview, error) {
+	template, err := s.GetTemplate(ctx, key, req.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	locale, content := template.Variant(req.Locale)
+	var vars templating.Variables
+	if hasPlaceholders(content) {
+		vars, err = s.variables(ctx, models.RenderRequest{
+			UserID:    req.UserID,
+			Name:      req.Name,
+			Data:      req.Data,
+			Variables: req.Variables,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	rendered, err := s.renderContent(content, vars, "")
+	if err != nil {
+		return nil, err
+	}
+
+	notification := models.PushNotification{
+		Title:             rendered.Title,
+		Body:              rendered.Body,
+		Image:             rendered.Image,
+		Link:              rendered.Link,
+		Data:              req.Data,
+		PlatformOverrides: rendered.PlatformOverrides,
+	}
+	payloads := make(map[string]any, len(previewPlatforms))
+	for _, platform := range previewPlatforms {
+		payloads[platform] = fcm.BuildMessage(previewToken, notification.ForPlatform(platform))
+	}
+
+	return &models.TemplatePreview{
+		Key:      template.Key,
+		Version:  template.Version,
+		Locale:   locale,
+		Content:  rendered,
+		Payloads: payloads,
+	}, nil
+}
+
+// templateCache keeps the latest version of recently sent templates, so sends
+// do not read the database each time. Updates and deletes through this
+// instance take effect at once; other instances see them when entries expire.
+type templateCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]cachedTemplate
+}
+
+type cachedTemplate struct {
+	template  *models.PushTemplate
+	expiresAt time.Time
+}
+
+// newTemplateCache returns a cache holding entries for ttl, or one that holds
+// nothing when ttl is zero
+func newTemplateCache(ttl time.Duration) *templateCache {
+	return &templateCache{ttl: ttl, entries: map[string]cachedTemplate{}}
+}
+
+func (c *templateCache) get(key string) (*models.PushTemplate, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.template, true
+}
+
+func (c *templateCache) put(key string, template *models.PushTemplate) {
+	if c.ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = cachedTemplate{template: template, expiresAt: time.Now().Add(c.ttl)}
+}
+
+func (c *templateCache) remove(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"push-service/internal/models"
+	"push-service/internal/queue"
+	"push-service/internal/repository"
+	"push-service/pkg/logger"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// UserService handles data subject requests (GDPR export and erasure) and
+// the user's segmentation attributes and notification preferences
+type UserService interface {
+	ExportUserData(ctx context.Context, userID string) (*models.UserDataExport, error)
+	EraseUserData(ctx context.Context, userID string) (*models.UserErasureResult, error)
+	GetUserAttributes(ctx context.Context, userID string) (*models.UserAttributes, error)
+	SetUserAttributes(ctx context.Context, userID string, req models.SetUserAttributesRequest) (*models.UserAttributes, error)
+	GetPreferences(ctx context.Context, userID string) (*models.UserPreferences, error)
+	SetPreferences(ctx context.Context, userID string, req models.SetPreferencesRequest) (*models.UserPreferences, error)
+	DeletePreference(ctx context.Context, userID, category, deviceToken string) error
+}
+
+type userService struct {
+	userDataRepo       repository.UserDataRepository
+	userAttributesRepo repository.UserAttributesRepository
+	preferenceRepo     repository.PreferenceRepository
+	pushQueue          *queue.PushQueue
+}
+
+func NewUserService(userDataRepo repository.UserDataRepository, userAttributesRepo repository.UserAttributesRepository, preferenceRepo repository.PreferenceRepository, pushQueue *queue.PushQueue) UserService {
+	return &userService{
+		userDataRepo:       userDataRepo,
+		userAttributesRepo: userAttributesRepo,
+		preferenceRepo:     preferenceRepo,
+		pushQueue:          pushQueue,
+	}
+}
+
+func (s *userService) ExportUserData(ctx context.Context, userID string) (*models.UserDataExport, error) {
+	export, err := s.userDataRepo.Export(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	export.ExportedAt = time.Now().UTC()
+
+	logger.FromContext(ctx).Info("User data exported",
+		zap.String("user_id", userID),
+		zap.Int("device_count", len(export.Devices)),
+		zap.Int("notification_count", len(export.Notifications)),
+	)
+
+	return export, nil
+}
+
+// EraseUserData hard-deletes the user's data and publishes an audit event.
+// Erasure is idempotent, so a failed audit publish is reported in the result
+// rather than failing a request whose deletes have already committed.
+func (s *userService) EraseUserData(ctx context.Context, userID string) (*models.UserErasureResult, error) {
+	result, err := s.userDataRepo.Erase(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	result.ErasedAt = time.Now().UTC()
+
+	event := models.AuditEvent{
+		Type:   models.AuditEventUserErased,
+		UserID: userID,
+		Details: map[string]any{
+			"devices_deleted":          result.DevicesDeleted,
+			"notifications_deleted":    result.NotificationsDeleted,
+			"attributes_deleted":       result.AttributesDeleted,
+			"preferences_deleted":      result.PreferencesDeleted,
+			"quiet_hours_deleted":      result.QuietHoursDeleted,
+			"deferred_pushes_deleted":  result.DeferredPushesDeleted,
+			"idempotency_keys_deleted": result.IdempotencyKeysDeleted,
+			"gateway_messages_deleted": result.GatewayMessagesDeleted,
+		},
+		OccurredAt: result.ErasedAt,
+	}
+	if err := s.pushQueue.PublishAudit(ctx, event); err != nil {
+		logger.FromContext(ctx).Error("Failed to publish erasure audit event",
+			zap.String("user_id", userID),
+			zap.Error(err),
+		)
+	} else {
+		result.AuditEventPublished = true
+	}
+
+	logger.FromContext(ctx).Info("User data erased",
+		zap.String("user_id", userID),
+		zap.Int64("devices_deleted", result.DevicesDeleted),
+		zap.Int64("notifications_deleted", result.NotificationsDeleted),
+	)
+
+	return result, nil
+}
+
+// GetUserAttributes returns the user's segmentation attributes. Users without
+// stored attributes get an empty set rather than an error.
+func (s *userService) GetUserAttributes(ctx context.Context, userID string) (*models.UserAttributes, error) {
+	attributes, err := s.userAttributesRepo.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if attributes == nil {
+		attributes = &models.UserAttributes{
+			UserID:     userID,
+			Tags:       []string{},
+			Attributes: map[string]string{},
+		}
+	}
+	return attributes, nil
+}
+
+// SetUserAttributes replaces the user's tags and attributes
+func (s *userService) SetUserAttributes(ctx context.Context, userID string, req models.SetUserAttributesRequest) (*models.UserAttributes, error) {
+	attributes := &models.UserAttributes{
+		UserID:     userID,
+		Tags:       req.Tags,
+		Attributes: req.Attributes,
+	}
+
+	if err := s.userAttributesRepo.Set(ctx, attributes); err != nil {
+		return nil, err
+	}
+	if attributes.Attributes == nil {
+		attributes.Attributes = map[string]string{}
+	}
+
+	logger.FromContext(ctx).Info("User attributes updated",
+		zap.String("user_id", userID),
+		zap.Int("tag_count", len(attributes.Tags)),
+		zap.Int("attribute_count", len(attributes.Attributes)),
+	)
+
+	return attributes, nil
+}
+
+// GetPreferences returns the preferences the user has set. Categories without
+// one are enabled.
+func (s *userService) GetPreferences(ctx context.Context, userID string) (*models.UserPreferences, error) {
+	preferences, err := s.preferenceRepo.List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.UserPreferences{UserID: userID, Preferences: preferences}, nil
+}
+
+// SetPreferences stores the given preferences, leaving the user's others as
+// they are, and returns all of them
+func (s *userService) SetPreferences(ctx context.Context, userID string, req models.SetPreferencesRequest) (*models.UserPreferences, error) {
+	preferences := make([]models.NotificationPreference, len(req.Preferences))
+	for i, update := range req.Preferences {
+		preferences[i] = models.NotificationPreference{
+			Category:    update.Category,
+			DeviceToken: update.DeviceToken,
+			Enabled:     *update.Enabled,
+		}
+	}
+
+	if err := s.preferenceRepo.Set(ctx, userID, preferences); err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx).Info("Notification preferences updated",
+		zap.String("user_id", userID),
+		zap.Int("preference_count", len(preferences)),
+	)
+
+	return s.GetPreferences(ctx, userID)
+}
+
+// DeletePreference removes a preference; an empty deviceToken names the
+// user-wide one
+func (s *userService) DeletePreference(ctx context.Context, userID, category, deviceToken string) error {
+	err := s.preferenceRepo.Delete(ctx, userID, category, deviceToken)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrPreferenceNotFound.WithDetails(map[string]string{"category": category})
+	}
+	return err
+}
@@ -0,0 +1,197 @@
+// Package templating renders notification text containing {{variable}}
+// placeholders
+package templating
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Missing-variable policies
+const (
+	// MissingError fails rendering when a placeholder has no value or default
+	MissingError = "error"
+	// MissingEmpty renders missing variables as empty text
+	MissingEmpty = "empty"
+	// MissingKeep leaves the placeholder as written
+	MissingKeep = "keep"
+)
+
+// Escaping modes, applied to substituted values but not the template text
+const (
+	EscapeNone = "none"
+	EscapeHTML = "html"
+)
+
+// Options controls how missing variables and substituted values are handled
+type Options struct {
+	Missing string
+	Escape  string
+}
+
+// Validate reports an unknown policy or escaping mode
+func (o Options) Validate() error {
+	switch o.Missing {
+	case MissingError, MissingEmpty, MissingKeep:
+	default:
+		return fmt.Errorf("unknown missing variable policy %q", o.Missing)
+	}
+	switch o.Escape {
+	case EscapeNone, EscapeHTML:
+	default:
+		return fmt.Errorf("unknown escape mode %q", o.Escape)
+	}
+	return nil
+}
+
+// ErrSyntax is returned for malformed placeholders
+var ErrSyntax = errors.New("template syntax error")
+
+// MissingVariablesError lists the placeholders that had no value
+type MissingVariablesError struct {
+	Names []string
+}
+
+func (e *MissingVariablesError) Error() string {
+	return "missing template variables: " + strings.Join(e.Names, ", ")
+}
+
+// Variables are the values placeholders are replaced with
+type Variables map[string]string
+
+// Set adds value under name, formatting non-string values as JSON would
+func (v Variables) Set(name string, value any) {
+	switch value := value.(type) {
+	case nil:
+		return
+	case string:
+		v[name] = value
+	case float64:
+		v[name] = strconv.FormatFloat(value, 'f', -1, 64)
+	default:
+		v[name] = fmt.Sprint(value)
+	}
+}
+
+// SetAll adds each of values, with prefix and a dot before its name when
+// prefix is not empty
+func (v Variables) SetAll(prefix string, values map[string]any) {
+	for name, value := range values {
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+		v.Set(name, value)
+	}
+}
+
+// HasPlaceholders reports whether text contains anything to render
+func HasPlaceholders(text string) bool {
+	return strings.Contains(text, "{{")
+}
+
+// Validate reports a malformed placeholder in text
+func Validate(text string) error {
+	_, err := Render(text, nil, Options{Missing: MissingKeep, Escape: EscapeNone})
+	return err
+}
+
+// Render replaces each {{name}} in text with its variable. A placeholder may
+// give a default for a missing variable as {{name | default: "friend"}}, and
+// \{{ writes a literal {{.
+func Render(text string, vars Variables, opts Options) (string, error) {
+	var out strings.Builder
+	var missing []string
+
+	for {
+		start := strings.Index(text, "{{")
+		if start < 0 {
+			out.WriteString(text)
+			break
+		}
+		if start > 0 && text[start-1] == '\\' {
+			out.WriteString(text[:start-1])
+			out.WriteString("{{")
+			text = text[start+2:]
+			continue
+		}
+		end := strings.Index(text[start:], "}}")
+		if end < 0 {
+			return "", fmt.Errorf("%w: unclosed {{ at %q", ErrSyntax, truncate(text[start:]))
+		}
+		end += start
+
+		out.WriteString(text[:start])
+		name, fallback, hasDefault, err := parsePlaceholder(text[start+2 : end])
+		if err != nil {
+			return "", err
+		}
+
+		if value, ok := vars[name]; ok {
+			if opts.Escape == EscapeHTML {
+				value = html.EscapeString(value)
+			}
+			out.WriteString(value)
+		} else if hasDefault {
+			out.WriteString(fallback)
+		} else {
+			switch opts.Missing {
+			case MissingEmpty:
+			case MissingKeep:
+				out.WriteString(text[start : end+2])
+			default:
+				missing = append(missing, name)
+			}
+		}
+		text = text[end+2:]
+	}
+
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return "", &MissingVariablesError{Names: dedupe(missing)}
+	}
+	return out.String(), nil
+}
+
+// parsePlaceholder splits "name | default: \"text\"" into its parts
+func parsePlaceholder(inner string) (name, fallback string, hasDefault bool, err error) {
+	name, filter, hasFilter := strings.Cut(inner, "|")
+	name = strings.TrimSpace(name)
+	if name == "" || strings.ContainsAny(name, " \t\n{}") {
+		return "", "", false, fmt.Errorf("%w: invalid variable name %q", ErrSyntax, inner)
+	}
+	if !hasFilter {
+		return name, "", false, nil
+	}
+
+	filter = strings.TrimSpace(filter)
+	arg, ok := strings.CutPrefix(filter, "default:")
+	if !ok {
+		return "", "", false, fmt.Errorf("%w: unknown filter %q", ErrSyntax, filter)
+	}
+	fallback, err = strconv.Unquote(strings.TrimSpace(arg))
+	if err != nil {
+		return "", "", false, fmt.Errorf("%w: default must be a quoted string in %q", ErrSyntax, inner)
+	}
+	return name, fallback, true, nil
+}
+
+func dedupe(sorted []string) []string {
+	out := sorted[:0]
+	for i, name := range sorted {
+		if i == 0 || name != sorted[i-1] {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+func truncate(text string) string {
+	if len(text) > 20 {
+		return text[:20] + "..."
+	}
+	return text
+}
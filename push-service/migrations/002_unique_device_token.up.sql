@@ -0,0 +1,10 @@
+ALTER TABLE devices ADD COLUMN app VARCHAR(100) NOT NULL DEFAULT 'default';
+
+-- Collapse duplicate registrations, keeping the most recently updated row per (app, token)
+DELETE FROM devices d
+USING devices newer
+WHERE d.app = newer.app
+  AND d.token = newer.token
+  AND (d.updated_at, d.id) < (newer.updated_at, newer.id);
+
+ALTER TABLE devices ADD CONSTRAINT uq_devices_app_token UNIQUE (app, token);
@@ -0,0 +1,6 @@
+ALTER TABLE devices ADD COLUMN last_seen_at TIMESTAMP WITH TIME ZONE DEFAULT NOW();
+
+UPDATE devices SET last_seen_at = updated_at;
+
+CREATE INDEX idx_devices_last_seen_at ON devices(last_seen_at) WHERE is_active = true;
+CREATE INDEX idx_devices_inactive_updated_at ON devices(updated_at) WHERE is_active = false;
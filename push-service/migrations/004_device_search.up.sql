@@ -0,0 +1,6 @@
+ALTER TABLE devices ADD COLUMN metadata JSONB NOT NULL DEFAULT '{}';
+
+CREATE INDEX idx_devices_metadata ON devices USING GIN (metadata jsonb_path_ops);
+CREATE INDEX idx_devices_created_at_id ON devices(created_at, id);
+CREATE INDEX idx_devices_last_seen_at_id ON devices(last_seen_at, id);
+CREATE INDEX idx_devices_platform_app ON devices(platform, app);
@@ -0,0 +1,13 @@
+ALTER TABLE devices ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX idx_devices_tags ON devices USING GIN (tags);
+
+CREATE TABLE user_attributes (
+    user_id VARCHAR(255) PRIMARY KEY,
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    attributes JSONB NOT NULL DEFAULT '{}',
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_user_attributes_tags ON user_attributes USING GIN (tags);
+CREATE INDEX idx_user_attributes_attributes ON user_attributes USING GIN (attributes jsonb_path_ops);
@@ -0,0 +1,14 @@
+CREATE TABLE api_keys (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    prefix VARCHAR(32) NOT NULL,
+    key_hash CHAR(64) NOT NULL UNIQUE,
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    rotated_from UUID REFERENCES api_keys(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_api_keys_active ON api_keys(created_at) WHERE revoked_at IS NULL;
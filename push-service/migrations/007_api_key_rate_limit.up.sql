@@ -0,0 +1,2 @@
+-- Per-key request limit, overriding the configured default when set
+ALTER TABLE api_keys ADD COLUMN rate_limit INTEGER CHECK (rate_limit > 0);
@@ -0,0 +1,11 @@
+-- Track the delivery progress of each queued or synchronous send. A row
+-- covers one notification to one user, across all of that user's devices.
+ALTER TABLE push_notifications DROP CONSTRAINT IF EXISTS push_notifications_status_check;
+ALTER TABLE push_notifications ADD CONSTRAINT push_notifications_status_check
+    CHECK (status IN ('queued', 'sending', 'retrying', 'sent', 'failed', 'delivered'));
+
+ALTER TABLE push_notifications
+    ADD COLUMN device_count INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN success_count INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN failure_count INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW();
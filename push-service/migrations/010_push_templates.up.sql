@@ -0,0 +1,9 @@
+-- Push content rendered by push-service. title and body may contain
+-- {{variable}} placeholders filled from the send request and user attributes.
+CREATE TABLE push_templates (
+    id VARCHAR(255) PRIMARY KEY,
+    title TEXT NOT NULL,
+    body TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
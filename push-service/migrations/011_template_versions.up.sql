@@ -0,0 +1,36 @@
+-- Versioned, localized push templates. Each update adds a version; a version
+-- has content for one or more locales, with optional per-platform overrides.
+ALTER TABLE push_templates RENAME COLUMN id TO key;
+ALTER TABLE push_templates
+    ADD COLUMN description TEXT NOT NULL DEFAULT '',
+    ADD COLUMN latest_version INTEGER NOT NULL DEFAULT 1;
+
+CREATE TABLE push_template_versions (
+    template_key VARCHAR(255) NOT NULL REFERENCES push_templates(key) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    default_locale VARCHAR(35) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (template_key, version)
+);
+
+CREATE TABLE push_template_contents (
+    template_key VARCHAR(255) NOT NULL,
+    version INTEGER NOT NULL,
+    locale VARCHAR(35) NOT NULL,
+    title TEXT NOT NULL,
+    body TEXT NOT NULL,
+    image TEXT,
+    link TEXT,
+    platform_overrides JSONB NOT NULL DEFAULT '{}',
+    PRIMARY KEY (template_key, version, locale),
+    FOREIGN KEY (template_key, version) REFERENCES push_template_versions(template_key, version) ON DELETE CASCADE
+);
+
+-- Existing templates become version 1 in English
+INSERT INTO push_template_versions (template_key, version, default_locale, created_at)
+SELECT key, 1, 'en', updated_at FROM push_templates;
+
+INSERT INTO push_template_contents (template_key, version, locale, title, body)
+SELECT key, 1, 'en', title, body FROM push_templates;
+
+ALTER TABLE push_templates DROP COLUMN title, DROP COLUMN body;
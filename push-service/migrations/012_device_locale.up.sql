@@ -0,0 +1,3 @@
+-- The device's language as a BCP 47 tag such as pt-BR, used to pick a
+-- notification's locale variant. Empty when the app has not reported one.
+ALTER TABLE devices ADD COLUMN locale VARCHAR(35) NOT NULL DEFAULT '';
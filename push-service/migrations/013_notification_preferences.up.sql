@@ -0,0 +1,20 @@
+-- Opt-ins per user and notification category. A row with an empty
+-- device_token applies to all of the user's devices; a row for a device token
+-- takes precedence over it on that device.
+CREATE TABLE notification_preferences (
+    user_id VARCHAR(255) NOT NULL,
+    category VARCHAR(50) NOT NULL,
+    device_token TEXT NOT NULL DEFAULT '',
+    enabled BOOLEAN NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (user_id, category, device_token)
+);
+
+-- Notifications are suppressed, not sent, to devices that opted out of their category
+ALTER TABLE push_notifications DROP CONSTRAINT IF EXISTS push_notifications_status_check;
+ALTER TABLE push_notifications ADD CONSTRAINT push_notifications_status_check
+    CHECK (status IN ('queued', 'sending', 'retrying', 'sent', 'failed', 'suppressed', 'delivered'));
+
+ALTER TABLE push_notifications
+    ADD COLUMN category VARCHAR(50) NOT NULL DEFAULT 'transactional',
+    ADD COLUMN suppressed_count INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,31 @@
+-- IANA time zone reported by the device, used to apply quiet hours in its local time
+ALTER TABLE devices ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT '';
+
+-- Daily quiet hours for a user, or for every user of an app. A user's quiet
+-- hours take precedence over their app's. Times are HH:MM in the device's
+-- local time; a window that ends before it starts spans midnight.
+CREATE TABLE quiet_hours (
+    scope VARCHAR(10) NOT NULL CHECK (scope IN ('user', 'app')),
+    scope_id VARCHAR(255) NOT NULL,
+    start_time VARCHAR(5) NOT NULL CHECK (start_time ~ '^([01][0-9]|2[0-3]):[0-5][0-9]$'),
+    end_time VARCHAR(5) NOT NULL CHECK (end_time ~ '^([01][0-9]|2[0-3]):[0-5][0-9]$'),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (scope, scope_id)
+);
+
+-- Queue messages held until the quiet hours of their devices end. The
+-- scheduler publishes due rows to push_notifications and deletes them.
+CREATE TABLE deferred_pushes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id VARCHAR(255) NOT NULL,
+    message JSONB NOT NULL,
+    deliver_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_deferred_pushes_deliver_at ON deferred_pushes(deliver_at);
+CREATE INDEX idx_deferred_pushes_user_id ON deferred_pushes(user_id);
+
+ALTER TABLE push_notifications DROP CONSTRAINT IF EXISTS push_notifications_status_check;
+ALTER TABLE push_notifications ADD CONSTRAINT push_notifications_status_check
+    CHECK (status IN ('queued', 'sending', 'retrying', 'deferred', 'sent', 'failed', 'suppressed', 'delivered'));
@@ -0,0 +1,7 @@
+-- Notifications received from the API gateway keep the gateway's ID, which
+-- need not be a UUID, so their status can be tracked and looked up by it
+ALTER TABLE push_notifications ADD COLUMN gateway_notification_id VARCHAR(255);
+
+CREATE UNIQUE INDEX idx_push_notifications_gateway_notification_id
+    ON push_notifications(gateway_notification_id)
+    WHERE gateway_notification_id IS NOT NULL;
@@ -0,0 +1,5 @@
+-- Users a request with an Idempotency-Key was addressed to, so erasing a user
+-- also removes the stored responses that hold their devices and results
+ALTER TABLE idempotency_keys ADD COLUMN user_ids TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX idx_idempotency_keys_user_ids ON idempotency_keys USING GIN (user_ids);
@@ -0,0 +1,260 @@
+package logger
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Components whose level can be changed on their own. A component's level
+// applies to loggers named for it, including nested names such as
+// "worker.fcm", where the innermost component with a level wins.
+const (
+	ComponentHTTP   = "http"
+	ComponentGRPC   = "grpc"
+	ComponentWorker = "worker"
+	ComponentFCM    = "fcm"
+	ComponentQueue  = "queue"
+)
+
+// Components lists every component that can be given its own level
+var Components = []string{ComponentHTTP, ComponentGRPC, ComponentWorker, ComponentFCM, ComponentQueue}
+
+// IsComponent reports whether name is a known component
+func IsComponent(name string) bool {
+	for _, component := range Components {
+		if component == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseLevel parses a level name such as "debug" or "warn"
+func ParseLevel(text string) (zapcore.Level, error) {
+	level, err := zapcore.ParseLevel(text)
+	if err != nil {
+		return level, fmt.Errorf("unknown log level %q", text)
+	}
+	return level, nil
+}
+
+// ComponentLevel is a component's level override
+type ComponentLevel struct {
+	Component string     `json:"component" example:"fcm"`
+	Level     string     `json:"level" example:"debug"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// LevelStatus reports the global level and active component overrides
+type LevelStatus struct {
+	Level          string           `json:"level" example:"info"`
+	LevelExpiresAt *time.Time       `json:"level_expires_at,omitempty"`
+	Components     []ComponentLevel `json:"components"`
+}
+
+// override is a component level, in effect until expiresAt unless it is zero
+type override struct {
+	level     zapcore.Level
+	expiresAt time.Time
+}
+
+func (o override) active(now time.Time) bool {
+	return o.expiresAt.IsZero() || now.Before(o.expiresAt)
+}
+
+// levels holds the global level and per-component overrides. Overrides are
+// copied on write so log calls read them without locking.
+type levels struct {
+	global zap.AtomicLevel
+
+	mu             sync.Mutex
+	overrides      atomic.Pointer[map[string]override]
+	globalExpiry   time.Time
+	globalRevert   *time.Timer
+	globalFallback zapcore.Level
+}
+
+func newLevels(level zapcore.Level) *levels {
+	l := &levels{global: zap.NewAtomicLevelAt(level)}
+	l.overrides.Store(&map[string]override{})
+	return l
+}
+
+// enabled reports whether any logger could write at level
+func (l *levels) enabled(level zapcore.Level) bool {
+	if l.global.Enabled(level) {
+		return true
+	}
+	now := time.Now()
+	for _, o := range *l.overrides.Load() {
+		if o.active(now) && level >= o.level {
+			return true
+		}
+	}
+	return false
+}
+
+// enabledFor reports whether the logger named name writes at level
+func (l *levels) enabledFor(name string, level zapcore.Level) bool {
+	overrides := *l.overrides.Load()
+	if len(overrides) > 0 && name != "" {
+		now := time.Now()
+		segments := strings.Split(name, ".")
+		for i := len(segments) - 1; i >= 0; i-- {
+			if o, ok := overrides[segments[i]]; ok && o.active(now) {
+				return level >= o.level
+			}
+		}
+	}
+	return l.global.Enabled(level)
+}
+
+// setGlobal sets the global level, reverting to the current one after ttl
+// when ttl is positive
+func (l *levels) setGlobal(level zapcore.Level, ttl time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.globalRevert != nil {
+		l.globalRevert.Stop()
+		l.globalRevert = nil
+	} else {
+		l.globalFallback = l.global.Level()
+	}
+	l.globalExpiry = time.Time{}
+	l.global.SetLevel(level)
+
+	if ttl <= 0 {
+		return
+	}
+	fallback := l.globalFallback
+	l.globalExpiry = time.Now().Add(ttl)
+	var timer *time.Timer
+	timer = time.AfterFunc(ttl, func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if l.globalRevert != timer {
+			return
+		}
+		l.global.SetLevel(fallback)
+		l.globalRevert = nil
+		l.globalExpiry = time.Time{}
+	})
+	l.globalRevert = timer
+}
+
+// setComponent overrides a component's level, for ttl when ttl is positive
+func (l *levels) setComponent(component string, level zapcore.Level, ttl time.Duration) {
+	o := override{level: level}
+	if ttl > 0 {
+		o.expiresAt = time.Now().Add(ttl)
+	}
+	l.updateOverrides(func(overrides map[string]override) {
+		overrides[component] = o
+	})
+}
+
+// clearComponent removes a component's override
+func (l *levels) clearComponent(component string) {
+	l.updateOverrides(func(overrides map[string]override) {
+		delete(overrides, component)
+	})
+}
+
+// updateOverrides applies fn to a copy of the overrides without expired
+// entries and publishes the copy
+func (l *levels) updateOverrides(fn func(map[string]override)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	current := *l.overrides.Load()
+	updated := make(map[string]override, len(current)+1)
+	for component, o := range current {
+		if o.active(now) {
+			updated[component] = o
+		}
+	}
+	fn(updated)
+	l.overrides.Store(&updated)
+}
+
+func (l *levels) status() LevelStatus {
+	l.mu.Lock()
+	status := LevelStatus{Level: l.global.Level().String(), Components: []ComponentLevel{}}
+	if !l.globalExpiry.IsZero() {
+		expiresAt := l.globalExpiry.UTC()
+		status.LevelExpiresAt = &expiresAt
+	}
+	l.mu.Unlock()
+
+	now := time.Now()
+	for component, o := range *l.overrides.Load() {
+		if !o.active(now) {
+			continue
+		}
+		entry := ComponentLevel{Component: component, Level: o.level.String()}
+		if !o.expiresAt.IsZero() {
+			expiresAt := o.expiresAt.UTC()
+			entry.ExpiresAt = &expiresAt
+		}
+		status.Components = append(status.Components, entry)
+	}
+	sort.Slice(status.Components, func(i, j int) bool {
+		return status.Components[i].Component < status.Components[j].Component
+	})
+	return status
+}
+
+// levelCore filters entries by the level of their logger's component, or the
+// global level when no component override applies
+type levelCore struct {
+	zapcore.Core
+	levels *levels
+}
+
+func (c *levelCore) Enabled(level zapcore.Level) bool {
+	return c.levels.enabled(level)
+}
+
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields), levels: c.levels}
+}
+
+func (c *levelCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.levels.enabledFor(entry.LoggerName, entry.Level) {
+		return checked
+	}
+	return c.Core.Check(entry, checked)
+}
+
+// globalLevels controls the global logger's levels
+var globalLevels = newLevels(zap.InfoLevel)
+
+// SetLevel changes the global level. With a positive ttl the previous level
+// is restored once it elapses.
+func SetLevel(level zapcore.Level, ttl time.Duration) {
+	globalLevels.setGlobal(level, ttl)
+}
+
+// SetComponentLevel overrides a component's level, for ttl when ttl is positive
+func SetComponentLevel(component string, level zapcore.Level, ttl time.Duration) {
+	globalLevels.setComponent(component, level, ttl)
+}
+
+// ClearComponentLevel returns a component to the global level
+func ClearComponentLevel(component string) {
+	globalLevels.clearComponent(component)
+}
+
+// Levels reports the global level and active component overrides
+func Levels() LevelStatus {
+	return globalLevels.status()
+}
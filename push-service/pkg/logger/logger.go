@@ -1,11 +1,19 @@
 package logger
 
 import (
+	"context"
+
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
 func New(level, format string) (*zap.Logger, error) {
+	return build(level, format, newLevels(zap.InfoLevel))
+}
+
+// build creates a logger whose levels are controlled by levels, starting at level
+func build(level, format string, levels *levels) (*zap.Logger, error) {
 	var config zap.Config
 
 	if format == "json" {
@@ -19,24 +27,29 @@ func New(level, format string) (*zap.Logger, error) {
 	if err := logLevel.UnmarshalText([]byte(level)); err != nil {
 		return nil, err
 	}
-	config.Level = zap.NewAtomicLevelAt(logLevel)
+	levels.global.SetLevel(logLevel)
+	// Entries are filtered by levelCore, so the encoder core accepts every level
+	config.Level = zap.NewAtomicLevelAt(zap.DebugLevel)
 
 	// Customize encoder config for better structure
 	config.EncoderConfig.TimeKey = "timestamp"
 	config.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
 
-	return config.Build()
+	return config.Build(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return &levelCore{Core: core, levels: levels}
+	}))
 }
 
 // Global logger instance for easy access
 var globalLogger *zap.Logger
 
 func InitGlobal(level, format string) error {
-	logger, err := New(level, format)
+	logger, err := build(level, format, globalLevels)
 	if err != nil {
 		return err
 	}
 	globalLogger = logger
+	zap.ReplaceGlobals(logger)
 	return nil
 }
 
@@ -48,3 +61,83 @@ func L() *zap.Logger {
 	}
 	return globalLogger
 }
+
+// RequestIDField is the log field carrying a request's correlation ID
+const RequestIDField = "request_id"
+
+// maxRequestIDLength bounds caller-supplied request IDs
+const maxRequestIDLength = 128
+
+// RequestIDOrNew returns id if it is usable as a request ID, or a new random
+// one otherwise. IDs must be printable ASCII without spaces, so a caller cannot
+// inject log lines or oversized values.
+func RequestIDOrNew(id string) string {
+	if id == "" || len(id) > maxRequestIDLength {
+		return uuid.NewString()
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return uuid.NewString()
+		}
+	}
+	return id
+}
+
+type contextKey struct{}
+
+type contextValue struct {
+	requestID string
+	component string
+	logger    *zap.Logger
+}
+
+// withLogger builds the value's logger from its component and request ID
+func (v contextValue) withLogger() contextValue {
+	v.logger = L()
+	if v.component != "" {
+		v.logger = v.logger.Named(v.component)
+	}
+	if v.requestID != "" {
+		v.logger = v.logger.With(zap.String(RequestIDField, v.requestID))
+	}
+	return v
+}
+
+// WithRequestID returns a context carrying the request ID and a logger that
+// adds it to every entry
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	if requestID == "" {
+		return ctx
+	}
+	value, _ := ctx.Value(contextKey{}).(contextValue)
+	value.requestID = requestID
+	return context.WithValue(ctx, contextKey{}, value.withLogger())
+}
+
+// WithComponent returns a context whose logger is named for component, so
+// the component's level applies to everything logged under it
+func WithComponent(ctx context.Context, component string) context.Context {
+	value, _ := ctx.Value(contextKey{}).(contextValue)
+	if value.component != "" {
+		component = value.component + "." + component
+	}
+	value.component = component
+	return context.WithValue(ctx, contextKey{}, value.withLogger())
+}
+
+// RequestIDFromContext returns the request ID stored by WithRequestID, if any
+func RequestIDFromContext(ctx context.Context) string {
+	if value, ok := ctx.Value(contextKey{}).(contextValue); ok {
+		return value.requestID
+	}
+	return ""
+}
+
+// FromContext returns the context's request-scoped logger, or the global
+// logger when the context has no request ID or component
+func FromContext(ctx context.Context) *zap.Logger {
+	if value, ok := ctx.Value(contextKey{}).(contextValue); ok {
+		return value.logger
+	}
+	return L()
+}
@@ -0,0 +1,155 @@
+package logger
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Token redaction modes
+const (
+	// TokenMask keeps the first and last 10 characters of a token
+	TokenMask = "mask"
+	// TokenHash replaces a token with a short SHA-256 digest, so log lines
+	// about the same device can still be matched up
+	TokenHash = "hash"
+)
+
+// Redacted stands in for user content and sensitive data values
+const Redacted = "[REDACTED]"
+
+// RedactionPolicy controls how device tokens, notification content and
+// notification data appear in logs
+type RedactionPolicy struct {
+	Tokens string // TokenMask or TokenHash
+	// Content logs notification titles, bodies and data values as written.
+	// Otherwise only their length is logged.
+	Content bool
+	// DataKeys are notification data keys whose values are always redacted,
+	// matched case-insensitively
+	DataKeys []string
+}
+
+// Validate reports an unknown token mode
+func (p RedactionPolicy) Validate() error {
+	switch p.Tokens {
+	case TokenMask, TokenHash:
+		return nil
+	default:
+		return fmt.Errorf("unknown token redaction mode %q", p.Tokens)
+	}
+}
+
+// redaction is the policy applied by Token, Tokens, Content and Data. Fields
+// are encoded lazily, so a changed policy applies to entries written after it.
+var redaction atomic.Pointer[redactionPolicy]
+
+type redactionPolicy struct {
+	tokens   string
+	content  bool
+	dataKeys map[string]bool
+}
+
+func init() {
+	redaction.Store(&redactionPolicy{tokens: TokenMask})
+}
+
+// SetRedaction replaces the redaction policy
+func SetRedaction(policy RedactionPolicy) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+	dataKeys := make(map[string]bool, len(policy.DataKeys))
+	for _, key := range policy.DataKeys {
+		dataKeys[strings.ToLower(key)] = true
+	}
+	redaction.Store(&redactionPolicy{tokens: policy.Tokens, content: policy.Content, dataKeys: dataKeys})
+	return nil
+}
+
+// RedactToken returns token as it may appear in logs
+func RedactToken(token string) string {
+	if redaction.Load().tokens == TokenHash {
+		sum := sha256.Sum256([]byte(token))
+		return "sha256:" + hex.EncodeToString(sum[:6])
+	}
+	if len(token) <= 20 {
+		return "***"
+	}
+	return token[:10] + "..." + token[len(token)-10:]
+}
+
+// Token logs a device token under the redaction policy
+func Token(key, token string) zap.Field {
+	return zap.Stringer(key, redactedToken(token))
+}
+
+// Tokens logs device tokens under the redaction policy
+func Tokens(key string, tokens []string) zap.Field {
+	return zap.Array(key, redactedTokens(tokens))
+}
+
+// Content logs user-written text, such as a notification title or body,
+// under the redaction policy
+func Content(key, text string) zap.Field {
+	return zap.Stringer(key, redactedContent(text))
+}
+
+// Data logs notification data under the redaction policy. Keys are always
+// logged; values only when content is logged and the key is not listed in
+// the policy's data keys.
+func Data(key string, data map[string]any) zap.Field {
+	return zap.Object(key, redactedData(data))
+}
+
+type redactedToken string
+
+func (t redactedToken) String() string {
+	return RedactToken(string(t))
+}
+
+type redactedTokens []string
+
+func (t redactedTokens) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, token := range t {
+		enc.AppendString(RedactToken(token))
+	}
+	return nil
+}
+
+type redactedContent string
+
+func (c redactedContent) String() string {
+	if redaction.Load().content {
+		return string(c)
+	}
+	return fmt.Sprintf("%s (%d chars)", Redacted, len([]rune(string(c))))
+}
+
+type redactedData map[string]any
+
+func (d redactedData) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	policy := redaction.Load()
+	keys := make([]string, 0, len(d))
+	for key := range d {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if !policy.content || policy.dataKeys[strings.ToLower(key)] {
+			enc.AddString(key, Redacted)
+			continue
+		}
+		if err := enc.AddReflected(key, d[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package logger
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/fs"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const rawToken = "fcm-token-abcdefghijklmnopqrstuvwxyz-0123456789"
+
+// encode writes fields as a JSON log line
+func encode(t *testing.T, fields ...zap.Field) string {
+	t.Helper()
+	var buf bytes.Buffer
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), zapcore.AddSync(&buf), zap.DebugLevel)
+	zap.New(core).Info("test", fields...)
+	return buf.String()
+}
+
+func setRedaction(t *testing.T, policy RedactionPolicy) {
+	t.Helper()
+	if err := SetRedaction(policy); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { SetRedaction(RedactionPolicy{Tokens: TokenMask}) })
+}
+
+func TestTokenRedaction(t *testing.T) {
+	for _, mode := range []string{TokenMask, TokenHash} {
+		t.Run(mode, func(t *testing.T) {
+			setRedaction(t, RedactionPolicy{Tokens: mode})
+
+			line := encode(t, Token("token", rawToken), Tokens("tokens", []string{rawToken, "short"}))
+			if strings.Contains(line, rawToken) || strings.Contains(line, `"short"`) {
+				t.Fatalf("raw token logged: %s", line)
+			}
+			if RedactToken(rawToken) != RedactToken(rawToken) {
+				t.Fatal("redacted token is not stable")
+			}
+		})
+	}
+
+	if err := SetRedaction(RedactionPolicy{Tokens: "full"}); err == nil {
+		t.Fatal("expected an unknown token mode to be rejected")
+	}
+}
+
+func TestContentRedaction(t *testing.T) {
+	data := map[string]any{"order_id": "A-1", "email": "user@example.com"}
+
+	setRedaction(t, RedactionPolicy{Tokens: TokenMask})
+	line := encode(t, Content("title", "Your order shipped"), Data("data", data))
+	for _, raw := range []string{"Your order shipped", "A-1", "user@example.com"} {
+		if strings.Contains(line, raw) {
+			t.Fatalf("%q logged without content logging enabled: %s", raw, line)
+		}
+	}
+	if !strings.Contains(line, `"order_id"`) {
+		t.Fatalf("data keys should still be logged: %s", line)
+	}
+
+	setRedaction(t, RedactionPolicy{Tokens: TokenMask, Content: true, DataKeys: []string{"Email"}})
+	line = encode(t, Content("title", "Your order shipped"), Data("data", data))
+	if !strings.Contains(line, "Your order shipped") || !strings.Contains(line, "A-1") {
+		t.Fatalf("content should be logged: %s", line)
+	}
+	if strings.Contains(line, "user@example.com") {
+		t.Fatalf("configured data key logged: %s", line)
+	}
+}
+
+// stringFields are zap field constructors that write a value as given
+var stringFields = map[string]bool{
+	"String": true, "Strings": true, "Stringp": true, "ByteString": true, "ByteStrings": true,
+	"Stringer": true, "Any": true, "Reflect": true, "Object": true, "Array": true, "Inline": true,
+}
+
+// TestNoRawTokensLogged fails when a zap field in the service's source logs
+// something that looks like a device token or a whole device, which must go
+// through Token, Tokens or another redacting field instead
+func TestNoRawTokensLogged(t *testing.T) {
+	root := filepath.Join("..", "..")
+	fset := token.NewFileSet()
+	checked := 0
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			switch d.Name() {
+			case "docs", "proto", "vendor", ".git":
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		// redact.go builds the redacting fields themselves
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") || path == filepath.Join(root, "pkg", "logger", "redact.go") {
+			return nil
+		}
+
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return err
+		}
+		checked++
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) < 2 {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok || !stringFields[sel.Sel.Name] {
+				return true
+			}
+			if pkg, ok := sel.X.(*ast.Ident); !ok || pkg.Name != "zap" {
+				return true
+			}
+
+			key := types.ExprString(call.Args[0])
+			if lit, ok := call.Args[0].(*ast.BasicLit); ok {
+				key, _ = strconv.Unquote(lit.Value)
+			}
+			value := strings.ToLower(types.ExprString(call.Args[1]))
+			if strings.Contains(strings.ToLower(key), "token") || strings.Contains(value, "token") ||
+				value == "device" || value == "devices" {
+				t.Errorf("%s: zap.%s(%q, %s) may log a raw device token; use logger.Token or logger.Tokens",
+					fset.Position(call.Pos()), sel.Sel.Name, key, types.ExprString(call.Args[1]))
+			}
+			return true
+		})
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if checked == 0 {
+		t.Fatal("no source files checked")
+	}
+}
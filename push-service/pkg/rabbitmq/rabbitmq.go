@@ -5,12 +5,56 @@ import (
 	"encoding/json"
 	"fmt"
 	"push-service/internal/config"
+	"push-service/pkg/logger"
 	"time"
 
 	amqp "github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel"
 	"go.uber.org/zap"
 )
 
+// RequestIDHeader is the message header carrying the publishing request's ID
+const RequestIDHeader = "x-request-id"
+
+// publishHeaders returns headers for a message published under ctx, adding
+// the request ID and trace context when the context carries them
+func publishHeaders(ctx context.Context, headers amqp.Table) amqp.Table {
+	if headers == nil {
+		headers = amqp.Table{}
+	}
+	if requestID := logger.RequestIDFromContext(ctx); requestID != "" {
+		headers[RequestIDHeader] = requestID
+	}
+	otel.GetTextMapPropagator().Inject(ctx, headerCarrier(headers))
+	return headers
+}
+
+// ExtractTraceContext returns ctx with the trace context of a delivery's
+// headers, so consumer spans continue the publisher's trace
+func ExtractTraceContext(ctx context.Context, headers amqp.Table) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, headerCarrier(headers))
+}
+
+// headerCarrier adapts AMQP headers for trace context propagation
+type headerCarrier amqp.Table
+
+func (c headerCarrier) Get(key string) string {
+	value, _ := c[key].(string)
+	return value
+}
+
+func (c headerCarrier) Set(key, value string) {
+	c[key] = value
+}
+
+func (c headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
 type RabbitMQClient struct {
 	conn    *amqp.Connection
 	channel *amqp.Channel
@@ -80,10 +124,13 @@ func (r *RabbitMQClient) Close() error {
 }
 
 func (r *RabbitMQClient) Ping(ctx context.Context) error {
-	// Check if connection is still alive
+	// Check if connection and channel are still alive
 	if r.conn.IsClosed() {
 		return fmt.Errorf("connection is closed")
 	}
+	if r.channel.IsClosed() {
+		return fmt.Errorf("channel is closed")
+	}
 	return nil
 }
 
@@ -142,6 +189,7 @@ func (r *RabbitMQClient) Enqueue(ctx context.Context, exchange, routingKey strin
 			Body:         jsonMessage,
 			DeliveryMode: amqp.Persistent, // Make message persistent
 			Timestamp:    time.Now(),
+			Headers:      publishHeaders(ctx, nil),
 		},
 	)
 
@@ -172,9 +220,9 @@ func (r *RabbitMQClient) EnqueueWithDelay(ctx context.Context, exchange, routing
 			Body:         jsonMessage,
 			DeliveryMode: amqp.Persistent,
 			Timestamp:    time.Now(),
-			Headers: amqp.Table{
+			Headers: publishHeaders(ctx, amqp.Table{
 				"x-delay": delayMs,
-			},
+			}),
 		},
 	)
 
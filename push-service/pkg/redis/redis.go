@@ -14,6 +14,9 @@ type RedisClient struct {
 	Client *redis.Client
 }
 
+// NewRedisClient creates a client and tests the connection. The client is
+// returned with the error if Redis does not answer, as it reconnects once
+// Redis is back; it is nil only if the configuration is invalid.
 func NewRedisClient(cfg *config.RedisConfig) (*RedisClient, error) {
 	// Use the GetRedisURL method
 	redisURL := cfg.GetRedisURL()
@@ -30,7 +33,7 @@ func NewRedisClient(cfg *config.RedisConfig) (*RedisClient, error) {
 	defer cancel()
 
 	if err := client.Ping(ctx).Err(); err != nil {
-		return nil, err
+		return &RedisClient{Client: client}, err
 	}
 
 	zap.L().Info("Connected to Redis", zap.String("url", redisURL))
@@ -0,0 +1,133 @@
+// Package tracing sets up OpenTelemetry tracing and span export
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"push-service/internal/config"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// Span exporters
+const (
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+)
+
+// instrumentationName names the tracer used for the service's own spans
+const instrumentationName = "push-service"
+
+// Init installs the global tracer provider and W3C trace context propagator.
+// The returned function flushes pending spans and must be called on shutdown.
+// When tracing is disabled only the propagator is installed, so incoming
+// trace context is still passed on to published messages.
+func Init(ctx context.Context, cfg *config.TracingConfig) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if !cfg.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, closer, err := newExporter(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithAttributes(semconv.ServiceName(cfg.ServiceName)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	zap.L().Info("Tracing enabled",
+		zap.String("exporter", cfg.Exporter),
+		zap.Float64("sample_ratio", cfg.SampleRatio),
+	)
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if closer != nil {
+			if closeErr := closer.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		return err
+	}, nil
+}
+
+// newExporter builds the configured span exporter, returning the file to
+// close on shutdown for the file exporter
+func newExporter(ctx context.Context, cfg *config.TracingConfig) (sdktrace.SpanExporter, io.Closer, error) {
+	switch cfg.Exporter {
+	case ExporterOTLP:
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.Endpoint)}
+		if cfg.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		exporter, err := otlptracegrpc.New(ctx, opts...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+		}
+		return exporter, nil, nil
+	case ExporterStdout:
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create stdout exporter: %w", err)
+		}
+		return exporter, nil, nil
+	case ExporterFile:
+		file, err := os.OpenFile(cfg.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open trace file: %w", err)
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(file))
+		if err != nil {
+			file.Close()
+			return nil, nil, fmt.Errorf("failed to create file exporter: %w", err)
+		}
+		return exporter, file, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown trace exporter %q", cfg.Exporter)
+	}
+}
+
+// Tracer returns the tracer for the service's own spans
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// End records err on span, if any, and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
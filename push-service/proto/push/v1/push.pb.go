@@ -0,0 +1,1688 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: proto/push/v1/push.proto
+
+package pushv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Platform is the platform a device token belongs to.
+type Platform int32
+
+const (
+	Platform_PLATFORM_UNSPECIFIED Platform = 0
+	Platform_PLATFORM_IOS         Platform = 1
+	Platform_PLATFORM_ANDROID     Platform = 2
+	Platform_PLATFORM_WEB         Platform = 3
+)
+
+// Enum value maps for Platform.
+var (
+	Platform_name = map[int32]string{
+		0: "PLATFORM_UNSPECIFIED",
+		1: "PLATFORM_IOS",
+		2: "PLATFORM_ANDROID",
+		3: "PLATFORM_WEB",
+	}
+	Platform_value = map[string]int32{
+		"PLATFORM_UNSPECIFIED": 0,
+		"PLATFORM_IOS":         1,
+		"PLATFORM_ANDROID":     2,
+		"PLATFORM_WEB":         3,
+	}
+)
+
+func (x Platform) Enum() *Platform {
+	p := new(Platform)
+	*p = x
+	return p
+}
+
+func (x Platform) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Platform) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_push_v1_push_proto_enumTypes[0].Descriptor()
+}
+
+func (Platform) Type() protoreflect.EnumType {
+	return &file_proto_push_v1_push_proto_enumTypes[0]
+}
+
+func (x Platform) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Platform.Descriptor instead.
+func (Platform) EnumDescriptor() ([]byte, []int) {
+	return file_proto_push_v1_push_proto_rawDescGZIP(), []int{0}
+}
+
+// NotificationState is the delivery state of a notification.
+type NotificationState int32
+
+const (
+	NotificationState_NOTIFICATION_STATE_UNSPECIFIED NotificationState = 0
+	// Waiting in the queue.
+	NotificationState_NOTIFICATION_STATE_QUEUED NotificationState = 1
+	// Being sent to FCM by a worker.
+	NotificationState_NOTIFICATION_STATE_SENDING NotificationState = 2
+	// Sending failed and will be retried.
+	NotificationState_NOTIFICATION_STATE_RETRYING NotificationState = 3
+	// FCM accepted the notification for at least one device.
+	NotificationState_NOTIFICATION_STATE_SENT NotificationState = 4
+	// Sending failed for every device and will not be retried.
+	NotificationState_NOTIFICATION_STATE_FAILED NotificationState = 5
+	// Every device opted out of the notification's category.
+	NotificationState_NOTIFICATION_STATE_SUPPRESSED NotificationState = 6
+	// Held until the quiet hours of its devices end.
+	NotificationState_NOTIFICATION_STATE_DEFERRED NotificationState = 7
+)
+
+// Enum value maps for NotificationState.
+var (
+	NotificationState_name = map[int32]string{
+		0: "NOTIFICATION_STATE_UNSPECIFIED",
+		1: "NOTIFICATION_STATE_QUEUED",
+		2: "NOTIFICATION_STATE_SENDING",
+		3: "NOTIFICATION_STATE_RETRYING",
+		4: "NOTIFICATION_STATE_SENT",
+		5: "NOTIFICATION_STATE_FAILED",
+		6: "NOTIFICATION_STATE_SUPPRESSED",
+		7: "NOTIFICATION_STATE_DEFERRED",
+	}
+	NotificationState_value = map[string]int32{
+		"NOTIFICATION_STATE_UNSPECIFIED": 0,
+		"NOTIFICATION_STATE_QUEUED":      1,
+		"NOTIFICATION_STATE_SENDING":     2,
+		"NOTIFICATION_STATE_RETRYING":    3,
+		"NOTIFICATION_STATE_SENT":        4,
+		"NOTIFICATION_STATE_FAILED":      5,
+		"NOTIFICATION_STATE_SUPPRESSED":  6,
+		"NOTIFICATION_STATE_DEFERRED":    7,
+	}
+)
+
+func (x NotificationState) Enum() *NotificationState {
+	p := new(NotificationState)
+	*p = x
+	return p
+}
+
+func (x NotificationState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (NotificationState) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_push_v1_push_proto_enumTypes[1].Descriptor()
+}
+
+func (NotificationState) Type() protoreflect.EnumType {
+	return &file_proto_push_v1_push_proto_enumTypes[1]
+}
+
+func (x NotificationState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use NotificationState.Descriptor instead.
+func (NotificationState) EnumDescriptor() ([]byte, []int) {
+	return file_proto_push_v1_push_proto_rawDescGZIP(), []int{1}
+}
+
+type Device struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	App           string                 `protobuf:"bytes,3,opt,name=app,proto3" json:"app,omitempty"`
+	Token         string                 `protobuf:"bytes,4,opt,name=token,proto3" json:"token,omitempty"`
+	Platform      Platform               `protobuf:"varint,5,opt,name=platform,proto3,enum=push.v1.Platform" json:"platform,omitempty"`
+	IsActive      bool                   `protobuf:"varint,6,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
+	Metadata      map[string]string      `protobuf:"bytes,7,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Tags          []string               `protobuf:"bytes,8,rep,name=tags,proto3" json:"tags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Device) Reset() {
+	*x = Device{}
+	mi := &file_proto_push_v1_push_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Device) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Device) ProtoMessage() {}
+
+func (x *Device) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_push_v1_push_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Device.ProtoReflect.Descriptor instead.
+func (*Device) Descriptor() ([]byte, []int) {
+	return file_proto_push_v1_push_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Device) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Device) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Device) GetApp() string {
+	if x != nil {
+		return x.App
+	}
+	return ""
+}
+
+func (x *Device) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *Device) GetPlatform() Platform {
+	if x != nil {
+		return x.Platform
+	}
+	return Platform_PLATFORM_UNSPECIFIED
+}
+
+func (x *Device) GetIsActive() bool {
+	if x != nil {
+		return x.IsActive
+	}
+	return false
+}
+
+func (x *Device) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *Device) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type RegisterDeviceRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	UserId string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// Defaults to "default".
+	App      string   `protobuf:"bytes,2,opt,name=app,proto3" json:"app,omitempty"`
+	Token    string   `protobuf:"bytes,3,opt,name=token,proto3" json:"token,omitempty"`
+	Platform Platform `protobuf:"varint,4,opt,name=platform,proto3,enum=push.v1.Platform" json:"platform,omitempty"`
+	// Metadata such as app_version, merged into any existing metadata.
+	Metadata      map[string]string `protobuf:"bytes,5,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Tags          []string          `protobuf:"bytes,6,rep,name=tags,proto3" json:"tags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterDeviceRequest) Reset() {
+	*x = RegisterDeviceRequest{}
+	mi := &file_proto_push_v1_push_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterDeviceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterDeviceRequest) ProtoMessage() {}
+
+func (x *RegisterDeviceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_push_v1_push_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterDeviceRequest.ProtoReflect.Descriptor instead.
+func (*RegisterDeviceRequest) Descriptor() ([]byte, []int) {
+	return file_proto_push_v1_push_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RegisterDeviceRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *RegisterDeviceRequest) GetApp() string {
+	if x != nil {
+		return x.App
+	}
+	return ""
+}
+
+func (x *RegisterDeviceRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *RegisterDeviceRequest) GetPlatform() Platform {
+	if x != nil {
+		return x.Platform
+	}
+	return Platform_PLATFORM_UNSPECIFIED
+}
+
+func (x *RegisterDeviceRequest) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *RegisterDeviceRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type RegisterDeviceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Device        *Device                `protobuf:"bytes,1,opt,name=device,proto3" json:"device,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterDeviceResponse) Reset() {
+	*x = RegisterDeviceResponse{}
+	mi := &file_proto_push_v1_push_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterDeviceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterDeviceResponse) ProtoMessage() {}
+
+func (x *RegisterDeviceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_push_v1_push_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterDeviceResponse.ProtoReflect.Descriptor instead.
+func (*RegisterDeviceResponse) Descriptor() ([]byte, []int) {
+	return file_proto_push_v1_push_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RegisterDeviceResponse) GetDevice() *Device {
+	if x != nil {
+		return x.Device
+	}
+	return nil
+}
+
+type UnregisterDeviceRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Token string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	// App the token is registered in. Defaults to "default".
+	App           string `protobuf:"bytes,2,opt,name=app,proto3" json:"app,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnregisterDeviceRequest) Reset() {
+	*x = UnregisterDeviceRequest{}
+	mi := &file_proto_push_v1_push_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnregisterDeviceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnregisterDeviceRequest) ProtoMessage() {}
+
+func (x *UnregisterDeviceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_push_v1_push_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnregisterDeviceRequest.ProtoReflect.Descriptor instead.
+func (*UnregisterDeviceRequest) Descriptor() ([]byte, []int) {
+	return file_proto_push_v1_push_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *UnregisterDeviceRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *UnregisterDeviceRequest) GetApp() string {
+	if x != nil {
+		return x.App
+	}
+	return ""
+}
+
+type UnregisterDeviceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnregisterDeviceResponse) Reset() {
+	*x = UnregisterDeviceResponse{}
+	mi := &file_proto_push_v1_push_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnregisterDeviceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnregisterDeviceResponse) ProtoMessage() {}
+
+func (x *UnregisterDeviceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_push_v1_push_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnregisterDeviceResponse.ProtoReflect.Descriptor instead.
+func (*UnregisterDeviceResponse) Descriptor() ([]byte, []int) {
+	return file_proto_push_v1_push_proto_rawDescGZIP(), []int{4}
+}
+
+type ListUserDevicesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUserDevicesRequest) Reset() {
+	*x = ListUserDevicesRequest{}
+	mi := &file_proto_push_v1_push_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUserDevicesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUserDevicesRequest) ProtoMessage() {}
+
+func (x *ListUserDevicesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_push_v1_push_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUserDevicesRequest.ProtoReflect.Descriptor instead.
+func (*ListUserDevicesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_push_v1_push_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListUserDevicesRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type ListUserDevicesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Devices       []*Device              `protobuf:"bytes,1,rep,name=devices,proto3" json:"devices,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUserDevicesResponse) Reset() {
+	*x = ListUserDevicesResponse{}
+	mi := &file_proto_push_v1_push_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUserDevicesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUserDevicesResponse) ProtoMessage() {}
+
+func (x *ListUserDevicesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_push_v1_push_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUserDevicesResponse.ProtoReflect.Descriptor instead.
+func (*ListUserDevicesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_push_v1_push_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListUserDevicesResponse) GetDevices() []*Device {
+	if x != nil {
+		return x.Devices
+	}
+	return nil
+}
+
+type SendPushRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	UserId string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Title  string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Body   string                 `protobuf:"bytes,3,opt,name=body,proto3" json:"body,omitempty"`
+	Image  string                 `protobuf:"bytes,4,opt,name=image,proto3" json:"image,omitempty"`
+	Link   string                 `protobuf:"bytes,5,opt,name=link,proto3" json:"link,omitempty"`
+	Data   map[string]string      `protobuf:"bytes,6,rep,name=data,proto3" json:"data,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Only send to devices on these platforms.
+	Platforms []Platform `protobuf:"varint,7,rep,packed,name=platforms,proto3,enum=push.v1.Platform" json:"platforms,omitempty"`
+	// Send now, bypassing the queue, and report per-device results.
+	Sync bool `protobuf:"varint,8,opt,name=sync,proto3" json:"sync,omitempty"`
+	// One of transactional, security, marketing, promotions, social or
+	// reminders, checked against the user's preferences. Defaults to
+	// transactional.
+	Category      string `protobuf:"bytes,9,opt,name=category,proto3" json:"category,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendPushRequest) Reset() {
+	*x = SendPushRequest{}
+	mi := &file_proto_push_v1_push_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendPushRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendPushRequest) ProtoMessage() {}
+
+func (x *SendPushRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_push_v1_push_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendPushRequest.ProtoReflect.Descriptor instead.
+func (*SendPushRequest) Descriptor() ([]byte, []int) {
+	return file_proto_push_v1_push_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SendPushRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SendPushRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *SendPushRequest) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *SendPushRequest) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+func (x *SendPushRequest) GetLink() string {
+	if x != nil {
+		return x.Link
+	}
+	return ""
+}
+
+func (x *SendPushRequest) GetData() map[string]string {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *SendPushRequest) GetPlatforms() []Platform {
+	if x != nil {
+		return x.Platforms
+	}
+	return nil
+}
+
+func (x *SendPushRequest) GetSync() bool {
+	if x != nil {
+		return x.Sync
+	}
+	return false
+}
+
+func (x *SendPushRequest) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+type SendPushResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID to pass to GetNotificationStatus or WatchNotificationStatus.
+	NotificationId string `protobuf:"bytes,1,opt,name=notification_id,json=notificationId,proto3" json:"notification_id,omitempty"`
+	// Set for synchronous sends only.
+	SuccessCount int32               `protobuf:"varint,2,opt,name=success_count,json=successCount,proto3" json:"success_count,omitempty"`
+	FailureCount int32               `protobuf:"varint,3,opt,name=failure_count,json=failureCount,proto3" json:"failure_count,omitempty"`
+	Results      []*DeviceSendResult `protobuf:"bytes,4,rep,name=results,proto3" json:"results,omitempty"`
+	// Devices that opted out of the notification's category.
+	SuppressedCount int32 `protobuf:"varint,5,opt,name=suppressed_count,json=suppressedCount,proto3" json:"suppressed_count,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SendPushResponse) Reset() {
+	*x = SendPushResponse{}
+	mi := &file_proto_push_v1_push_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendPushResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendPushResponse) ProtoMessage() {}
+
+func (x *SendPushResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_push_v1_push_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendPushResponse.ProtoReflect.Descriptor instead.
+func (*SendPushResponse) Descriptor() ([]byte, []int) {
+	return file_proto_push_v1_push_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SendPushResponse) GetNotificationId() string {
+	if x != nil {
+		return x.NotificationId
+	}
+	return ""
+}
+
+func (x *SendPushResponse) GetSuccessCount() int32 {
+	if x != nil {
+		return x.SuccessCount
+	}
+	return 0
+}
+
+func (x *SendPushResponse) GetFailureCount() int32 {
+	if x != nil {
+		return x.FailureCount
+	}
+	return 0
+}
+
+func (x *SendPushResponse) GetResults() []*DeviceSendResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *SendPushResponse) GetSuppressedCount() int32 {
+	if x != nil {
+		return x.SuppressedCount
+	}
+	return 0
+}
+
+// DeviceSendResult is the outcome of a synchronous send to one device.
+type DeviceSendResult struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId  string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	Platform  Platform               `protobuf:"varint,2,opt,name=platform,proto3,enum=push.v1.Platform" json:"platform,omitempty"`
+	Token     string                 `protobuf:"bytes,3,opt,name=token,proto3" json:"token,omitempty"`
+	Success   bool                   `protobuf:"varint,4,opt,name=success,proto3" json:"success,omitempty"`
+	MessageId string                 `protobuf:"bytes,5,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	// One of unregistered, invalid_argument, credential_mismatch,
+	// rate_exceeded, unavailable, internal, timeout or unknown.
+	ErrorCode string `protobuf:"bytes,6,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
+	Error     string `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`
+	// Not sent because the device opted out of the notification's category.
+	Suppressed    bool `protobuf:"varint,8,opt,name=suppressed,proto3" json:"suppressed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeviceSendResult) Reset() {
+	*x = DeviceSendResult{}
+	mi := &file_proto_push_v1_push_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeviceSendResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeviceSendResult) ProtoMessage() {}
+
+func (x *DeviceSendResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_push_v1_push_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeviceSendResult.ProtoReflect.Descriptor instead.
+func (*DeviceSendResult) Descriptor() ([]byte, []int) {
+	return file_proto_push_v1_push_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DeviceSendResult) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *DeviceSendResult) GetPlatform() Platform {
+	if x != nil {
+		return x.Platform
+	}
+	return Platform_PLATFORM_UNSPECIFIED
+}
+
+func (x *DeviceSendResult) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *DeviceSendResult) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *DeviceSendResult) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *DeviceSendResult) GetErrorCode() string {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ""
+}
+
+func (x *DeviceSendResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *DeviceSendResult) GetSuppressed() bool {
+	if x != nil {
+		return x.Suppressed
+	}
+	return false
+}
+
+type SendBulkPushRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	UserIds []string               `protobuf:"bytes,1,rep,name=user_ids,json=userIds,proto3" json:"user_ids,omitempty"`
+	Title   string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Body    string                 `protobuf:"bytes,3,opt,name=body,proto3" json:"body,omitempty"`
+	Data    map[string]string      `protobuf:"bytes,4,rep,name=data,proto3" json:"data,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// As in SendPushRequest.
+	Category      string `protobuf:"bytes,5,opt,name=category,proto3" json:"category,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendBulkPushRequest) Reset() {
+	*x = SendBulkPushRequest{}
+	mi := &file_proto_push_v1_push_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendBulkPushRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendBulkPushRequest) ProtoMessage() {}
+
+func (x *SendBulkPushRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_push_v1_push_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendBulkPushRequest.ProtoReflect.Descriptor instead.
+func (*SendBulkPushRequest) Descriptor() ([]byte, []int) {
+	return file_proto_push_v1_push_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *SendBulkPushRequest) GetUserIds() []string {
+	if x != nil {
+		return x.UserIds
+	}
+	return nil
+}
+
+func (x *SendBulkPushRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *SendBulkPushRequest) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *SendBulkPushRequest) GetData() map[string]string {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *SendBulkPushRequest) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+type SendBulkPushResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserCount     int32                  `protobuf:"varint,1,opt,name=user_count,json=userCount,proto3" json:"user_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendBulkPushResponse) Reset() {
+	*x = SendBulkPushResponse{}
+	mi := &file_proto_push_v1_push_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendBulkPushResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendBulkPushResponse) ProtoMessage() {}
+
+func (x *SendBulkPushResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_push_v1_push_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendBulkPushResponse.ProtoReflect.Descriptor instead.
+func (*SendBulkPushResponse) Descriptor() ([]byte, []int) {
+	return file_proto_push_v1_push_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SendBulkPushResponse) GetUserCount() int32 {
+	if x != nil {
+		return x.UserCount
+	}
+	return 0
+}
+
+type SendSegmentPushRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Segment definition, e.g. "platform = ios AND tag:beta AND app_version >= 3.0".
+	Segment string            `protobuf:"bytes,1,opt,name=segment,proto3" json:"segment,omitempty"`
+	Title   string            `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Body    string            `protobuf:"bytes,3,opt,name=body,proto3" json:"body,omitempty"`
+	Image   string            `protobuf:"bytes,4,opt,name=image,proto3" json:"image,omitempty"`
+	Link    string            `protobuf:"bytes,5,opt,name=link,proto3" json:"link,omitempty"`
+	Data    map[string]string `protobuf:"bytes,6,rep,name=data,proto3" json:"data,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// As in SendPushRequest.
+	Category      string `protobuf:"bytes,7,opt,name=category,proto3" json:"category,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendSegmentPushRequest) Reset() {
+	*x = SendSegmentPushRequest{}
+	mi := &file_proto_push_v1_push_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendSegmentPushRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendSegmentPushRequest) ProtoMessage() {}
+
+func (x *SendSegmentPushRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_push_v1_push_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendSegmentPushRequest.ProtoReflect.Descriptor instead.
+func (*SendSegmentPushRequest) Descriptor() ([]byte, []int) {
+	return file_proto_push_v1_push_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *SendSegmentPushRequest) GetSegment() string {
+	if x != nil {
+		return x.Segment
+	}
+	return ""
+}
+
+func (x *SendSegmentPushRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *SendSegmentPushRequest) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *SendSegmentPushRequest) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+func (x *SendSegmentPushRequest) GetLink() string {
+	if x != nil {
+		return x.Link
+	}
+	return ""
+}
+
+func (x *SendSegmentPushRequest) GetData() map[string]string {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *SendSegmentPushRequest) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+type SendSegmentPushResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Segment       string                 `protobuf:"bytes,1,opt,name=segment,proto3" json:"segment,omitempty"`
+	DeviceCount   int32                  `protobuf:"varint,2,opt,name=device_count,json=deviceCount,proto3" json:"device_count,omitempty"`
+	EnqueuedCount int32                  `protobuf:"varint,3,opt,name=enqueued_count,json=enqueuedCount,proto3" json:"enqueued_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendSegmentPushResponse) Reset() {
+	*x = SendSegmentPushResponse{}
+	mi := &file_proto_push_v1_push_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendSegmentPushResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendSegmentPushResponse) ProtoMessage() {}
+
+func (x *SendSegmentPushResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_push_v1_push_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendSegmentPushResponse.ProtoReflect.Descriptor instead.
+func (*SendSegmentPushResponse) Descriptor() ([]byte, []int) {
+	return file_proto_push_v1_push_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SendSegmentPushResponse) GetSegment() string {
+	if x != nil {
+		return x.Segment
+	}
+	return ""
+}
+
+func (x *SendSegmentPushResponse) GetDeviceCount() int32 {
+	if x != nil {
+		return x.DeviceCount
+	}
+	return 0
+}
+
+func (x *SendSegmentPushResponse) GetEnqueuedCount() int32 {
+	if x != nil {
+		return x.EnqueuedCount
+	}
+	return 0
+}
+
+type GetNotificationStatusRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	NotificationId string                 `protobuf:"bytes,1,opt,name=notification_id,json=notificationId,proto3" json:"notification_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetNotificationStatusRequest) Reset() {
+	*x = GetNotificationStatusRequest{}
+	mi := &file_proto_push_v1_push_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetNotificationStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNotificationStatusRequest) ProtoMessage() {}
+
+func (x *GetNotificationStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_push_v1_push_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNotificationStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetNotificationStatusRequest) Descriptor() ([]byte, []int) {
+	return file_proto_push_v1_push_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetNotificationStatusRequest) GetNotificationId() string {
+	if x != nil {
+		return x.NotificationId
+	}
+	return ""
+}
+
+type WatchNotificationStatusRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	NotificationId string                 `protobuf:"bytes,1,opt,name=notification_id,json=notificationId,proto3" json:"notification_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *WatchNotificationStatusRequest) Reset() {
+	*x = WatchNotificationStatusRequest{}
+	mi := &file_proto_push_v1_push_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchNotificationStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchNotificationStatusRequest) ProtoMessage() {}
+
+func (x *WatchNotificationStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_push_v1_push_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchNotificationStatusRequest.ProtoReflect.Descriptor instead.
+func (*WatchNotificationStatusRequest) Descriptor() ([]byte, []int) {
+	return file_proto_push_v1_push_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *WatchNotificationStatusRequest) GetNotificationId() string {
+	if x != nil {
+		return x.NotificationId
+	}
+	return ""
+}
+
+type NotificationStatus struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	NotificationId string                 `protobuf:"bytes,1,opt,name=notification_id,json=notificationId,proto3" json:"notification_id,omitempty"`
+	UserId         string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	State          NotificationState      `protobuf:"varint,3,opt,name=state,proto3,enum=push.v1.NotificationState" json:"state,omitempty"`
+	DeviceCount    int32                  `protobuf:"varint,4,opt,name=device_count,json=deviceCount,proto3" json:"device_count,omitempty"`
+	SuccessCount   int32                  `protobuf:"varint,5,opt,name=success_count,json=successCount,proto3" json:"success_count,omitempty"`
+	FailureCount   int32                  `protobuf:"varint,6,opt,name=failure_count,json=failureCount,proto3" json:"failure_count,omitempty"`
+	ErrorMessage   string                 `protobuf:"bytes,7,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt      *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	SentAt         *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=sent_at,json=sentAt,proto3" json:"sent_at,omitempty"`
+	Category       string                 `protobuf:"bytes,11,opt,name=category,proto3" json:"category,omitempty"`
+	// Devices that opted out of the notification's category.
+	SuppressedCount int32 `protobuf:"varint,12,opt,name=suppressed_count,json=suppressedCount,proto3" json:"suppressed_count,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *NotificationStatus) Reset() {
+	*x = NotificationStatus{}
+	mi := &file_proto_push_v1_push_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NotificationStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NotificationStatus) ProtoMessage() {}
+
+func (x *NotificationStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_push_v1_push_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NotificationStatus.ProtoReflect.Descriptor instead.
+func (*NotificationStatus) Descriptor() ([]byte, []int) {
+	return file_proto_push_v1_push_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *NotificationStatus) GetNotificationId() string {
+	if x != nil {
+		return x.NotificationId
+	}
+	return ""
+}
+
+func (x *NotificationStatus) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *NotificationStatus) GetState() NotificationState {
+	if x != nil {
+		return x.State
+	}
+	return NotificationState_NOTIFICATION_STATE_UNSPECIFIED
+}
+
+func (x *NotificationStatus) GetDeviceCount() int32 {
+	if x != nil {
+		return x.DeviceCount
+	}
+	return 0
+}
+
+func (x *NotificationStatus) GetSuccessCount() int32 {
+	if x != nil {
+		return x.SuccessCount
+	}
+	return 0
+}
+
+func (x *NotificationStatus) GetFailureCount() int32 {
+	if x != nil {
+		return x.FailureCount
+	}
+	return 0
+}
+
+func (x *NotificationStatus) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+func (x *NotificationStatus) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *NotificationStatus) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *NotificationStatus) GetSentAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SentAt
+	}
+	return nil
+}
+
+func (x *NotificationStatus) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *NotificationStatus) GetSuppressedCount() int32 {
+	if x != nil {
+		return x.SuppressedCount
+	}
+	return 0
+}
+
+type GetQueueStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetQueueStatsRequest) Reset() {
+	*x = GetQueueStatsRequest{}
+	mi := &file_proto_push_v1_push_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetQueueStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetQueueStatsRequest) ProtoMessage() {}
+
+func (x *GetQueueStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_push_v1_push_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetQueueStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetQueueStatsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_push_v1_push_proto_rawDescGZIP(), []int{17}
+}
+
+type GetQueueStatsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Message counts keyed by queue name.
+	Queues        map[string]int64 `protobuf:"bytes,1,rep,name=queues,proto3" json:"queues,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetQueueStatsResponse) Reset() {
+	*x = GetQueueStatsResponse{}
+	mi := &file_proto_push_v1_push_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetQueueStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetQueueStatsResponse) ProtoMessage() {}
+
+func (x *GetQueueStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_push_v1_push_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetQueueStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetQueueStatsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_push_v1_push_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetQueueStatsResponse) GetQueues() map[string]int64 {
+	if x != nil {
+		return x.Queues
+	}
+	return nil
+}
+
+var File_proto_push_v1_push_proto protoreflect.FileDescriptor
+
+const file_proto_push_v1_push_proto_rawDesc = "" +
+	"\n" +
+	"\x18proto/push/v1/push.proto\x12\apush.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xb1\x02\n" +
+	"\x06Device\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x10\n" +
+	"\x03app\x18\x03 \x01(\tR\x03app\x12\x14\n" +
+	"\x05token\x18\x04 \x01(\tR\x05token\x12-\n" +
+	"\bplatform\x18\x05 \x01(\x0e2\x11.push.v1.PlatformR\bplatform\x12\x1b\n" +
+	"\tis_active\x18\x06 \x01(\bR\bisActive\x129\n" +
+	"\bmetadata\x18\a \x03(\v2\x1d.push.v1.Device.MetadataEntryR\bmetadata\x12\x12\n" +
+	"\x04tags\x18\b \x03(\tR\x04tags\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xa2\x02\n" +
+	"\x15RegisterDeviceRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x10\n" +
+	"\x03app\x18\x02 \x01(\tR\x03app\x12\x14\n" +
+	"\x05token\x18\x03 \x01(\tR\x05token\x12-\n" +
+	"\bplatform\x18\x04 \x01(\x0e2\x11.push.v1.PlatformR\bplatform\x12H\n" +
+	"\bmetadata\x18\x05 \x03(\v2,.push.v1.RegisterDeviceRequest.MetadataEntryR\bmetadata\x12\x12\n" +
+	"\x04tags\x18\x06 \x03(\tR\x04tags\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"A\n" +
+	"\x16RegisterDeviceResponse\x12'\n" +
+	"\x06device\x18\x01 \x01(\v2\x0f.push.v1.DeviceR\x06device\"A\n" +
+	"\x17UnregisterDeviceRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12\x10\n" +
+	"\x03app\x18\x02 \x01(\tR\x03app\"\x1a\n" +
+	"\x18UnregisterDeviceResponse\"1\n" +
+	"\x16ListUserDevicesRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"D\n" +
+	"\x17ListUserDevicesResponse\x12)\n" +
+	"\adevices\x18\x01 \x03(\v2\x0f.push.v1.DeviceR\adevices\"\xd0\x02\n" +
+	"\x0fSendPushRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x12\n" +
+	"\x04body\x18\x03 \x01(\tR\x04body\x12\x14\n" +
+	"\x05image\x18\x04 \x01(\tR\x05image\x12\x12\n" +
+	"\x04link\x18\x05 \x01(\tR\x04link\x126\n" +
+	"\x04data\x18\x06 \x03(\v2\".push.v1.SendPushRequest.DataEntryR\x04data\x12/\n" +
+	"\tplatforms\x18\a \x03(\x0e2\x11.push.v1.PlatformR\tplatforms\x12\x12\n" +
+	"\x04sync\x18\b \x01(\bR\x04sync\x12\x1a\n" +
+	"\bcategory\x18\t \x01(\tR\bcategory\x1a7\n" +
+	"\tDataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xe5\x01\n" +
+	"\x10SendPushResponse\x12'\n" +
+	"\x0fnotification_id\x18\x01 \x01(\tR\x0enotificationId\x12#\n" +
+	"\rsuccess_count\x18\x02 \x01(\x05R\fsuccessCount\x12#\n" +
+	"\rfailure_count\x18\x03 \x01(\x05R\ffailureCount\x123\n" +
+	"\aresults\x18\x04 \x03(\v2\x19.push.v1.DeviceSendResultR\aresults\x12)\n" +
+	"\x10suppressed_count\x18\x05 \x01(\x05R\x0fsuppressedCount\"\x82\x02\n" +
+	"\x10DeviceSendResult\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x12-\n" +
+	"\bplatform\x18\x02 \x01(\x0e2\x11.push.v1.PlatformR\bplatform\x12\x14\n" +
+	"\x05token\x18\x03 \x01(\tR\x05token\x12\x18\n" +
+	"\asuccess\x18\x04 \x01(\bR\asuccess\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x05 \x01(\tR\tmessageId\x12\x1d\n" +
+	"\n" +
+	"error_code\x18\x06 \x01(\tR\terrorCode\x12\x14\n" +
+	"\x05error\x18\a \x01(\tR\x05error\x12\x1e\n" +
+	"\n" +
+	"suppressed\x18\b \x01(\bR\n" +
+	"suppressed\"\xeb\x01\n" +
+	"\x13SendBulkPushRequest\x12\x19\n" +
+	"\buser_ids\x18\x01 \x03(\tR\auserIds\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x12\n" +
+	"\x04body\x18\x03 \x01(\tR\x04body\x12:\n" +
+	"\x04data\x18\x04 \x03(\v2&.push.v1.SendBulkPushRequest.DataEntryR\x04data\x12\x1a\n" +
+	"\bcategory\x18\x05 \x01(\tR\bcategory\x1a7\n" +
+	"\tDataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"5\n" +
+	"\x14SendBulkPushResponse\x12\x1d\n" +
+	"\n" +
+	"user_count\x18\x01 \x01(\x05R\tuserCount\"\x9a\x02\n" +
+	"\x16SendSegmentPushRequest\x12\x18\n" +
+	"\asegment\x18\x01 \x01(\tR\asegment\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x12\n" +
+	"\x04body\x18\x03 \x01(\tR\x04body\x12\x14\n" +
+	"\x05image\x18\x04 \x01(\tR\x05image\x12\x12\n" +
+	"\x04link\x18\x05 \x01(\tR\x04link\x12=\n" +
+	"\x04data\x18\x06 \x03(\v2).push.v1.SendSegmentPushRequest.DataEntryR\x04data\x12\x1a\n" +
+	"\bcategory\x18\a \x01(\tR\bcategory\x1a7\n" +
+	"\tDataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"}\n" +
+	"\x17SendSegmentPushResponse\x12\x18\n" +
+	"\asegment\x18\x01 \x01(\tR\asegment\x12!\n" +
+	"\fdevice_count\x18\x02 \x01(\x05R\vdeviceCount\x12%\n" +
+	"\x0eenqueued_count\x18\x03 \x01(\x05R\renqueuedCount\"G\n" +
+	"\x1cGetNotificationStatusRequest\x12'\n" +
+	"\x0fnotification_id\x18\x01 \x01(\tR\x0enotificationId\"I\n" +
+	"\x1eWatchNotificationStatusRequest\x12'\n" +
+	"\x0fnotification_id\x18\x01 \x01(\tR\x0enotificationId\"\x8c\x04\n" +
+	"\x12NotificationStatus\x12'\n" +
+	"\x0fnotification_id\x18\x01 \x01(\tR\x0enotificationId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x120\n" +
+	"\x05state\x18\x03 \x01(\x0e2\x1a.push.v1.NotificationStateR\x05state\x12!\n" +
+	"\fdevice_count\x18\x04 \x01(\x05R\vdeviceCount\x12#\n" +
+	"\rsuccess_count\x18\x05 \x01(\x05R\fsuccessCount\x12#\n" +
+	"\rfailure_count\x18\x06 \x01(\x05R\ffailureCount\x12#\n" +
+	"\rerror_message\x18\a \x01(\tR\ferrorMessage\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x123\n" +
+	"\asent_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\x06sentAt\x12\x1a\n" +
+	"\bcategory\x18\v \x01(\tR\bcategory\x12)\n" +
+	"\x10suppressed_count\x18\f \x01(\x05R\x0fsuppressedCount\"\x16\n" +
+	"\x14GetQueueStatsRequest\"\x96\x01\n" +
+	"\x15GetQueueStatsResponse\x12B\n" +
+	"\x06queues\x18\x01 \x03(\v2*.push.v1.GetQueueStatsResponse.QueuesEntryR\x06queues\x1a9\n" +
+	"\vQueuesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01*^\n" +
+	"\bPlatform\x12\x18\n" +
+	"\x14PLATFORM_UNSPECIFIED\x10\x00\x12\x10\n" +
+	"\fPLATFORM_IOS\x10\x01\x12\x14\n" +
+	"\x10PLATFORM_ANDROID\x10\x02\x12\x10\n" +
+	"\fPLATFORM_WEB\x10\x03*\x97\x02\n" +
+	"\x11NotificationState\x12\"\n" +
+	"\x1eNOTIFICATION_STATE_UNSPECIFIED\x10\x00\x12\x1d\n" +
+	"\x19NOTIFICATION_STATE_QUEUED\x10\x01\x12\x1e\n" +
+	"\x1aNOTIFICATION_STATE_SENDING\x10\x02\x12\x1f\n" +
+	"\x1bNOTIFICATION_STATE_RETRYING\x10\x03\x12\x1b\n" +
+	"\x17NOTIFICATION_STATE_SENT\x10\x04\x12\x1d\n" +
+	"\x19NOTIFICATION_STATE_FAILED\x10\x05\x12!\n" +
+	"\x1dNOTIFICATION_STATE_SUPPRESSED\x10\x06\x12\x1f\n" +
+	"\x1bNOTIFICATION_STATE_DEFERRED\x10\a2\x91\x02\n" +
+	"\rDeviceService\x12Q\n" +
+	"\x0eRegisterDevice\x12\x1e.push.v1.RegisterDeviceRequest\x1a\x1f.push.v1.RegisterDeviceResponse\x12W\n" +
+	"\x10UnregisterDevice\x12 .push.v1.UnregisterDeviceRequest\x1a!.push.v1.UnregisterDeviceResponse\x12T\n" +
+	"\x0fListUserDevices\x12\x1f.push.v1.ListUserDevicesRequest\x1a .push.v1.ListUserDevicesResponse2\x81\x04\n" +
+	"\vPushService\x12?\n" +
+	"\bSendPush\x12\x18.push.v1.SendPushRequest\x1a\x19.push.v1.SendPushResponse\x12K\n" +
+	"\fSendBulkPush\x12\x1c.push.v1.SendBulkPushRequest\x1a\x1d.push.v1.SendBulkPushResponse\x12T\n" +
+	"\x0fSendSegmentPush\x12\x1f.push.v1.SendSegmentPushRequest\x1a .push.v1.SendSegmentPushResponse\x12[\n" +
+	"\x15GetNotificationStatus\x12%.push.v1.GetNotificationStatusRequest\x1a\x1b.push.v1.NotificationStatus\x12a\n" +
+	"\x17WatchNotificationStatus\x12'.push.v1.WatchNotificationStatusRequest\x1a\x1b.push.v1.NotificationStatus0\x01\x12N\n" +
+	"\rGetQueueStats\x12\x1d.push.v1.GetQueueStatsRequest\x1a\x1e.push.v1.GetQueueStatsResponseB#Z!push-service/proto/push/v1;pushv1b\x06proto3"
+
+var (
+	file_proto_push_v1_push_proto_rawDescOnce sync.Once
+	file_proto_push_v1_push_proto_rawDescData []byte
+)
+
+func file_proto_push_v1_push_proto_rawDescGZIP() []byte {
+	file_proto_push_v1_push_proto_rawDescOnce.Do(func() {
+		file_proto_push_v1_push_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_push_v1_push_proto_rawDesc), len(file_proto_push_v1_push_proto_rawDesc)))
+	})
+	return file_proto_push_v1_push_proto_rawDescData
+}
+
+var file_proto_push_v1_push_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_proto_push_v1_push_proto_msgTypes = make([]protoimpl.MessageInfo, 25)
+var file_proto_push_v1_push_proto_goTypes = []any{
+	(Platform)(0),                          // 0: push.v1.Platform
+	(NotificationState)(0),                 // 1: push.v1.NotificationState
+	(*Device)(nil),                         // 2: push.v1.Device
+	(*RegisterDeviceRequest)(nil),          // 3: push.v1.RegisterDeviceRequest
+	(*RegisterDeviceResponse)(nil),         // 4: push.v1.RegisterDeviceResponse
+	(*UnregisterDeviceRequest)(nil),        // 5: push.v1.UnregisterDeviceRequest
+	(*UnregisterDeviceResponse)(nil),       // 6: push.v1.UnregisterDeviceResponse
+	(*ListUserDevicesRequest)(nil),         // 7: push.v1.ListUserDevicesRequest
+	(*ListUserDevicesResponse)(nil),        // 8: push.v1.ListUserDevicesResponse
+	(*SendPushRequest)(nil),                // 9: push.v1.SendPushRequest
+	(*SendPushResponse)(nil),               // 10: push.v1.SendPushResponse
+	(*DeviceSendResult)(nil),               // 11: push.v1.DeviceSendResult
+	(*SendBulkPushRequest)(nil),            // 12: push.v1.SendBulkPushRequest
+	(*SendBulkPushResponse)(nil),           // 13: push.v1.SendBulkPushResponse
+	(*SendSegmentPushRequest)(nil),         // 14: push.v1.SendSegmentPushRequest
+	(*SendSegmentPushResponse)(nil),        // 15: push.v1.SendSegmentPushResponse
+	(*GetNotificationStatusRequest)(nil),   // 16: push.v1.GetNotificationStatusRequest
+	(*WatchNotificationStatusRequest)(nil), // 17: push.v1.WatchNotificationStatusRequest
+	(*NotificationStatus)(nil),             // 18: push.v1.NotificationStatus
+	(*GetQueueStatsRequest)(nil),           // 19: push.v1.GetQueueStatsRequest
+	(*GetQueueStatsResponse)(nil),          // 20: push.v1.GetQueueStatsResponse
+	nil,                                    // 21: push.v1.Device.MetadataEntry
+	nil,                                    // 22: push.v1.RegisterDeviceRequest.MetadataEntry
+	nil,                                    // 23: push.v1.SendPushRequest.DataEntry
+	nil,                                    // 24: push.v1.SendBulkPushRequest.DataEntry
+	nil,                                    // 25: push.v1.SendSegmentPushRequest.DataEntry
+	nil,                                    // 26: push.v1.GetQueueStatsResponse.QueuesEntry
+	(*timestamppb.Timestamp)(nil),          // 27: google.protobuf.Timestamp
+}
+var file_proto_push_v1_push_proto_depIdxs = []int32{
+	0,  // 0: push.v1.Device.platform:type_name -> push.v1.Platform
+	21, // 1: push.v1.Device.metadata:type_name -> push.v1.Device.MetadataEntry
+	0,  // 2: push.v1.RegisterDeviceRequest.platform:type_name -> push.v1.Platform
+	22, // 3: push.v1.RegisterDeviceRequest.metadata:type_name -> push.v1.RegisterDeviceRequest.MetadataEntry
+	2,  // 4: push.v1.RegisterDeviceResponse.device:type_name -> push.v1.Device
+	2,  // 5: push.v1.ListUserDevicesResponse.devices:type_name -> push.v1.Device
+	23, // 6: push.v1.SendPushRequest.data:type_name -> push.v1.SendPushRequest.DataEntry
+	0,  // 7: push.v1.SendPushRequest.platforms:type_name -> push.v1.Platform
+	11, // 8: push.v1.SendPushResponse.results:type_name -> push.v1.DeviceSendResult
+	0,  // 9: push.v1.DeviceSendResult.platform:type_name -> push.v1.Platform
+	24, // 10: push.v1.SendBulkPushRequest.data:type_name -> push.v1.SendBulkPushRequest.DataEntry
+	25, // 11: push.v1.SendSegmentPushRequest.data:type_name -> push.v1.SendSegmentPushRequest.DataEntry
+	1,  // 12: push.v1.NotificationStatus.state:type_name -> push.v1.NotificationState
+	27, // 13: push.v1.NotificationStatus.created_at:type_name -> google.protobuf.Timestamp
+	27, // 14: push.v1.NotificationStatus.updated_at:type_name -> google.protobuf.Timestamp
+	27, // 15: push.v1.NotificationStatus.sent_at:type_name -> google.protobuf.Timestamp
+	26, // 16: push.v1.GetQueueStatsResponse.queues:type_name -> push.v1.GetQueueStatsResponse.QueuesEntry
+	3,  // 17: push.v1.DeviceService.RegisterDevice:input_type -> push.v1.RegisterDeviceRequest
+	5,  // 18: push.v1.DeviceService.UnregisterDevice:input_type -> push.v1.UnregisterDeviceRequest
+	7,  // 19: push.v1.DeviceService.ListUserDevices:input_type -> push.v1.ListUserDevicesRequest
+	9,  // 20: push.v1.PushService.SendPush:input_type -> push.v1.SendPushRequest
+	12, // 21: push.v1.PushService.SendBulkPush:input_type -> push.v1.SendBulkPushRequest
+	14, // 22: push.v1.PushService.SendSegmentPush:input_type -> push.v1.SendSegmentPushRequest
+	16, // 23: push.v1.PushService.GetNotificationStatus:input_type -> push.v1.GetNotificationStatusRequest
+	17, // 24: push.v1.PushService.WatchNotificationStatus:input_type -> push.v1.WatchNotificationStatusRequest
+	19, // 25: push.v1.PushService.GetQueueStats:input_type -> push.v1.GetQueueStatsRequest
+	4,  // 26: push.v1.DeviceService.RegisterDevice:output_type -> push.v1.RegisterDeviceResponse
+	6,  // 27: push.v1.DeviceService.UnregisterDevice:output_type -> push.v1.UnregisterDeviceResponse
+	8,  // 28: push.v1.DeviceService.ListUserDevices:output_type -> push.v1.ListUserDevicesResponse
+	10, // 29: push.v1.PushService.SendPush:output_type -> push.v1.SendPushResponse
+	13, // 30: push.v1.PushService.SendBulkPush:output_type -> push.v1.SendBulkPushResponse
+	15, // 31: push.v1.PushService.SendSegmentPush:output_type -> push.v1.SendSegmentPushResponse
+	18, // 32: push.v1.PushService.GetNotificationStatus:output_type -> push.v1.NotificationStatus
+	18, // 33: push.v1.PushService.WatchNotificationStatus:output_type -> push.v1.NotificationStatus
+	20, // 34: push.v1.PushService.GetQueueStats:output_type -> push.v1.GetQueueStatsResponse
+	26, // [26:35] is the sub-list for method output_type
+	17, // [17:26] is the sub-list for method input_type
+	17, // [17:17] is the sub-list for extension type_name
+	17, // [17:17] is the sub-list for extension extendee
+	0,  // [0:17] is the sub-list for field type_name
+}
+
+func init() { file_proto_push_v1_push_proto_init() }
+func file_proto_push_v1_push_proto_init() {
+	if File_proto_push_v1_push_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_push_v1_push_proto_rawDesc), len(file_proto_push_v1_push_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   25,
+			NumExtensions: 0,
+			NumServices:   2,
+		},
+		GoTypes:           file_proto_push_v1_push_proto_goTypes,
+		DependencyIndexes: file_proto_push_v1_push_proto_depIdxs,
+		EnumInfos:         file_proto_push_v1_push_proto_enumTypes,
+		MessageInfos:      file_proto_push_v1_push_proto_msgTypes,
+	}.Build()
+	File_proto_push_v1_push_proto = out.File
+	file_proto_push_v1_push_proto_goTypes = nil
+	file_proto_push_v1_push_proto_depIdxs = nil
+}
@@ -0,0 +1,535 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: proto/push/v1/push.proto
+
+package pushv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	DeviceService_RegisterDevice_FullMethodName   = "/push.v1.DeviceService/RegisterDevice"
+	DeviceService_UnregisterDevice_FullMethodName = "/push.v1.DeviceService/UnregisterDevice"
+	DeviceService_ListUserDevices_FullMethodName  = "/push.v1.DeviceService/ListUserDevices"
+)
+
+// DeviceServiceClient is the client API for DeviceService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// DeviceService manages the device tokens notifications are sent to.
+type DeviceServiceClient interface {
+	// RegisterDevice registers a device token, reactivating it and moving it to
+	// the given user if it is already known.
+	RegisterDevice(ctx context.Context, in *RegisterDeviceRequest, opts ...grpc.CallOption) (*RegisterDeviceResponse, error)
+	// UnregisterDevice deactivates a device token.
+	UnregisterDevice(ctx context.Context, in *UnregisterDeviceRequest, opts ...grpc.CallOption) (*UnregisterDeviceResponse, error)
+	// ListUserDevices lists the devices registered to a user.
+	ListUserDevices(ctx context.Context, in *ListUserDevicesRequest, opts ...grpc.CallOption) (*ListUserDevicesResponse, error)
+}
+
+type deviceServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDeviceServiceClient(cc grpc.ClientConnInterface) DeviceServiceClient {
+	return &deviceServiceClient{cc}
+}
+
+func (c *deviceServiceClient) RegisterDevice(ctx context.Context, in *RegisterDeviceRequest, opts ...grpc.CallOption) (*RegisterDeviceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RegisterDeviceResponse)
+	err := c.cc.Invoke(ctx, DeviceService_RegisterDevice_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deviceServiceClient) UnregisterDevice(ctx context.Context, in *UnregisterDeviceRequest, opts ...grpc.CallOption) (*UnregisterDeviceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UnregisterDeviceResponse)
+	err := c.cc.Invoke(ctx, DeviceService_UnregisterDevice_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deviceServiceClient) ListUserDevices(ctx context.Context, in *ListUserDevicesRequest, opts ...grpc.CallOption) (*ListUserDevicesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListUserDevicesResponse)
+	err := c.cc.Invoke(ctx, DeviceService_ListUserDevices_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DeviceServiceServer is the server API for DeviceService service.
+// All implementations must embed UnimplementedDeviceServiceServer
+// for forward compatibility.
+//
+// DeviceService manages the device tokens notifications are sent to.
+type DeviceServiceServer interface {
+	// RegisterDevice registers a device token, reactivating it and moving it to
+	// the given user if it is already known.
+	RegisterDevice(context.Context, *RegisterDeviceRequest) (*RegisterDeviceResponse, error)
+	// UnregisterDevice deactivates a device token.
+	UnregisterDevice(context.Context, *UnregisterDeviceRequest) (*UnregisterDeviceResponse, error)
+	// ListUserDevices lists the devices registered to a user.
+	ListUserDevices(context.Context, *ListUserDevicesRequest) (*ListUserDevicesResponse, error)
+	mustEmbedUnimplementedDeviceServiceServer()
+}
+
+// UnimplementedDeviceServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedDeviceServiceServer struct{}
+
+func (UnimplementedDeviceServiceServer) RegisterDevice(context.Context, *RegisterDeviceRequest) (*RegisterDeviceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterDevice not implemented")
+}
+func (UnimplementedDeviceServiceServer) UnregisterDevice(context.Context, *UnregisterDeviceRequest) (*UnregisterDeviceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnregisterDevice not implemented")
+}
+func (UnimplementedDeviceServiceServer) ListUserDevices(context.Context, *ListUserDevicesRequest) (*ListUserDevicesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUserDevices not implemented")
+}
+func (UnimplementedDeviceServiceServer) mustEmbedUnimplementedDeviceServiceServer() {}
+func (UnimplementedDeviceServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafeDeviceServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DeviceServiceServer will
+// result in compilation errors.
+type UnsafeDeviceServiceServer interface {
+	mustEmbedUnimplementedDeviceServiceServer()
+}
+
+func RegisterDeviceServiceServer(s grpc.ServiceRegistrar, srv DeviceServiceServer) {
+	// If the following call pancis, it indicates UnimplementedDeviceServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&DeviceService_ServiceDesc, srv)
+}
+
+func _DeviceService_RegisterDevice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterDeviceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeviceServiceServer).RegisterDevice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeviceService_RegisterDevice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeviceServiceServer).RegisterDevice(ctx, req.(*RegisterDeviceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeviceService_UnregisterDevice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnregisterDeviceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeviceServiceServer).UnregisterDevice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeviceService_UnregisterDevice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeviceServiceServer).UnregisterDevice(ctx, req.(*UnregisterDeviceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeviceService_ListUserDevices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUserDevicesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeviceServiceServer).ListUserDevices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeviceService_ListUserDevices_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeviceServiceServer).ListUserDevices(ctx, req.(*ListUserDevicesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// DeviceService_ServiceDesc is the grpc.ServiceDesc for DeviceService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DeviceService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "push.v1.DeviceService",
+	HandlerType: (*DeviceServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "RegisterDevice",
+			Handler:    _DeviceService_RegisterDevice_Handler,
+		},
+		{
+			MethodName: "UnregisterDevice",
+			Handler:    _DeviceService_UnregisterDevice_Handler,
+		},
+		{
+			MethodName: "ListUserDevices",
+			Handler:    _DeviceService_ListUserDevices_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/push/v1/push.proto",
+}
+
+const (
+	PushService_SendPush_FullMethodName                = "/push.v1.PushService/SendPush"
+	PushService_SendBulkPush_FullMethodName            = "/push.v1.PushService/SendBulkPush"
+	PushService_SendSegmentPush_FullMethodName         = "/push.v1.PushService/SendSegmentPush"
+	PushService_GetNotificationStatus_FullMethodName   = "/push.v1.PushService/GetNotificationStatus"
+	PushService_WatchNotificationStatus_FullMethodName = "/push.v1.PushService/WatchNotificationStatus"
+	PushService_GetQueueStats_FullMethodName           = "/push.v1.PushService/GetQueueStats"
+)
+
+// PushServiceClient is the client API for PushService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// PushService sends notifications and reports on their progress. Sends carry
+// one inline title and body for every device; stored templates, template
+// variables, locale variants and localization keys are only available over
+// HTTP.
+type PushServiceClient interface {
+	// SendPush sends a notification to a user's devices. It is queued unless
+	// sync is set, in which case the response carries per-device results.
+	SendPush(ctx context.Context, in *SendPushRequest, opts ...grpc.CallOption) (*SendPushResponse, error)
+	// SendBulkPush queues a notification for each of several users.
+	SendBulkPush(ctx context.Context, in *SendBulkPushRequest, opts ...grpc.CallOption) (*SendBulkPushResponse, error)
+	// SendSegmentPush queues a notification for every device matching a segment.
+	SendSegmentPush(ctx context.Context, in *SendSegmentPushRequest, opts ...grpc.CallOption) (*SendSegmentPushResponse, error)
+	// GetNotificationStatus returns the current status of a notification.
+	GetNotificationStatus(ctx context.Context, in *GetNotificationStatusRequest, opts ...grpc.CallOption) (*NotificationStatus, error)
+	// WatchNotificationStatus streams the status of a notification each time it
+	// changes, starting with the current status. The stream ends once the
+	// notification is sent, has failed or is suppressed. Deferred notifications
+	// are still to be sent, so their stream stays open.
+	WatchNotificationStatus(ctx context.Context, in *WatchNotificationStatusRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[NotificationStatus], error)
+	// GetQueueStats returns the number of messages in each push queue.
+	GetQueueStats(ctx context.Context, in *GetQueueStatsRequest, opts ...grpc.CallOption) (*GetQueueStatsResponse, error)
+}
+
+type pushServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPushServiceClient(cc grpc.ClientConnInterface) PushServiceClient {
+	return &pushServiceClient{cc}
+}
+
+func (c *pushServiceClient) SendPush(ctx context.Context, in *SendPushRequest, opts ...grpc.CallOption) (*SendPushResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SendPushResponse)
+	err := c.cc.Invoke(ctx, PushService_SendPush_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pushServiceClient) SendBulkPush(ctx context.Context, in *SendBulkPushRequest, opts ...grpc.CallOption) (*SendBulkPushResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SendBulkPushResponse)
+	err := c.cc.Invoke(ctx, PushService_SendBulkPush_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pushServiceClient) SendSegmentPush(ctx context.Context, in *SendSegmentPushRequest, opts ...grpc.CallOption) (*SendSegmentPushResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SendSegmentPushResponse)
+	err := c.cc.Invoke(ctx, PushService_SendSegmentPush_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pushServiceClient) GetNotificationStatus(ctx context.Context, in *GetNotificationStatusRequest, opts ...grpc.CallOption) (*NotificationStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NotificationStatus)
+	err := c.cc.Invoke(ctx, PushService_GetNotificationStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pushServiceClient) WatchNotificationStatus(ctx context.Context, in *WatchNotificationStatusRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[NotificationStatus], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PushService_ServiceDesc.Streams[0], PushService_WatchNotificationStatus_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchNotificationStatusRequest, NotificationStatus]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PushService_WatchNotificationStatusClient = grpc.ServerStreamingClient[NotificationStatus]
+
+func (c *pushServiceClient) GetQueueStats(ctx context.Context, in *GetQueueStatsRequest, opts ...grpc.CallOption) (*GetQueueStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetQueueStatsResponse)
+	err := c.cc.Invoke(ctx, PushService_GetQueueStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PushServiceServer is the server API for PushService service.
+// All implementations must embed UnimplementedPushServiceServer
+// for forward compatibility.
+//
+// PushService sends notifications and reports on their progress. Sends carry
+// one inline title and body for every device; stored templates, template
+// variables, locale variants and localization keys are only available over
+// HTTP.
+type PushServiceServer interface {
+	// SendPush sends a notification to a user's devices. It is queued unless
+	// sync is set, in which case the response carries per-device results.
+	SendPush(context.Context, *SendPushRequest) (*SendPushResponse, error)
+	// SendBulkPush queues a notification for each of several users.
+	SendBulkPush(context.Context, *SendBulkPushRequest) (*SendBulkPushResponse, error)
+	// SendSegmentPush queues a notification for every device matching a segment.
+	SendSegmentPush(context.Context, *SendSegmentPushRequest) (*SendSegmentPushResponse, error)
+	// GetNotificationStatus returns the current status of a notification.
+	GetNotificationStatus(context.Context, *GetNotificationStatusRequest) (*NotificationStatus, error)
+	// WatchNotificationStatus streams the status of a notification each time it
+	// changes, starting with the current status. The stream ends once the
+	// notification is sent, has failed or is suppressed. Deferred notifications
+	// are still to be sent, so their stream stays open.
+	WatchNotificationStatus(*WatchNotificationStatusRequest, grpc.ServerStreamingServer[NotificationStatus]) error
+	// GetQueueStats returns the number of messages in each push queue.
+	GetQueueStats(context.Context, *GetQueueStatsRequest) (*GetQueueStatsResponse, error)
+	mustEmbedUnimplementedPushServiceServer()
+}
+
+// UnimplementedPushServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedPushServiceServer struct{}
+
+func (UnimplementedPushServiceServer) SendPush(context.Context, *SendPushRequest) (*SendPushResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendPush not implemented")
+}
+func (UnimplementedPushServiceServer) SendBulkPush(context.Context, *SendBulkPushRequest) (*SendBulkPushResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendBulkPush not implemented")
+}
+func (UnimplementedPushServiceServer) SendSegmentPush(context.Context, *SendSegmentPushRequest) (*SendSegmentPushResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendSegmentPush not implemented")
+}
+func (UnimplementedPushServiceServer) GetNotificationStatus(context.Context, *GetNotificationStatusRequest) (*NotificationStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNotificationStatus not implemented")
+}
+func (UnimplementedPushServiceServer) WatchNotificationStatus(*WatchNotificationStatusRequest, grpc.ServerStreamingServer[NotificationStatus]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchNotificationStatus not implemented")
+}
+func (UnimplementedPushServiceServer) GetQueueStats(context.Context, *GetQueueStatsRequest) (*GetQueueStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetQueueStats not implemented")
+}
+func (UnimplementedPushServiceServer) mustEmbedUnimplementedPushServiceServer() {}
+func (UnimplementedPushServiceServer) testEmbeddedByValue()                     {}
+
+// UnsafePushServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PushServiceServer will
+// result in compilation errors.
+type UnsafePushServiceServer interface {
+	mustEmbedUnimplementedPushServiceServer()
+}
+
+func RegisterPushServiceServer(s grpc.ServiceRegistrar, srv PushServiceServer) {
+	// If the following call pancis, it indicates UnimplementedPushServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&PushService_ServiceDesc, srv)
+}
+
+func _PushService_SendPush_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendPushRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PushServiceServer).SendPush(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PushService_SendPush_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PushServiceServer).SendPush(ctx, req.(*SendPushRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PushService_SendBulkPush_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendBulkPushRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PushServiceServer).SendBulkPush(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PushService_SendBulkPush_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PushServiceServer).SendBulkPush(ctx, req.(*SendBulkPushRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PushService_SendSegmentPush_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendSegmentPushRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PushServiceServer).SendSegmentPush(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PushService_SendSegmentPush_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PushServiceServer).SendSegmentPush(ctx, req.(*SendSegmentPushRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PushService_GetNotificationStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNotificationStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PushServiceServer).GetNotificationStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PushService_GetNotificationStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PushServiceServer).GetNotificationStatus(ctx, req.(*GetNotificationStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PushService_WatchNotificationStatus_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchNotificationStatusRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PushServiceServer).WatchNotificationStatus(m, &grpc.GenericServerStream[WatchNotificationStatusRequest, NotificationStatus]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PushService_WatchNotificationStatusServer = grpc.ServerStreamingServer[NotificationStatus]
+
+func _PushService_GetQueueStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetQueueStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PushServiceServer).GetQueueStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PushService_GetQueueStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PushServiceServer).GetQueueStats(ctx, req.(*GetQueueStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PushService_ServiceDesc is the grpc.ServiceDesc for PushService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PushService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "push.v1.PushService",
+	HandlerType: (*PushServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SendPush",
+			Handler:    _PushService_SendPush_Handler,
+		},
+		{
+			MethodName: "SendBulkPush",
+			Handler:    _PushService_SendBulkPush_Handler,
+		},
+		{
+			MethodName: "SendSegmentPush",
+			Handler:    _PushService_SendSegmentPush_Handler,
+		},
+		{
+			MethodName: "GetNotificationStatus",
+			Handler:    _PushService_GetNotificationStatus_Handler,
+		},
+		{
+			MethodName: "GetQueueStats",
+			Handler:    _PushService_GetQueueStats_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchNotificationStatus",
+			Handler:       _PushService_WatchNotificationStatus_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/push/v1/push.proto",
+}